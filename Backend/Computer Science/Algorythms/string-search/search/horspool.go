@@ -0,0 +1,39 @@
+package search
+
+// IndexAllHorspool returns offsets of all occurrences of pattern in text in ascending order
+// with the Boyer-Moore-Horspool algorithm: it compares a window of text from its end
+// and then shifts the window so that its last byte is aligned with the same byte
+// in pattern, skipping up to len(pattern) bytes at once.
+// Asymptotic: O(len(text) / len(pattern)) typically, O(len(text) * len(pattern)) at worst.
+func IndexAllHorspool(text, pattern string) []int {
+	if pattern == "" {
+		return everyOffset(len(text))
+	}
+
+	last := len(pattern) - 1
+
+	// shift[c] is distance from the last occurrence of c in pattern[:last] to its end.
+	var shift [256]int
+	for c := range shift {
+		shift[c] = len(pattern)
+	}
+
+	for i := 0; i < last; i++ {
+		shift[pattern[i]] = last - i
+	}
+
+	var offsets []int
+
+	for i := 0; i+last < len(text); i += shift[text[i+last]] {
+		j := last
+		for j >= 0 && text[i+j] == pattern[j] {
+			j--
+		}
+
+		if j < 0 {
+			offsets = append(offsets, i)
+		}
+	}
+
+	return offsets
+}
@@ -0,0 +1,57 @@
+package search
+
+// PrefixFunction returns the prefix function of s: pi[i] is length of the longest proper prefix
+// of s[:i+1] which is also its suffix.
+// Asymptotic: O(len(s))
+func PrefixFunction(s string) []int {
+	pi := make([]int, len(s))
+
+	for i := 1; i < len(s); i++ {
+		// Try borders of s[:i] from the longest one, each of them may extend by s[i].
+		k := pi[i-1]
+		for k > 0 && s[i] != s[k] {
+			k = pi[k-1]
+		}
+
+		if s[i] == s[k] {
+			k++
+		}
+
+		pi[i] = k
+	}
+
+	return pi
+}
+
+// IndexAllKMP returns offsets of all occurrences of pattern in text in ascending order
+// with the Knuth-Morris-Pratt algorithm: after a mismatch it continues from the longest border
+// of the matched part, so it never reads a byte of text twice.
+// Asymptotic: O(len(text) + len(pattern))
+func IndexAllKMP(text, pattern string) []int {
+	if pattern == "" {
+		return everyOffset(len(text))
+	}
+
+	var (
+		pi      = PrefixFunction(pattern)
+		offsets []int
+		k       int // length of the matched prefix of pattern.
+	)
+
+	for i := 0; i < len(text); i++ {
+		for k > 0 && text[i] != pattern[k] {
+			k = pi[k-1]
+		}
+
+		if text[i] == pattern[k] {
+			k++
+		}
+
+		if k == len(pattern) {
+			offsets = append(offsets, i-k+1)
+			k = pi[k-1]
+		}
+	}
+
+	return offsets
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+)
+
+// base of the polynomial hash, computed modulo 2^64. It is the prime strings.Index uses.
+const base = 16777619
+
+// ErrLengthMismatch is wrapped by errors of IndexAllRabinKarpMulti given patterns of different
+// lengths, which share a single rolling hash of the text.
+var ErrLengthMismatch = errors.New("patterns differ in length")
+
+// Match is an occurrence of one of several patterns.
+type Match struct {
+	Pattern int // position of the pattern in the slice.
+	Offset  int
+}
+
+// hash returns the polynomial hash of s and base raised to len(s), which drops the first byte
+// of a window of that length.
+func hash(s string) (uint64, uint64) {
+	var h, pow uint64 = 0, 1
+
+	for i := 0; i < len(s); i++ {
+		h = h*base + uint64(s[i])
+		pow *= base
+	}
+
+	return h, pow
+}
+
+// IndexAllRabinKarp returns offsets of all occurrences of pattern in text in ascending order
+// with the Rabin-Karp algorithm.
+// Asymptotic: O(len(text) + len(pattern)) expected.
+func IndexAllRabinKarp(text, pattern string) []int {
+	if pattern == "" {
+		return everyOffset(len(text))
+	}
+
+	// A single pattern never mismatches in length.
+	matches, _ := IndexAllRabinKarpMulti(text, []string{pattern})
+
+	var offsets []int
+	for _, m := range matches {
+		offsets = append(offsets, m.Offset)
+	}
+
+	return offsets
+}
+
+// IndexAllRabinKarpMulti returns all occurrences of patterns of equal length in text
+// ordered by offsets, then by positions of patterns. It rolls the hash of a window along the text
+// and looks it up among hashes of patterns, comparing bytes only when hashes are equal,
+// so its time barely depends on count of patterns. It returns ErrLengthMismatch
+// if patterns differ in length.
+// Asymptotic: O(len(text) + total length of patterns) expected.
+func IndexAllRabinKarpMulti(text string, patterns []string) ([]Match, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	m := len(patterns[0])
+	byHash := make(map[uint64][]int, len(patterns))
+
+	var pow uint64
+
+	for i, p := range patterns {
+		if len(p) != m {
+			return nil, fmt.Errorf("%w: %q and %q", ErrLengthMismatch, patterns[0], p)
+		}
+
+		var h uint64
+
+		h, pow = hash(p)
+		byHash[h] = append(byHash[h], i)
+	}
+
+	if m > len(text) {
+		return nil, nil
+	}
+
+	var (
+		matches []Match
+		h, _    = hash(text[:m])
+	)
+
+	for i := 0; ; i++ {
+		// Patterns matching at the same offset are equal, so they share the hash and are listed in order.
+		for _, id := range byHash[h] {
+			if text[i:i+m] == patterns[id] {
+				matches = append(matches, Match{Pattern: id, Offset: i})
+			}
+		}
+
+		if i+m == len(text) {
+			break
+		}
+
+		h = h*base + uint64(text[i+m]) - pow*uint64(text[i])
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,23 @@
+// Package search implements classic algorithms finding every occurrence of a pattern in a text:
+// the Z-function, Knuth-Morris-Pratt, Rabin-Karp and Boyer-Moore-Horspool.
+// All of them compare bytes and report overlapping occurrences, so "aa" occurs in "aaa"
+// at 0 and 1. The empty pattern occurs at every offset from 0 to len(text) inclusive,
+// as strings.Index finds it at 0.
+package search
+
+// IndexAll returns offsets of all occurrences of pattern in text in ascending order.
+// It uses Boyer-Moore-Horspool, which is the fastest of the algorithms here on natural texts.
+// Asymptotic: O(len(text) / len(pattern)) typically, O(len(text) * len(pattern)) at worst.
+func IndexAll(text, pattern string) []int {
+	return IndexAllHorspool(text, pattern)
+}
+
+// everyOffset returns the occurrences of the empty pattern in a text of length n.
+func everyOffset(n int) []int {
+	offsets := make([]int, n+1)
+	for i := range offsets {
+		offsets[i] = i
+	}
+
+	return offsets
+}
@@ -0,0 +1,174 @@
+package search_test
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/string-search/search"
+)
+
+var algorithms = []struct {
+	name     string
+	indexAll func(text, pattern string) []int
+}{
+	{name: "Z", indexAll: search.IndexAllZ},
+	{name: "KMP", indexAll: search.IndexAllKMP},
+	{name: "RabinKarp", indexAll: search.IndexAllRabinKarp},
+	{name: "Horspool", indexAll: search.IndexAllHorspool},
+	{name: "IndexAll", indexAll: search.IndexAll},
+}
+
+// indexAll is the reference: strings.Index repeated after every found occurrence.
+func indexAll(text, pattern string) []int {
+	var offsets []int
+
+	for start := 0; start <= len(text); {
+		i := strings.Index(text[start:], pattern)
+		if i < 0 {
+			break
+		}
+
+		offsets = append(offsets, start+i)
+		start += i + 1
+	}
+
+	return offsets
+}
+
+func TestZArray(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s        string
+		expected []int
+	}{
+		{s: "aabcaabxaaaz", expected: []int{12, 1, 0, 0, 3, 1, 0, 0, 2, 2, 1, 0}},
+		{s: "abcababc", expected: []int{8, 0, 0, 2, 0, 3, 0, 0}},
+		{s: "aaaaa", expected: []int{5, 4, 3, 2, 1}},
+		{s: "", expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		if got := search.ZArray(tt.s); !slices.Equal(got, tt.expected) {
+			t.Errorf("ZArray(%q): expected %v, got %v", tt.s, tt.expected, got)
+		}
+	}
+}
+
+func TestPrefixFunction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s        string
+		expected []int
+	}{
+		{s: "aabaaab", expected: []int{0, 1, 0, 1, 2, 2, 3}},
+		{s: "abcabcd", expected: []int{0, 0, 0, 1, 2, 3, 0}},
+		{s: "aaaa", expected: []int{0, 1, 2, 3}},
+		{s: "", expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		if got := search.PrefixFunction(tt.s); !slices.Equal(got, tt.expected) {
+			t.Errorf("PrefixFunction(%q): expected %v, got %v", tt.s, tt.expected, got)
+		}
+	}
+}
+
+func TestIndexAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		pattern  string
+		expected []int
+	}{
+		{text: "abracadabra", pattern: "abra", expected: []int{0, 7}},
+		{text: "aaaa", pattern: "aa", expected: []int{0, 1, 2}},
+		{text: "abc", pattern: "", expected: []int{0, 1, 2, 3}},
+		{text: "", pattern: "", expected: []int{0}},
+		{text: "ab", pattern: "abc", expected: nil},
+		{text: "GCATCGCAGAGAGTATACAGTACG", pattern: "GCAGAGAG", expected: []int{5}},
+		{text: "мама мыла раму", pattern: "ма", expected: []int{0, 4}},
+	}
+
+	for _, alg := range algorithms {
+		for _, tt := range tests {
+			if got := alg.indexAll(tt.text, tt.pattern); !slices.Equal(got, tt.expected) {
+				t.Errorf("%s(%q, %q): expected %v, got %v", alg.name, tt.text, tt.pattern, tt.expected, got)
+			}
+		}
+	}
+}
+
+func TestRabinKarpMulti(t *testing.T) {
+	t.Parallel()
+
+	got, err := search.IndexAllRabinKarpMulti("the cat sat on the mat", []string{"at ", "the", "mat", "the"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []search.Match{
+		{Pattern: 1, Offset: 0}, {Pattern: 3, Offset: 0}, {Pattern: 0, Offset: 5}, {Pattern: 0, Offset: 9},
+		{Pattern: 1, Offset: 15}, {Pattern: 3, Offset: 15}, {Pattern: 2, Offset: 19},
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := search.IndexAllRabinKarpMulti("text", []string{"ab", "abc"}); !errors.Is(err, search.ErrLengthMismatch) {
+		t.Errorf("expected %v, got %v", search.ErrLengthMismatch, err)
+	}
+
+	if got, err := search.IndexAllRabinKarpMulti("text", nil); got != nil || err != nil {
+		t.Errorf("expected nothing for no patterns, got %v, %v", got, err)
+	}
+}
+
+func FuzzIndexAll(f *testing.F) {
+	f.Add("abracadabra", "abra")
+	f.Add("aaaaaaaa", "aaa")
+	f.Add("abababab", "abab")
+	f.Add("", "")
+	f.Add("short", "longer pattern")
+	f.Add("\xff\x00\xff\x00", "\x00\xff")
+
+	f.Fuzz(func(t *testing.T, text, pattern string) {
+		expected := indexAll(text, pattern)
+
+		for _, alg := range algorithms {
+			if got := alg.indexAll(text, pattern); !slices.Equal(got, expected) {
+				t.Fatalf("%s(%q, %q): expected %v, got %v", alg.name, text, pattern, expected, got)
+			}
+		}
+	})
+}
+
+func BenchmarkIndexAll(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	words := strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor")
+
+	var text strings.Builder
+	for text.Len() < 1<<20 {
+		text.WriteString(words[rnd.Intn(len(words))])
+		text.WriteByte(' ')
+	}
+
+	for _, pattern := range []string{"sit", "tempor lorem", "consectetur adipiscing elit sed"} {
+		for _, alg := range algorithms {
+			b.Run(alg.name+"/"+pattern, func(b *testing.B) {
+				b.SetBytes(int64(text.Len()))
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					alg.indexAll(text.String(), pattern)
+				}
+			})
+		}
+	}
+}
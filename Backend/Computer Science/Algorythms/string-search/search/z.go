@@ -0,0 +1,68 @@
+package search
+
+// ZArray returns the Z-function of s: z[i] is length of the longest common prefix of s and s[i:].
+// By convention z[0] is len(s).
+// Asymptotic: O(len(s))
+func ZArray(s string) []int {
+	z := make([]int, len(s))
+	if len(s) == 0 {
+		return z
+	}
+
+	z[0] = len(s)
+
+	// s[l:r] is the rightmost match of a prefix found so far.
+	for i, l, r := 1, 0, 0; i < len(s); i++ {
+		if i < r {
+			z[i] = min(r-i, z[i-l])
+		}
+
+		for i+z[i] < len(s) && s[z[i]] == s[i+z[i]] {
+			z[i]++
+		}
+
+		if i+z[i] > r {
+			l, r = i, i+z[i]
+		}
+	}
+
+	return z
+}
+
+// IndexAllZ returns offsets of all occurrences of pattern in text in ascending order.
+// It extends the Z-function of pattern over text instead of computing it for pattern + "#" + text,
+// so it needs no separator absent from both and only O(len(pattern)) memory.
+// Asymptotic: O(len(text) + len(pattern))
+func IndexAllZ(text, pattern string) []int {
+	if pattern == "" {
+		return everyOffset(len(text))
+	}
+
+	var (
+		z       = ZArray(pattern)
+		offsets []int
+	)
+
+	// text[l:r] is the rightmost match of a prefix of pattern found so far.
+	for i, l, r := 0, 0, 0; i+len(pattern) <= len(text); i++ {
+		k := 0
+		if i < r {
+			// text[i:r] equals pattern[i-l:r-l], which shares z[i-l] bytes with pattern.
+			k = min(r-i, z[i-l])
+		}
+
+		for k < len(pattern) && text[i+k] == pattern[k] {
+			k++
+		}
+
+		if i+k > r {
+			l, r = i, i+k
+		}
+
+		if k == len(pattern) {
+			offsets = append(offsets, i)
+		}
+	}
+
+	return offsets
+}
@@ -0,0 +1,183 @@
+package suffix
+
+// Automaton is the suffix automaton of a text: the minimal automaton accepting its suffixes.
+// Every state stands for substrings sharing the set of end offsets, so it has at most
+// 2n states and 3n transitions for a text of length n, and every substring is read
+// along a path from the initial state.
+// It is immutable, so it is safe for concurrent use.
+// Use NewAutomaton to create an automaton.
+type Automaton struct {
+	n      int     // length of the text.
+	length []int32 // of the longest substring of a state.
+	link   []int32 // the state of the longest suffix with other end offsets, -1 for the initial one.
+	count  []int32 // count of end offsets of a state, which is count of occurrences of its substrings.
+	first  []int32 // the first transition of a state, -1 if none.
+
+	// Transitions are kept in lists, as most states have one or two.
+	label []byte
+	to    []int32
+	next  []int32
+}
+
+// NewAutomaton builds the suffix automaton of text, which must be shorter than 1 GiB.
+// Asymptotic: O(len(text) σ) for σ distinct bytes of text.
+func NewAutomaton(text string) *Automaton {
+	a := &Automaton{n: len(text)}
+	a.addState(0, -1)
+
+	last := int32(0)
+
+	for i := 0; i < len(text); i++ {
+		last = a.extend(last, text[i])
+	}
+
+	// Pass counts of end offsets up along links from longer substrings to shorter ones.
+	byLength := make([][]int32, len(text)+1)
+	for v := range a.length {
+		byLength[a.length[v]] = append(byLength[a.length[v]], int32(v))
+	}
+
+	for l := len(text); l > 0; l-- {
+		for _, v := range byLength[l] {
+			a.count[a.link[v]] += a.count[v]
+		}
+	}
+
+	return a
+}
+
+func (a *Automaton) addState(length, link int32) int32 {
+	a.length = append(a.length, length)
+	a.link = append(a.link, link)
+	a.count = append(a.count, 0)
+	a.first = append(a.first, -1)
+
+	return int32(len(a.length) - 1)
+}
+
+// transition returns the state after v and c, -1 if none.
+func (a *Automaton) transition(v int32, c byte) int32 {
+	for e := a.first[v]; e >= 0; e = a.next[e] {
+		if a.label[e] == c {
+			return a.to[e]
+		}
+	}
+
+	return -1
+}
+
+// setTransition points the transition of v by c to state to, adding it if missing.
+func (a *Automaton) setTransition(v int32, c byte, to int32) {
+	for e := a.first[v]; e >= 0; e = a.next[e] {
+		if a.label[e] == c {
+			a.to[e] = to
+			return
+		}
+	}
+
+	a.label = append(a.label, c)
+	a.to = append(a.to, to)
+	a.next = append(a.next, a.first[v])
+	a.first[v] = int32(len(a.label) - 1)
+}
+
+// extend appends c to the text whose whole is the state last and returns the state of the new whole.
+func (a *Automaton) extend(last int32, c byte) int32 {
+	cur := a.addState(a.length[last]+1, 0)
+	a.count[cur] = 1
+
+	p := last
+	for p >= 0 && a.transition(p, c) < 0 {
+		a.setTransition(p, c, cur)
+		p = a.link[p]
+	}
+
+	if p < 0 {
+		return cur
+	}
+
+	q := a.transition(p, c)
+	if a.length[p]+1 == a.length[q] {
+		a.link[cur] = q
+		return cur
+	}
+
+	// q stands for longer substrings too: split off the ones ending where c is appended.
+	clone := a.addState(a.length[p]+1, a.link[q])
+	for e := a.first[q]; e >= 0; e = a.next[e] {
+		a.setTransition(clone, a.label[e], a.to[e])
+	}
+
+	for ; p >= 0 && a.transition(p, c) == q; p = a.link[p] {
+		a.setTransition(p, c, clone)
+	}
+
+	a.link[q], a.link[cur] = clone, clone
+
+	return cur
+}
+
+// state returns the state reached by pattern, -1 if it is not a substring.
+func (a *Automaton) state(pattern string) int32 {
+	v := int32(0)
+
+	for i := 0; i < len(pattern) && v >= 0; i++ {
+		v = a.transition(v, pattern[i])
+	}
+
+	return v
+}
+
+// Contains reports whether pattern is a substring of the text.
+// Asymptotic: O(len(pattern) σ)
+func (a *Automaton) Contains(pattern string) bool {
+	return a.state(pattern) >= 0
+}
+
+// Count returns count of possibly overlapping occurrences of pattern in the text.
+// The empty pattern occurs at every offset from 0 to the length of the text inclusive.
+// Asymptotic: O(len(pattern) σ)
+func (a *Automaton) Count(pattern string) int {
+	if pattern == "" {
+		return a.n + 1
+	}
+
+	v := a.state(pattern)
+	if v < 0 {
+		return 0
+	}
+
+	return int(a.count[v])
+}
+
+// LongestCommonSubstring returns the longest substring of s which the text contains too,
+// the leftmost in s if there are several. It reads s keeping the longest suffix of the read part
+// contained in the text, which shrinks along links on a mismatch.
+// Asymptotic: O(len(s) σ)
+func (a *Automaton) LongestCommonSubstring(s string) string {
+	var (
+		v             int32
+		length        int
+		bestEnd, best int
+	)
+
+	for i := 0; i < len(s); i++ {
+		for v > 0 && a.transition(v, s[i]) < 0 {
+			v = a.link[v]
+			length = int(a.length[v])
+		}
+
+		if to := a.transition(v, s[i]); to >= 0 {
+			v = to
+			length++
+		} else {
+			length = 0
+		}
+
+		if length > best {
+			best, bestEnd = length, i+1
+		}
+	}
+
+	return s[bestEnd-best : bestEnd]
+}
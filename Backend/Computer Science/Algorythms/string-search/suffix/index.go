@@ -0,0 +1,137 @@
+package suffix
+
+import (
+	"slices"
+	"sort"
+)
+
+// Index answers substring queries over a static text with its suffix and LCP arrays.
+// It is immutable, so it is safe for concurrent use.
+// Use New to create an index.
+type Index struct {
+	text string
+	sa   []int32
+	lcp  []int32
+}
+
+// New builds the index of text, which must be shorter than 2 GiB.
+// It takes 8 bytes of memory per byte of text besides the text itself.
+// Asymptotic: O(len(text))
+func New(text string) *Index {
+	sa := SuffixArray(text)
+
+	return &Index{text: text, sa: sa, lcp: LCP(text, sa)}
+}
+
+// bounds returns the range of the suffix array holding suffixes which start with pattern.
+func (x *Index) bounds(pattern string) (int, int) {
+	prefix := func(i int) string {
+		start := int(x.sa[i])
+		return x.text[start:min(len(x.text), start+len(pattern))]
+	}
+
+	lo := sort.Search(len(x.sa), func(i int) bool { return prefix(i) >= pattern })
+	hi := lo + sort.Search(len(x.sa)-lo, func(i int) bool { return prefix(lo+i) > pattern })
+
+	return lo, hi
+}
+
+// Count returns count of possibly overlapping occurrences of pattern in the text.
+// The empty pattern occurs at every offset from 0 to the length of the text inclusive.
+// Asymptotic: O(len(pattern) log len(text))
+func (x *Index) Count(pattern string) int {
+	if pattern == "" {
+		return len(x.text) + 1
+	}
+
+	lo, hi := x.bounds(pattern)
+
+	return hi - lo
+}
+
+// Locate returns offsets of possibly overlapping occurrences of pattern in the text in ascending order.
+// Asymptotic: O(len(pattern) log len(text) + k log k) for k occurrences.
+func (x *Index) Locate(pattern string) []int {
+	if pattern == "" {
+		offsets := make([]int, len(x.text)+1)
+		for i := range offsets {
+			offsets[i] = i
+		}
+
+		return offsets
+	}
+
+	lo, hi := x.bounds(pattern)
+	if lo == hi {
+		return nil
+	}
+
+	offsets := make([]int, 0, hi-lo)
+	for _, start := range x.sa[lo:hi] {
+		offsets = append(offsets, int(start))
+	}
+
+	slices.Sort(offsets)
+
+	return offsets
+}
+
+// LongestRepeatedSubstring returns the longest substring occurring in the text twice at least,
+// possibly overlapping, the least of them in lexicographic order if there are several.
+// Asymptotic: O(len(text))
+func (x *Index) LongestRepeatedSubstring() string {
+	if len(x.lcp) == 0 {
+		return ""
+	}
+
+	best := 0
+	for i, h := range x.lcp {
+		if h > x.lcp[best] {
+			best = i
+		}
+	}
+
+	start := int(x.sa[best])
+
+	return x.text[start : start+int(x.lcp[best])]
+}
+
+// LongestCommonSubstring returns the longest string occurring both in a and b, the least of them
+// in lexicographic order if there are several. It sorts suffixes of a and b joined with a separator
+// less than any byte: the longest common prefix of neighbour suffixes from different strings
+// is the answer.
+// Asymptotic: O(len(a) + len(b))
+func LongestCommonSubstring(a, b string) string {
+	joined := make([]int32, 0, len(a)+len(b)+1)
+
+	for i := 0; i < len(a); i++ {
+		joined = append(joined, int32(a[i])+1)
+	}
+
+	joined = append(joined, 0)
+
+	for i := 0; i < len(b); i++ {
+		joined = append(joined, int32(b[i])+1)
+	}
+
+	sa := sais(joined, 256)
+	// The separator is unique, so no common prefix spans it.
+	lcp := kasai(sa, func(i, j int) bool { return joined[i] == joined[j] })
+
+	var bestStart, bestLen int
+
+	for i := 1; i < len(sa); i++ {
+		prev, cur := int(sa[i-1]), int(sa[i])
+		if (prev < len(a)) == (cur < len(a)) || int(lcp[i]) <= bestLen {
+			continue
+		}
+
+		bestStart, bestLen = min(prev, cur), int(lcp[i])
+	}
+
+	if bestLen == 0 {
+		return ""
+	}
+
+	return a[bestStart : bestStart+bestLen]
+}
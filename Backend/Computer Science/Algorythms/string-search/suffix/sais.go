@@ -0,0 +1,224 @@
+// Package suffix implements indexes of all substrings of a static text:
+// the suffix array with the LCP array, built in linear time with SA-IS and Kasai's algorithm,
+// and the suffix automaton. Both answer substring queries in time depending
+// on length of the pattern rather than of the text.
+package suffix
+
+import "slices"
+
+// naiveThreshold is length below which sorting suffixes directly is faster than SA-IS.
+const naiveThreshold = 10
+
+// SuffixArray returns start offsets of suffixes of text in lexicographic order.
+// Offsets are int32 to halve memory, so text must be shorter than 2 GiB.
+// Asymptotic: O(len(text))
+func SuffixArray(text string) []int32 {
+	s := make([]int32, len(text))
+	for i := 0; i < len(text); i++ {
+		s[i] = int32(text[i])
+	}
+
+	return sais(s, 255)
+}
+
+// naive sorts suffixes of s by comparing them.
+func naive(s []int32) []int32 {
+	sa := make([]int32, len(s))
+	for i := range sa {
+		sa[i] = int32(i)
+	}
+
+	slices.SortFunc(sa, func(a, b int32) int {
+		return slices.Compare(s[a:], s[b:])
+	})
+
+	return sa
+}
+
+// sais builds the suffix array of s with values from 0 to upper by induced sorting:
+// suffixes are classified as S if they are less than the next one and L otherwise,
+// the leftmost S suffixes after L ones (LMS) are sorted recursively as a shorter string
+// of their substrings, and then induce the order of all the others in two scans.
+func sais(s []int32, upper int) []int32 {
+	n := len(s)
+	if n < naiveThreshold {
+		return naive(s)
+	}
+
+	// ls[i] tells whether the suffix at i is of S type.
+	ls := make([]bool, n)
+	for i := n - 2; i >= 0; i-- {
+		if s[i] == s[i+1] {
+			ls[i] = ls[i+1]
+		} else {
+			ls[i] = s[i] < s[i+1]
+		}
+	}
+
+	// Buckets: L suffixes starting with c go from sumL[c], S ones from sumS[c].
+	sumL, sumS := make([]int32, upper+2), make([]int32, upper+2)
+
+	for i := 0; i < n; i++ {
+		if ls[i] {
+			sumL[s[i]+1]++
+		} else {
+			sumS[s[i]]++
+		}
+	}
+
+	for c := 0; c <= upper; c++ {
+		sumS[c] += sumL[c]
+		sumL[c+1] += sumS[c]
+	}
+
+	sa := make([]int32, n)
+	buf := make([]int32, upper+2)
+
+	induce := func(lms []int32) {
+		for i := range sa {
+			sa[i] = -1
+		}
+
+		copy(buf, sumS)
+
+		for _, d := range lms {
+			sa[buf[s[d]]] = d
+			buf[s[d]]++
+		}
+
+		copy(buf, sumL)
+		sa[buf[s[n-1]]] = int32(n - 1)
+		buf[s[n-1]]++
+
+		for i := 0; i < n; i++ {
+			if v := sa[i]; v >= 1 && !ls[v-1] {
+				sa[buf[s[v-1]]] = v - 1
+				buf[s[v-1]]++
+			}
+		}
+
+		copy(buf, sumL)
+
+		for i := n - 1; i >= 0; i-- {
+			if v := sa[i]; v >= 1 && ls[v-1] {
+				buf[s[v-1]+1]--
+				sa[buf[s[v-1]+1]] = v - 1
+			}
+		}
+	}
+
+	// lmsIndex numbers LMS positions from left to right, -1 for the others.
+	lmsIndex := make([]int32, n+1)
+	for i := range lmsIndex {
+		lmsIndex[i] = -1
+	}
+
+	var lms []int32
+
+	for i := 1; i < n; i++ {
+		if !ls[i-1] && ls[i] {
+			lmsIndex[i] = int32(len(lms))
+			lms = append(lms, int32(i))
+		}
+	}
+
+	m := len(lms)
+
+	induce(lms)
+
+	if m == 0 {
+		return sa
+	}
+
+	sorted := make([]int32, 0, m)
+
+	for _, v := range sa {
+		if lmsIndex[v] >= 0 {
+			sorted = append(sorted, v)
+		}
+	}
+
+	// Name LMS substrings by their order, equal ones get equal names.
+	end := func(v int32) int32 {
+		if next := lmsIndex[v] + 1; int(next) < m {
+			return lms[next]
+		}
+
+		return int32(n)
+	}
+
+	rec := make([]int32, m)
+	name := 0
+
+	for i := 1; i < m; i++ {
+		l, r := sorted[i-1], sorted[i]
+		endL, endR := end(l), end(r)
+
+		same := endL-l == endR-r
+		if same {
+			for l < endL && s[l] == s[r] {
+				l++
+				r++
+			}
+
+			same = int(l) < n && int(r) < n && s[l] == s[r]
+		}
+
+		if !same {
+			name++
+		}
+
+		rec[lmsIndex[sorted[i]]] = int32(name)
+	}
+
+	recSA := sais(rec, name)
+	for i, v := range recSA {
+		sorted[i] = lms[v]
+	}
+
+	induce(sorted)
+
+	return sa
+}
+
+// LCP returns the longest common prefix array of text for its suffix array sa:
+// lcp[i] is length of the longest common prefix of suffixes at sa[i-1] and sa[i], lcp[0] is 0.
+// It uses Kasai's algorithm: going from a suffix to the next shorter one
+// decreases their common prefix with predecessors by one at most.
+// Asymptotic: O(len(text))
+func LCP(text string, sa []int32) []int32 {
+	return kasai(sa, func(i, j int) bool { return text[i] == text[j] })
+}
+
+// kasai computes the LCP array of a string of length len(sa), which has equal symbols at i and j
+// if equal reports so.
+func kasai(sa []int32, equal func(i, j int) bool) []int32 {
+	n := len(sa)
+	rank := make([]int32, n)
+
+	for i, v := range sa {
+		rank[v] = int32(i)
+	}
+
+	lcp := make([]int32, n)
+
+	for i, h := 0, 0; i < n; i++ {
+		if rank[i] == 0 {
+			h = 0
+			continue
+		}
+
+		j := int(sa[rank[i]-1])
+		for i+h < n && j+h < n && equal(i+h, j+h) {
+			h++
+		}
+
+		lcp[rank[i]] = int32(h)
+
+		if h > 0 {
+			h--
+		}
+	}
+
+	return lcp
+}
@@ -0,0 +1,253 @@
+package suffix_test
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/string-search/suffix"
+)
+
+// naiveSuffixArray sorts suffixes by comparing them.
+func naiveSuffixArray(text string) []int32 {
+	sa := make([]int32, len(text))
+	for i := range sa {
+		sa[i] = int32(i)
+	}
+
+	slices.SortFunc(sa, func(a, b int32) int { return strings.Compare(text[a:], text[b:]) })
+
+	return sa
+}
+
+func commonPrefixLen(a, b string) int32 {
+	var i int32
+	for int(i) < len(a) && int(i) < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+// naiveLocate returns offsets of occurrences with strings.HasPrefix at every offset.
+func naiveLocate(text, pattern string) []int {
+	var offsets []int
+
+	for i := 0; i <= len(text); i++ {
+		if strings.HasPrefix(text[i:], pattern) {
+			offsets = append(offsets, i)
+		}
+	}
+
+	return offsets
+}
+
+// naiveLongestCommon returns length of the longest common substring of a and b.
+func naiveLongestCommon(a, b string) int {
+	best := 0
+
+	for i := range a {
+		for j := range b {
+			best = max(best, int(commonPrefixLen(a[i:], b[j:])))
+		}
+	}
+
+	return best
+}
+
+func randomText(rnd *rand.Rand, alphabet string, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+
+	return string(b)
+}
+
+func TestSuffixArray(t *testing.T) {
+	t.Parallel()
+
+	sa := suffix.SuffixArray("banana")
+	if expected := []int32{5, 3, 1, 0, 4, 2}; !slices.Equal(sa, expected) {
+		t.Errorf("expected %v, got %v", expected, sa)
+	}
+
+	if got, expected := suffix.LCP("banana", sa), []int32{0, 1, 3, 0, 0, 2}; !slices.Equal(got, expected) {
+		t.Errorf("expected LCP %v, got %v", expected, got)
+	}
+
+	rnd := rand.New(rand.NewSource(25))
+
+	// Small alphabets give many equal LMS substrings, so SA-IS recurses deeper.
+	for _, alphabet := range []string{"a", "ab", "abc", "acgt", "\x00\xff"} {
+		for range 200 {
+			text := randomText(rnd, alphabet, rnd.Intn(300))
+			sa := suffix.SuffixArray(text)
+
+			if expected := naiveSuffixArray(text); !slices.Equal(sa, expected) {
+				t.Fatalf("%q: expected %v, got %v", text, expected, sa)
+			}
+
+			lcp := suffix.LCP(text, sa)
+			for i := 1; i < len(sa); i++ {
+				if expected := commonPrefixLen(text[sa[i-1]:], text[sa[i]:]); lcp[i] != expected {
+					t.Fatalf("%q: lcp[%d] = %d, expected %d", text, i, lcp[i], expected)
+				}
+			}
+		}
+	}
+}
+
+func TestCountAndLocate(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(26))
+
+	for range 100 {
+		text := randomText(rnd, "abc", rnd.Intn(200))
+		index, automaton := suffix.New(text), suffix.NewAutomaton(text)
+
+		for range 20 {
+			pattern := randomText(rnd, "abcd", rnd.Intn(5))
+			expected := naiveLocate(text, pattern)
+
+			if got := index.Locate(pattern); !slices.Equal(got, expected) {
+				t.Fatalf("Locate(%q) in %q: expected %v, got %v", pattern, text, expected, got)
+			}
+
+			if got := index.Count(pattern); got != len(expected) {
+				t.Fatalf("Count(%q) in %q: expected %d, got %d", pattern, text, len(expected), got)
+			}
+
+			if got := automaton.Count(pattern); got != len(expected) {
+				t.Fatalf("automaton Count(%q) in %q: expected %d, got %d", pattern, text, len(expected), got)
+			}
+
+			if got := automaton.Contains(pattern); got != (len(expected) > 0) {
+				t.Fatalf("Contains(%q) in %q = %v", pattern, text, got)
+			}
+		}
+	}
+}
+
+func TestLongestRepeatedSubstring(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "banana", expected: "ana"},
+		{text: "abcd", expected: ""},
+		{text: "", expected: ""},
+		{text: "aaaa", expected: "aaa"},
+		{text: "abcXabcYbcdZbcd", expected: "abc"},
+		{text: "to be or not to be", expected: "to be"},
+	}
+
+	for _, tt := range tests {
+		if got := suffix.New(tt.text).LongestRepeatedSubstring(); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.text, tt.expected, got)
+		}
+	}
+}
+
+func TestLongestCommonSubstring(t *testing.T) {
+	t.Parallel()
+
+	if got := suffix.LongestCommonSubstring("xabxac", "abcabxabcd"); got != "abxa" {
+		t.Errorf("expected abxa, got %q", got)
+	}
+
+	if got := suffix.LongestCommonSubstring("abc", "xyz"); got != "" {
+		t.Errorf("expected nothing common, got %q", got)
+	}
+
+	rnd := rand.New(rand.NewSource(27))
+
+	for range 500 {
+		a, b := randomText(rnd, "ab\x00", rnd.Intn(40)), randomText(rnd, "ab\x00", rnd.Intn(40))
+		expected := naiveLongestCommon(a, b)
+
+		for name, got := range map[string]string{
+			"suffix array": suffix.LongestCommonSubstring(a, b),
+			"automaton":    suffix.NewAutomaton(a).LongestCommonSubstring(b),
+		} {
+			if len(got) != expected || !strings.Contains(a, got) || !strings.Contains(b, got) {
+				t.Fatalf("%s: %q and %q: expected length %d, got %q", name, a, b, expected, got)
+			}
+		}
+	}
+}
+
+// corpus returns a text of n bytes resembling a source code base: repetitive, with a small alphabet.
+func corpus(n int) string {
+	rnd := rand.New(rand.NewSource(1))
+	words := strings.Fields("func return if err != nil { } for range := int string byte the of and to in is")
+
+	var b strings.Builder
+	for b.Len() < n {
+		b.WriteString(words[rnd.Intn(len(words))])
+		b.WriteByte(" \n\t"[rnd.Intn(3)])
+	}
+
+	return b.String()[:n]
+}
+
+func BenchmarkSuffixArray(b *testing.B) {
+	for _, size := range []int{1 << 20, 8 << 20} {
+		text := corpus(size)
+
+		b.Run(fmt.Sprintf("%dMiB", size>>20), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				suffix.SuffixArray(text)
+			}
+		})
+	}
+}
+
+func BenchmarkIndex(b *testing.B) {
+	text := corpus(8 << 20)
+
+	b.Run("New", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+
+		for i := 0; i < b.N; i++ {
+			suffix.New(text)
+		}
+	})
+
+	b.Run("NewAutomaton", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+
+		for i := 0; i < b.N; i++ {
+			suffix.NewAutomaton(text)
+		}
+	})
+
+	index, automaton := suffix.New(text), suffix.NewAutomaton(text)
+	pattern := "if err != nil { return"
+
+	b.Run("Count", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			index.Count(pattern)
+		}
+	})
+
+	b.Run("automaton Count", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			automaton.Count(pattern)
+		}
+	})
+
+	b.Run("strings.Count", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			strings.Count(text, pattern)
+		}
+	})
+}
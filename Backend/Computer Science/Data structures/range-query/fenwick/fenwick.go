@@ -0,0 +1,173 @@
+// Package fenwick implements a Fenwick (binary indexed) tree: an array where the element i
+// holds the sum of the values of the range (i - lowbit(i), i], so any prefix sum
+// and any point update touch O(log n) elements, with no memory beyond the values themselves.
+package fenwick
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrOutOfRange is wrapped by errors of operations given an index or a range
+// outside of the array, or a range with its bounds swapped.
+var ErrOutOfRange = errors.New("index out of range")
+
+type (
+	// Number is a type supporting arithmetic operators.
+	Number interface {
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+			~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+			~float32 | ~float64
+	}
+
+	// Tree maintains prefix sums of an array of fixed length.
+	// Use New or FromSlice to create a tree.
+	Tree[T Number] struct {
+		mu   sync.RWMutex
+		sums []T // 1-based partial sums, sums[0] is unused.
+	}
+)
+
+// New creates a tree over n zero values, panicking if n is negative.
+func New[T Number](n int) *Tree[T] {
+	if n < 0 {
+		panic(fmt.Sprintf("fenwick: negative length %d", n))
+	}
+
+	return &Tree[T]{sums: make([]T, n+1)}
+}
+
+// FromSlice creates a tree over a copy of values.
+// Asymptotic: O(n)
+func FromSlice[T Number](values []T) *Tree[T] {
+	t := New[T](len(values))
+	copy(t.sums[1:], values)
+
+	// Every partial sum is complete once all smaller indexes are visited, so push it to its parent.
+	for i := 1; i < len(t.sums); i++ {
+		if parent := i + i&-i; parent < len(t.sums) {
+			t.sums[parent] += t.sums[i]
+		}
+	}
+
+	return t
+}
+
+// Len returns length of the array.
+// Asymptotic: O(1)
+func (t *Tree[T]) Len() int {
+	return len(t.sums) - 1
+}
+
+// add adds delta to the value at the 1-based position i.
+func (t *Tree[T]) add(i int, delta T) {
+	for ; i < len(t.sums); i += i & -i {
+		t.sums[i] += delta
+	}
+}
+
+// prefix returns the sum of the first n values.
+func (t *Tree[T]) prefix(n int) T {
+	var sum T
+
+	for ; n > 0; n -= n & -n {
+		sum += t.sums[n]
+	}
+
+	return sum
+}
+
+// Add adds delta to the value at i.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Add(i int, delta T) error {
+	if i < 0 || i >= t.Len() {
+		return fmt.Errorf("%w: %d with length %d", ErrOutOfRange, i, t.Len())
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.add(i+1, delta)
+
+	return nil
+}
+
+// Set replaces the value at i.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Set(i int, val T) error {
+	if i < 0 || i >= t.Len() {
+		return fmt.Errorf("%w: %d with length %d", ErrOutOfRange, i, t.Len())
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.add(i+1, val-(t.prefix(i+1)-t.prefix(i)))
+
+	return nil
+}
+
+// PrefixSum returns the sum of the first n values.
+// Asymptotic: O(log n)
+func (t *Tree[T]) PrefixSum(n int) (T, error) {
+	if n < 0 || n > t.Len() {
+		var zero T
+
+		return zero, fmt.Errorf("%w: prefix of %d with length %d", ErrOutOfRange, n, t.Len())
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.prefix(n), nil
+}
+
+// RangeSum returns the sum of values of the half-open range [lo, hi).
+// Asymptotic: O(log n)
+func (t *Tree[T]) RangeSum(lo, hi int) (T, error) {
+	if lo < 0 || hi > t.Len() || lo > hi {
+		var zero T
+
+		return zero, fmt.Errorf("%w: [%d, %d) with length %d", ErrOutOfRange, lo, hi, t.Len())
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.prefix(hi) - t.prefix(lo), nil
+}
+
+// LowerBound returns the least n such that the sum of the first n values is at least target,
+// e.g. the bucket holding the target rank of a histogram, and reports whether there is one.
+// Values must not be negative, so prefix sums do not decrease.
+// Asymptotic: O(log n)
+func (t *Tree[T]) LowerBound(target T) (int, bool) {
+	var zero T
+	if target <= zero {
+		return 0, true
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	// Descend by powers of two: pos stays the longest prefix with the sum less than target.
+	step := 1
+	for step*2 < len(t.sums) {
+		step *= 2
+	}
+
+	pos := 0
+	for ; step > 0; step /= 2 {
+		if next := pos + step; next < len(t.sums) && t.sums[next] < target {
+			pos = next
+			target -= t.sums[next]
+		}
+	}
+
+	if pos == t.Len() {
+		return 0, false
+	}
+
+	return pos + 1, true
+}
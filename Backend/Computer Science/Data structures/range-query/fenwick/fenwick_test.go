@@ -0,0 +1,141 @@
+package fenwick_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"testing/quick"
+
+	"github.com/dzianismaroz/marathon/range-query/fenwick"
+	"github.com/dzianismaroz/marathon/range-query/internal/rangetest"
+)
+
+func naiveSum(values []int) int {
+	var sum int
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum
+}
+
+func TestAgainstNaive(t *testing.T) {
+	t.Parallel()
+
+	rangetest.Check(t, func(values []int) rangetest.Model {
+		sut := fenwick.FromSlice(values)
+
+		return rangetest.Model{
+			Query: func(lo, hi int) (int, error) {
+				sum, err := sut.RangeSum(lo, hi)
+				if err != nil {
+					return 0, err
+				}
+
+				// Prefix sums of both ends must agree with the range sum.
+				prefixLo, _ := sut.PrefixSum(lo)
+				if prefixHi, err := sut.PrefixSum(hi); err != nil || prefixHi-prefixLo != sum {
+					return 0, fmt.Errorf("PrefixSum(%d) - PrefixSum(%d) = %d, %v, RangeSum = %d", hi, lo, prefixHi-prefixLo, err, sum)
+				}
+
+				return sum, nil
+			},
+			Aggregate: naiveSum,
+			Set:       sut.Set,
+			Update:    func(lo, _ int, val int8) error { return sut.Add(lo, int(val)) },
+			Apply:     func(val int8, v int) int { return v + int(val) },
+			Point:     true,
+		}
+	}, 500)
+}
+
+func TestLowerBound(t *testing.T) {
+	t.Parallel()
+
+	property := func(counts []uint8, target uint16) bool {
+		values := make([]int, len(counts))
+		for i, c := range counts {
+			values[i] = int(c)
+		}
+
+		got, ok := fenwick.FromSlice(values).LowerBound(int(target))
+
+		// The naive answer is the first prefix reaching target.
+		expected, found, sum := 0, int(target) <= 0, 0
+		for i := 0; !found && i < len(values); i++ {
+			sum += values[i]
+			if sum >= int(target) {
+				expected, found = i+1, true
+			}
+		}
+
+		if got != expected || ok != found {
+			t.Logf("LowerBound(%d) over %v = %d, %v, expected %d, %v", target, values, got, ok, expected, found)
+			return false
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	// Count of players per score bucket: the player ranked 5th from the bottom is in bucket 2.
+	sut := fenwick.New[int](10)
+	for _, score := range []int{0, 1, 1, 2, 2, 2, 7, 9} {
+		if err := sut.Add(score, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if bucket, ok := sut.LowerBound(5); !ok || bucket-1 != 2 {
+		t.Errorf("expected bucket 2, got %d, %v", bucket-1, ok)
+	}
+
+	if above, err := sut.RangeSum(3, sut.Len()); err != nil || above != 2 {
+		t.Errorf("expected 2 players above bucket 2, got %d, %v", above, err)
+	}
+
+	if _, ok := sut.LowerBound(9); ok {
+		t.Error("found a rank above count of players")
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	sut := fenwick.New[float64](3)
+
+	if err := sut.Add(3, 1); !errors.Is(err, fenwick.ErrOutOfRange) {
+		t.Errorf("Add(3): expected %v, got %v", fenwick.ErrOutOfRange, err)
+	}
+
+	if err := sut.Set(-1, 1); !errors.Is(err, fenwick.ErrOutOfRange) {
+		t.Errorf("Set(-1): expected %v, got %v", fenwick.ErrOutOfRange, err)
+	}
+
+	if _, err := sut.PrefixSum(4); !errors.Is(err, fenwick.ErrOutOfRange) {
+		t.Errorf("PrefixSum(4): expected %v, got %v", fenwick.ErrOutOfRange, err)
+	}
+
+	if _, err := sut.RangeSum(2, 1); !errors.Is(err, fenwick.ErrOutOfRange) {
+		t.Errorf("RangeSum(2, 1): expected %v, got %v", fenwick.ErrOutOfRange, err)
+	}
+}
+
+func TestNegativeLength(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for negative length")
+		}
+	}()
+
+	fenwick.New[int](-1)
+}
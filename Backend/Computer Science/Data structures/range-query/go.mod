@@ -0,0 +1,3 @@
+module github.com/dzianismaroz/marathon/range-query
+
+go 1.23.2
@@ -0,0 +1,98 @@
+// Package rangetest checks range query structures against a naive slice
+// with random operations.
+package rangetest
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+type (
+	// Op is a random operation over the array: a query, an assignment or an update.
+	Op struct {
+		Kind   uint8
+		Lo, Hi uint16
+		Val    int8
+	}
+
+	// Model is a structure under test built over the values of the naive slice.
+	Model struct {
+		// Query returns the aggregate of the half-open range [lo, hi).
+		Query func(lo, hi int) (int, error)
+		// Aggregate returns the expected aggregate of values.
+		Aggregate func(values []int) int
+		// Set replaces the value at i.
+		Set func(i, val int) error
+		// Update applies the update made of val to the range [lo, hi),
+		// or to the value at lo only if Point is set.
+		Update func(lo, hi int, val int8) error
+		// Apply returns the value v after the update made of val.
+		Apply func(val int8, v int) int
+		Point bool
+	}
+)
+
+// Check runs random operations on models built by newModel and on naive slices
+// and fails the test unless all results agree.
+func Check(t *testing.T, newModel func(values []int) Model, maxCount int) {
+	t.Helper()
+
+	property := func(values []int8, ops []Op) bool {
+		naive := make([]int, len(values))
+		for i, v := range values {
+			naive[i] = int(v)
+		}
+
+		m := newModel(append([]int(nil), naive...))
+
+		for _, o := range ops {
+			lo, hi := int(o.Lo)%(len(naive)+1), int(o.Hi)%(len(naive)+1)
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+
+			switch o.Kind % 3 {
+			case 0:
+				got, err := m.Query(lo, hi)
+				if expected := m.Aggregate(naive[lo:hi]); err != nil || got != expected {
+					t.Logf("Query(%d, %d) = %d, %v, expected %d", lo, hi, got, err, expected)
+					return false
+				}
+			case 1:
+				if lo == len(naive) {
+					continue
+				}
+
+				if err := m.Set(lo, int(o.Val)); err != nil {
+					t.Log(err)
+					return false
+				}
+
+				naive[lo] = int(o.Val)
+			default:
+				if m.Point {
+					if lo == len(naive) {
+						continue
+					}
+
+					hi = lo + 1
+				}
+
+				if err := m.Update(lo, hi, o.Val); err != nil {
+					t.Log(err)
+					return false
+				}
+
+				for i := lo; i < hi; i++ {
+					naive[i] = m.Apply(o.Val, naive[i])
+				}
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: maxCount}); err != nil {
+		t.Error(err)
+	}
+}
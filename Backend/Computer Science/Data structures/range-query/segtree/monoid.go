@@ -0,0 +1,62 @@
+package segtree
+
+import (
+	"cmp"
+)
+
+// Number is a type supporting arithmetic operators.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum aggregates values by addition.
+func Sum[T Number]() Monoid[T] {
+	return Monoid[T]{Combine: func(a, b T) T { return a + b }}
+}
+
+// Min aggregates values by the least of them. identity must not be less than any value,
+// e.g. math.MaxInt.
+func Min[T cmp.Ordered](identity T) Monoid[T] {
+	return Monoid[T]{Identity: identity, Combine: func(a, b T) T { return min(a, b) }}
+}
+
+// Max aggregates values by the greatest of them. identity must not be greater than any value,
+// e.g. math.MinInt.
+func Max[T cmp.Ordered](identity T) Monoid[T] {
+	return Monoid[T]{Identity: identity, Combine: func(a, b T) T { return max(a, b) }}
+}
+
+// AddSum adds the update to every value of a range aggregated by Sum.
+func AddSum[T Number]() Action[T, T] {
+	return Action[T, T]{
+		Apply:   func(u, agg T, size int) T { return agg + u*T(size) },
+		Compose: func(newer, older T) T { return newer + older },
+	}
+}
+
+// AssignSum replaces every value of a range aggregated by Sum with the update.
+func AssignSum[T Number]() Action[T, T] {
+	return Action[T, T]{
+		Apply:   func(u, _ T, size int) T { return u * T(size) },
+		Compose: func(newer, _ T) T { return newer },
+	}
+}
+
+// AddExtreme adds the update to every value of a range aggregated by Min or Max,
+// which shifts the extreme by the same amount.
+func AddExtreme[T Number]() Action[T, T] {
+	return Action[T, T]{
+		Apply:   func(u, agg T, _ int) T { return agg + u },
+		Compose: func(newer, older T) T { return newer + older },
+	}
+}
+
+// AssignExtreme replaces every value of a range aggregated by Min or Max with the update.
+func AssignExtreme[T any]() Action[T, T] {
+	return Action[T, T]{
+		Apply:   func(u, _ T, _ int) T { return u },
+		Compose: func(newer, _ T) T { return newer },
+	}
+}
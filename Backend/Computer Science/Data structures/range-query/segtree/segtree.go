@@ -0,0 +1,257 @@
+// Package segtree implements a segment tree: a complete binary tree over an array
+// where every node holds the aggregate of its segment, so both aggregates of any range
+// and updates take O(log n). Range updates are deferred with lazy propagation:
+// a node covered by an update entirely keeps it pending until its children are visited.
+package segtree
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrOutOfRange is wrapped by errors of queries and updates of a position or a range
+	// outside of the array, or a range ending before it starts.
+	ErrOutOfRange = errors.New("index out of range")
+	// ErrNoAction is returned by Update of a tree created by New rather than NewLazy.
+	ErrNoAction = errors.New("segment tree has no range update action")
+)
+
+type (
+	// Monoid defines how values are aggregated: Combine must be associative
+	// and Identity must not change any value it is combined with.
+	// Combine does not have to be commutative: aggregates keep order of the array.
+	Monoid[T any] struct {
+		Identity T
+		Combine  func(a, b T) T
+	}
+
+	// Action defines range updates of type U over values of type T.
+	Action[T, U any] struct {
+		// Apply returns the aggregate of size values, which was agg before u is applied to every of them.
+		Apply func(u U, agg T, size int) T
+		// Compose returns the update equal to applying older, then newer.
+		Compose func(newer, older U) U
+	}
+
+	// Tree answers aggregate queries over ranges of an array of fixed length.
+	// Use New or NewLazy to create a tree.
+	Tree[T, U any] struct {
+		mu      sync.Mutex // queries push pending updates down, so they take it too.
+		n       int
+		aggs    []T    // aggregates of segments, the root is 1 and children of i are 2i and 2i+1.
+		lazy    []U    // updates pending for children of the node.
+		pending []bool // whether lazy holds an update.
+		monoid  Monoid[T]
+		action  *Action[T, U]
+	}
+)
+
+// New creates a tree over a copy of values supporting point updates only.
+// Asymptotic: O(n)
+func New[T any](values []T, monoid Monoid[T]) *Tree[T, T] {
+	return newTree[T, T](values, monoid, nil)
+}
+
+// NewLazy creates a tree over a copy of values supporting range updates by action.
+// Asymptotic: O(n)
+func NewLazy[T, U any](values []T, monoid Monoid[T], action Action[T, U]) *Tree[T, U] {
+	return newTree(values, monoid, &action)
+}
+
+func newTree[T, U any](values []T, monoid Monoid[T], action *Action[T, U]) *Tree[T, U] {
+	t := &Tree[T, U]{n: len(values), monoid: monoid, action: action}
+	if t.n == 0 {
+		return t
+	}
+
+	t.aggs = make([]T, 4*t.n)
+	if action != nil {
+		t.lazy = make([]U, 4*t.n)
+		t.pending = make([]bool, 4*t.n)
+	}
+
+	t.build(1, 0, t.n, values)
+
+	return t
+}
+
+// build fills the node of the segment [lo, hi) and its descendants.
+func (t *Tree[T, U]) build(node, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		t.aggs[node] = values[lo]
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	t.build(2*node, lo, mid, values)
+	t.build(2*node+1, mid, hi, values)
+	t.aggs[node] = t.monoid.Combine(t.aggs[2*node], t.aggs[2*node+1])
+}
+
+// applyTo applies u to every value of the node segment of the given size,
+// deferring it for the children.
+func (t *Tree[T, U]) applyTo(node, size int, u U) {
+	t.aggs[node] = t.action.Apply(u, t.aggs[node], size)
+	if size == 1 {
+		return
+	}
+
+	if t.pending[node] {
+		t.lazy[node] = t.action.Compose(u, t.lazy[node])
+	} else {
+		t.lazy[node], t.pending[node] = u, true
+	}
+}
+
+// push passes the update pending at the node of the segment [lo, hi) to its children.
+func (t *Tree[T, U]) push(node, lo, hi int) {
+	if t.action == nil || !t.pending[node] {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	t.applyTo(2*node, mid-lo, t.lazy[node])
+	t.applyTo(2*node+1, hi-mid, t.lazy[node])
+
+	var zero U
+
+	t.lazy[node], t.pending[node] = zero, false
+}
+
+// query returns the aggregate of the intersection of [qlo, qhi) with the node segment [lo, hi),
+// which must not be empty.
+func (t *Tree[T, U]) query(node, lo, hi, qlo, qhi int) T {
+	if qlo <= lo && hi <= qhi {
+		return t.aggs[node]
+	}
+
+	t.push(node, lo, hi)
+
+	mid := lo + (hi-lo)/2
+
+	switch {
+	case qhi <= mid:
+		return t.query(2*node, lo, mid, qlo, qhi)
+	case qlo >= mid:
+		return t.query(2*node+1, mid, hi, qlo, qhi)
+	default:
+		return t.monoid.Combine(t.query(2*node, lo, mid, qlo, qhi), t.query(2*node+1, mid, hi, qlo, qhi))
+	}
+}
+
+// update applies u to the intersection of [qlo, qhi) with the node segment [lo, hi).
+func (t *Tree[T, U]) update(node, lo, hi, qlo, qhi int, u U) {
+	if qhi <= lo || hi <= qlo {
+		return
+	}
+
+	if qlo <= lo && hi <= qhi {
+		t.applyTo(node, hi-lo, u)
+		return
+	}
+
+	t.push(node, lo, hi)
+
+	mid := lo + (hi-lo)/2
+	t.update(2*node, lo, mid, qlo, qhi, u)
+	t.update(2*node+1, mid, hi, qlo, qhi, u)
+	t.aggs[node] = t.monoid.Combine(t.aggs[2*node], t.aggs[2*node+1])
+}
+
+// set replaces the value at i within the node segment [lo, hi).
+func (t *Tree[T, U]) set(node, lo, hi, i int, val T) {
+	if hi-lo == 1 {
+		t.aggs[node] = val
+		return
+	}
+
+	t.push(node, lo, hi)
+
+	if mid := lo + (hi-lo)/2; i < mid {
+		t.set(2*node, lo, mid, i, val)
+	} else {
+		t.set(2*node+1, mid, hi, i, val)
+	}
+
+	t.aggs[node] = t.monoid.Combine(t.aggs[2*node], t.aggs[2*node+1])
+}
+
+// checkRange validates the half-open range [lo, hi).
+func (t *Tree[T, U]) checkRange(lo, hi int) error {
+	if lo < 0 || hi > t.n || lo > hi {
+		return fmt.Errorf("%w: [%d, %d) with length %d", ErrOutOfRange, lo, hi, t.n)
+	}
+
+	return nil
+}
+
+// Len returns length of the array.
+// Asymptotic: O(1)
+func (t *Tree[T, U]) Len() int {
+	return t.n
+}
+
+// Query returns the aggregate of values of the half-open range [lo, hi),
+// the identity for an empty one.
+// Asymptotic: O(log n)
+func (t *Tree[T, U]) Query(lo, hi int) (T, error) {
+	if err := t.checkRange(lo, hi); err != nil {
+		return t.monoid.Identity, err
+	}
+
+	if lo == hi {
+		return t.monoid.Identity, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.query(1, 0, t.n, lo, hi), nil
+}
+
+// Get returns the value at i.
+// Asymptotic: O(log n)
+func (t *Tree[T, U]) Get(i int) (T, error) {
+	return t.Query(i, i+1)
+}
+
+// Set replaces the value at i.
+// Asymptotic: O(log n)
+func (t *Tree[T, U]) Set(i int, val T) error {
+	if err := t.checkRange(i, i+1); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.set(1, 0, t.n, i, val)
+
+	return nil
+}
+
+// Update applies u to every value of the half-open range [lo, hi).
+// It returns ErrNoAction for a tree created by New.
+// Asymptotic: O(log n)
+func (t *Tree[T, U]) Update(lo, hi int, u U) error {
+	if t.action == nil {
+		return ErrNoAction
+	}
+
+	if err := t.checkRange(lo, hi); err != nil {
+		return err
+	}
+
+	if lo == hi {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.update(1, 0, t.n, lo, hi, u)
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package segtree_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/range-query/internal/rangetest"
+	"github.com/dzianismaroz/marathon/range-query/segtree"
+)
+
+// model describes a tree configuration checked against a naive slice.
+type model[U any] struct {
+	newTree   func(values []int) *segtree.Tree[int, U]
+	aggregate func(values []int) int
+	update    func(val int8) U
+	apply     func(u U, v int) int
+}
+
+// build creates the tree over values in the form checked by rangetest.
+func (m model[U]) build(values []int) rangetest.Model {
+	sut := m.newTree(values)
+
+	return rangetest.Model{
+		Query:     sut.Query,
+		Aggregate: m.aggregate,
+		Set:       sut.Set,
+		Update:    func(lo, hi int, val int8) error { return sut.Update(lo, hi, m.update(val)) },
+		Apply:     func(val int8, v int) int { return m.apply(m.update(val), v) },
+	}
+}
+
+func sum(values []int) int {
+	var result int
+	for _, v := range values {
+		result += v
+	}
+
+	return result
+}
+
+func minimum(values []int) int {
+	result := math.MaxInt
+	for _, v := range values {
+		result = min(result, v)
+	}
+
+	return result
+}
+
+func maximum(values []int) int {
+	result := math.MinInt
+	for _, v := range values {
+		result = max(result, v)
+	}
+
+	return result
+}
+
+func identity(val int8) int {
+	return int(val)
+}
+
+// affine is the update v -> mul*v + add.
+type affine struct {
+	mul, add int
+}
+
+func TestAgainstNaive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		newModel func(values []int) rangetest.Model
+	}{
+		{
+			name: "sum with addition",
+			newModel: model[int]{
+				newTree: func(values []int) *segtree.Tree[int, int] {
+					return segtree.NewLazy(values, segtree.Sum[int](), segtree.AddSum[int]())
+				},
+				aggregate: sum, update: identity,
+				apply: func(u, v int) int { return v + u },
+			}.build,
+		},
+		{
+			name: "sum with assignment",
+			newModel: model[int]{
+				newTree: func(values []int) *segtree.Tree[int, int] {
+					return segtree.NewLazy(values, segtree.Sum[int](), segtree.AssignSum[int]())
+				},
+				aggregate: sum, update: identity,
+				apply: func(u, _ int) int { return u },
+			}.build,
+		},
+		{
+			name: "min with addition",
+			newModel: model[int]{
+				newTree: func(values []int) *segtree.Tree[int, int] {
+					return segtree.NewLazy(values, segtree.Min(math.MaxInt), segtree.AddExtreme[int]())
+				},
+				aggregate: minimum, update: identity,
+				apply: func(u, v int) int { return v + u },
+			}.build,
+		},
+		{
+			name: "max with assignment",
+			newModel: model[int]{
+				newTree: func(values []int) *segtree.Tree[int, int] {
+					return segtree.NewLazy(values, segtree.Max(math.MinInt), segtree.AssignExtreme[int]())
+				},
+				aggregate: maximum, update: identity,
+				apply: func(u, _ int) int { return u },
+			}.build,
+		},
+		{
+			// Affine updates do not commute, so pending ones must be composed in order.
+			name: "sum with affine updates",
+			newModel: model[affine]{
+				newTree: func(values []int) *segtree.Tree[int, affine] {
+					return segtree.NewLazy(values, segtree.Sum[int](), segtree.Action[int, affine]{
+						Apply: func(u affine, agg int, size int) int { return u.mul*agg + u.add*size },
+						Compose: func(newer, older affine) affine {
+							return affine{mul: newer.mul * older.mul, add: newer.mul*older.add + newer.add}
+						},
+					})
+				},
+				aggregate: sum,
+				update:    func(val int8) affine { return affine{mul: int(val%3) - 1, add: int(val / 3)} },
+				apply:     func(u affine, v int) int { return u.mul*v + u.add },
+			}.build,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rangetest.Check(t, tt.newModel, 300)
+		})
+	}
+}
+
+func TestNonCommutative(t *testing.T) {
+	t.Parallel()
+
+	concat := segtree.Monoid[string]{Combine: func(a, b string) string { return a + b }}
+	sut := segtree.New(strings.Split("segment", ""), concat)
+
+	if got, err := sut.Query(1, 6); err != nil || got != "egmen" {
+		t.Errorf("expected egmen, got %q, %v", got, err)
+	}
+
+	if err := sut.Set(0, "S"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := sut.Query(0, sut.Len()); err != nil || got != "Segment" {
+		t.Errorf("expected Segment, got %q, %v", got, err)
+	}
+
+	if err := sut.Update(0, 1, "x"); !errors.Is(err, segtree.ErrNoAction) {
+		t.Errorf("expected %v, got %v", segtree.ErrNoAction, err)
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	sut := segtree.NewLazy([]int{1, 2, 3}, segtree.Sum[int](), segtree.AddSum[int]())
+
+	for _, r := range [][2]int{{-1, 2}, {0, 4}, {2, 1}} {
+		if _, err := sut.Query(r[0], r[1]); !errors.Is(err, segtree.ErrOutOfRange) {
+			t.Errorf("Query(%d, %d): expected %v, got %v", r[0], r[1], segtree.ErrOutOfRange, err)
+		}
+
+		if err := sut.Update(r[0], r[1], 1); !errors.Is(err, segtree.ErrOutOfRange) {
+			t.Errorf("Update(%d, %d): expected %v, got %v", r[0], r[1], segtree.ErrOutOfRange, err)
+		}
+	}
+
+	if err := sut.Set(3, 0); !errors.Is(err, segtree.ErrOutOfRange) {
+		t.Errorf("Set(3): expected %v, got %v", segtree.ErrOutOfRange, err)
+	}
+
+	empty := segtree.New(nil, segtree.Sum[int]())
+	if got, err := empty.Query(0, 0); err != nil || got != 0 {
+		t.Errorf("empty range of an empty tree: expected 0, got %d, %v", got, err)
+	}
+}
+
+func BenchmarkUpdateQuery(b *testing.B) {
+	const size = 1 << 20
+
+	rnd := rand.New(rand.NewSource(1))
+	sut := segtree.NewLazy(make([]int, size), segtree.Sum[int](), segtree.AddSum[int]())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		lo, hi := rnd.Intn(size), rnd.Intn(size)
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+
+		_ = sut.Update(lo, hi, 1)
+		_, _ = sut.Query(lo, hi)
+	}
+}
@@ -0,0 +1,383 @@
+// Package btree implements an in-memory B-tree: an ordered set keeping many
+// elements per node, so lookups touch O(log n / log d) nodes of contiguous memory
+// instead of O(log n) scattered binary nodes.
+package btree
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// DefaultDegree suits elements of a few machine words.
+const DefaultDegree = 32
+
+// ErrInvariant is wrapped by CheckInvariants errors naming a node with too few or too many
+// elements or children, an element out of order or leaves at different depths.
+var ErrInvariant = errors.New("b-tree invariant violated")
+
+type (
+	node[T any] struct {
+		items    []T        // sorted elements.
+		children []*node[T] // len(items)+1 subtrees, empty for a leaf.
+	}
+
+	// BTree is a B-tree of minimum degree d: every node but the root holds
+	// from d-1 to 2d-1 sorted elements and all leaves are at the same depth.
+	// It implements tree.OrderedSet with the same semantics as tree.Tree,
+	// including modification of the tree while iterating over it.
+	// Use New or NewFunc to create a tree.
+	BTree[T any] struct {
+		mu      sync.RWMutex
+		compare func(a, b T) int
+		degree  int
+		root    *node[T]
+		size    uint
+		version uint64 // incremented on every modification.
+	}
+)
+
+// New creates an empty B-tree of naturally ordered elements with the minimum degree,
+// which is raised to 2 when less. Pass DefaultDegree unless benchmarks suggest otherwise.
+func New[T cmp.Ordered](degree int) *BTree[T] {
+	return NewFunc(cmp.Compare[T], degree)
+}
+
+// NewFunc creates an empty B-tree ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewFunc[T any](compare func(a, b T) int, degree int) *BTree[T] {
+	return &BTree[T]{compare: compare, degree: max(degree, 2)}
+}
+
+// newNode allocates a node with room for an overflowing element, so it never reallocates.
+func (t *BTree[T]) newNode(leaf bool) *node[T] {
+	n := &node[T]{items: make([]T, 0, 2*t.degree)}
+	if !leaf {
+		n.children = make([]*node[T], 0, 2*t.degree+1)
+	}
+
+	return n
+}
+
+func (n *node[T]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// search returns position of elem among items of the node and whether it is there.
+func (t *BTree[T]) search(n *node[T], elem T) (int, bool) {
+	return slices.BinarySearchFunc(n.items, elem, t.compare)
+}
+
+// splitChild splits the overflowing i-th child of n around its median, which moves up to n.
+func (t *BTree[T]) splitChild(n *node[T], i int) {
+	child := n.children[i]
+	median := child.items[t.degree]
+
+	right := t.newNode(child.leaf())
+	right.items = append(right.items, child.items[t.degree+1:]...)
+	child.items = slices.Delete(child.items, t.degree, len(child.items))
+
+	if !child.leaf() {
+		right.children = append(right.children, child.children[t.degree+1:]...)
+		child.children = slices.Delete(child.children, t.degree+1, len(child.children))
+	}
+
+	n.items = slices.Insert(n.items, i, median)
+	n.children = slices.Insert(n.children, i+1, right)
+}
+
+// insert adds elem into the subtree and reports whether it was absent.
+// A child overflowing by the insertion is split, the node itself is split by its parent.
+func (t *BTree[T]) insert(n *node[T], elem T) bool {
+	i, found := t.search(n, elem)
+	if found {
+		return false
+	}
+
+	if n.leaf() {
+		n.items = slices.Insert(n.items, i, elem)
+		return true
+	}
+
+	if !t.insert(n.children[i], elem) {
+		return false
+	}
+
+	if len(n.children[i].items) >= 2*t.degree {
+		t.splitChild(n, i)
+	}
+
+	return true
+}
+
+// Add inserts elem into the tree, duplicates are ignored.
+// Asymptotic: O(d log n / log d)
+func (t *BTree[T]) Add(elem T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.root == nil {
+		t.root = t.newNode(true)
+	}
+
+	if !t.insert(t.root, elem) {
+		return
+	}
+
+	if len(t.root.items) >= 2*t.degree {
+		root := t.newNode(false)
+		root.children = append(root.children, t.root)
+		t.root = root
+		t.splitChild(root, 0)
+	}
+
+	t.size++
+	t.version++
+}
+
+// fixChild restores the minimum of d-1 elements in the i-th child of n
+// by borrowing an element from a sibling or merging with it.
+func (t *BTree[T]) fixChild(n *node[T], i int) {
+	child := n.children[i]
+	if len(child.items) >= t.degree-1 {
+		return
+	}
+
+	switch {
+	case i > 0 && len(n.children[i-1].items) >= t.degree:
+		// Rotate the greatest element of the left sibling through the parent.
+		left := n.children[i-1]
+		last := len(left.items) - 1
+		child.items = slices.Insert(child.items, 0, n.items[i-1])
+		n.items[i-1] = left.items[last]
+		left.items = slices.Delete(left.items, last, last+1)
+
+		if !left.leaf() {
+			last = len(left.children) - 1
+			child.children = slices.Insert(child.children, 0, left.children[last])
+			left.children = slices.Delete(left.children, last, last+1)
+		}
+	case i < len(n.children)-1 && len(n.children[i+1].items) >= t.degree:
+		// Rotate the least element of the right sibling through the parent.
+		right := n.children[i+1]
+		child.items = append(child.items, n.items[i])
+		n.items[i] = right.items[0]
+		right.items = slices.Delete(right.items, 0, 1)
+
+		if !right.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+	default:
+		// Both siblings are minimal: merge with one of them and the separating element.
+		if i == len(n.children)-1 {
+			i--
+		}
+
+		left, right := n.children[i], n.children[i+1]
+		left.items = append(append(left.items, n.items[i]), right.items...)
+		left.children = append(left.children, right.children...)
+		n.items = slices.Delete(n.items, i, i+1)
+		n.children = slices.Delete(n.children, i+1, i+2)
+	}
+}
+
+// removeMax removes the greatest element of the subtree and returns it.
+func (t *BTree[T]) removeMax(n *node[T]) T {
+	if n.leaf() {
+		last := len(n.items) - 1
+		elem := n.items[last]
+		n.items = slices.Delete(n.items, last, last+1)
+
+		return elem
+	}
+
+	last := len(n.children) - 1
+	elem := t.removeMax(n.children[last])
+	t.fixChild(n, last)
+
+	return elem
+}
+
+// remove deletes elem from the subtree and reports whether it was present.
+// A child underflowing by the removal is fixed, the node itself is fixed by its parent.
+func (t *BTree[T]) remove(n *node[T], elem T) bool {
+	i, found := t.search(n, elem)
+
+	switch {
+	case n.leaf():
+		if !found {
+			return false
+		}
+
+		n.items = slices.Delete(n.items, i, i+1)
+
+		return true
+	case found:
+		// Replace elem with its in-order predecessor taken from the left subtree.
+		n.items[i] = t.removeMax(n.children[i])
+	case !t.remove(n.children[i], elem):
+		return false
+	}
+
+	t.fixChild(n, i)
+
+	return true
+}
+
+// Delete removes elem from the tree and reports whether it was present.
+// Asymptotic: O(d log n / log d)
+func (t *BTree[T]) Delete(elem T) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.root == nil || !t.remove(t.root, elem) {
+		return false
+	}
+
+	if len(t.root.items) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+
+	t.size--
+	t.version++
+
+	return true
+}
+
+// Contains reports whether elem is present in the tree.
+// Asymptotic: O(log n)
+func (t *BTree[T]) Contains(elem T) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for n := t.root; n != nil; {
+		i, found := t.search(n, elem)
+		if found {
+			return true
+		}
+
+		if n.leaf() {
+			return false
+		}
+
+		n = n.children[i]
+	}
+
+	return false
+}
+
+// Size returns count of elements in the tree.
+// Asymptotic: O(1)
+func (t *BTree[T]) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.size
+}
+
+// Height returns count of nodes on the path from the root to any leaf, 0 for an empty tree.
+// Asymptotic: O(log n / log d)
+func (t *BTree[T]) Height() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root == nil {
+		return 0
+	}
+
+	height := 1
+	for n := t.root; !n.leaf(); n = n.children[0] {
+		height++
+	}
+
+	return height
+}
+
+// check validates the subtree bounded by (lo, hi) at the given depth
+// and returns the count of its elements and depth of its leaves.
+func (t *BTree[T]) check(n *node[T], lo, hi *T, root bool) (uint, int, error) {
+	if !root && len(n.items) < t.degree-1 || len(n.items) > 2*t.degree-1 {
+		return 0, 0, fmt.Errorf("%w: node of %v holds %d elements", ErrInvariant, n.items, len(n.items))
+	}
+
+	if !n.leaf() && len(n.children) != len(n.items)+1 {
+		return 0, 0, fmt.Errorf("%w: node of %v has %d children", ErrInvariant, n.items, len(n.children))
+	}
+
+	for i, item := range n.items {
+		if lo != nil && t.compare(item, *lo) <= 0 || hi != nil && t.compare(item, *hi) >= 0 ||
+			i > 0 && t.compare(n.items[i-1], item) >= 0 {
+			return 0, 0, fmt.Errorf("%w: %v is out of order", ErrInvariant, item)
+		}
+	}
+
+	count := uint(len(n.items))
+	if n.leaf() {
+		return count, 1, nil
+	}
+
+	depth := 0
+
+	for i, child := range n.children {
+		childLo, childHi := lo, hi
+		if i > 0 {
+			childLo = &n.items[i-1]
+		}
+
+		if i < len(n.items) {
+			childHi = &n.items[i]
+		}
+
+		childCount, childDepth, err := t.check(child, childLo, childHi, false)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if i > 0 && childDepth != depth {
+			return 0, 0, fmt.Errorf("%w: leaves under %v are at different depths", ErrInvariant, n.items)
+		}
+
+		depth = childDepth
+		count += childCount
+	}
+
+	return count, depth + 1, nil
+}
+
+// CheckInvariants verifies ordering of the elements, fill of every node,
+// depth of leaves and the tree size. It is intended to be called from tests.
+// Asymptotic: O(n)
+func (t *BTree[T]) CheckInvariants() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root == nil {
+		if t.size != 0 {
+			return fmt.Errorf("%w: empty tree has size %d", ErrInvariant, t.size)
+		}
+
+		return nil
+	}
+
+	if len(t.root.items) == 0 {
+		return fmt.Errorf("%w: root is empty", ErrInvariant)
+	}
+
+	count, _, err := t.check(t.root, nil, nil, true)
+	if err != nil {
+		return err
+	}
+
+	if count != t.size {
+		return fmt.Errorf("%w: tree holds %d elements, but size is %d", ErrInvariant, count, t.size)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package btree_test
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/btree"
+	"github.com/dzianismaroz/marathon/tree/settest"
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+var _ tree.OrderedSet[int] = (*btree.BTree[int])(nil)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	for _, degree := range []int{2, 3, 8, btree.DefaultDegree} {
+		t.Run(fmt.Sprintf("degree %d", degree), func(t *testing.T) {
+			t.Parallel()
+			settest.Run(t, func() tree.OrderedSet[int] { return btree.New[int](degree) })
+		})
+	}
+}
+
+func TestBTreeHeight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		degree   int
+		size     int
+		expected int
+	}{
+		{degree: 2, size: 0, expected: 0},
+		{degree: 2, size: 3, expected: 1},
+		{degree: 2, size: 4, expected: 2},
+		{degree: 32, size: 63, expected: 1},
+		{degree: 32, size: 100_000, expected: 4},
+	}
+
+	for _, tt := range tests {
+		sut := btree.New[int](tt.degree)
+		for i := range tt.size {
+			sut.Add(i)
+		}
+
+		if height := sut.Height(); height != tt.expected {
+			t.Errorf("degree %d with %d elements: expected height %d, got %d", tt.degree, tt.size, tt.expected, height)
+		}
+	}
+}
+
+func TestBTreeCustomOrder(t *testing.T) {
+	t.Parallel()
+
+	sut := btree.NewFunc(func(a, b string) int { return len(a) - len(b) }, 0)
+	for _, s := range []string{"ccc", "a", "bb", "dd"} {
+		sut.Add(s)
+	}
+
+	if got, expected := sut.SortedDesc(), []string{"ccc", "bb", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+const benchSize = 1_000_000
+
+// backends lists ordered sets compared by benchmarks.
+var backends = []struct {
+	name string
+	new  func() tree.OrderedSet[int]
+}{
+	{name: "Tree", new: func() tree.OrderedSet[int] { return tree.New[int]() }},
+	{name: "ArenaTree", new: func() tree.OrderedSet[int] { return tree.NewArena[int]() }},
+	{name: "BTree/degree=8", new: func() tree.OrderedSet[int] { return btree.New[int](8) }},
+	{name: "BTree/degree=32", new: func() tree.OrderedSet[int] { return btree.New[int](btree.DefaultDegree) }},
+	{name: "BTree/degree=128", new: func() tree.OrderedSet[int] { return btree.New[int](128) }},
+}
+
+func filled(newSet func() tree.OrderedSet[int], keys []int) tree.OrderedSet[int] {
+	set := newSet()
+	for _, key := range keys {
+		set.Add(key)
+	}
+
+	return set
+}
+
+func BenchmarkAdd(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchSize)
+
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				filled(backend.new, keys)
+			}
+
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*benchSize), "ns/add")
+		})
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchSize)
+
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			set := filled(backend.new, keys)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				set.Contains(keys[i%benchSize])
+			}
+		})
+	}
+}
+
+func BenchmarkDeleteAdd(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchSize)
+
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			set := filled(backend.new, keys)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				set.Delete(keys[i%benchSize])
+				set.Add(keys[i%benchSize])
+			}
+		})
+	}
+}
+
+func BenchmarkRange(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchSize)
+
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			set := filled(backend.new, keys)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				lo := keys[i%benchSize]
+				for v := range set.Range(lo, lo+1000) {
+					_ = v
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package btree
+
+import (
+	"iter"
+)
+
+type (
+	// frame is a node being walked with position of its next element.
+	frame[T any] struct {
+		node *node[T]
+		next int
+	}
+
+	// walker performs lazy in-order traversal with an explicit stack of nodes,
+	// one per level of the tree.
+	walker[T any] struct {
+		tree  *BTree[T]
+		stack []frame[T]
+		desc  bool
+	}
+)
+
+// pushFrom pushes n and the chain of its first-visited descendants.
+func (w *walker[T]) pushFrom(n *node[T]) {
+	for n != nil {
+		if w.desc {
+			w.stack = append(w.stack, frame[T]{n, len(n.items) - 1})
+		} else {
+			w.stack = append(w.stack, frame[T]{n, 0})
+		}
+
+		switch {
+		case n.leaf():
+			n = nil
+		case w.desc:
+			n = n.children[len(n.children)-1]
+		default:
+			n = n.children[0]
+		}
+	}
+}
+
+// seek positions the walker on the first element not before from in the walking direction.
+// from itself is skipped unless inclusive is set.
+func (w *walker[T]) seek(from T, inclusive bool) {
+	w.stack = w.stack[:0]
+
+	for n := w.tree.root; n != nil; {
+		i, found := w.tree.search(n, from)
+
+		// i is the position of the first element not before from in ascending order
+		// and the child subtree i holds elements between it and its predecessor.
+		child := i
+
+		switch {
+		case w.desc && found && inclusive:
+			w.stack = append(w.stack, frame[T]{n, i})
+			return
+		case w.desc:
+			w.stack = append(w.stack, frame[T]{n, i - 1})
+		case found && !inclusive:
+			w.stack = append(w.stack, frame[T]{n, i + 1})
+			child = i + 1
+		default:
+			w.stack = append(w.stack, frame[T]{n, i})
+
+			if found {
+				return
+			}
+		}
+
+		if n.leaf() {
+			return
+		}
+
+		n = n.children[child]
+	}
+}
+
+// next returns the next element of the walk and reports whether there is one.
+func (w *walker[T]) next() (T, bool) {
+	for len(w.stack) > 0 {
+		top := len(w.stack) - 1
+		n, i := w.stack[top].node, w.stack[top].next
+
+		if i < 0 || i >= len(n.items) {
+			w.stack = w.stack[:top]
+			continue
+		}
+
+		if w.desc {
+			w.stack[top].next--
+		} else {
+			w.stack[top].next++
+		}
+
+		if !n.leaf() {
+			if w.desc {
+				w.pushFrom(n.children[i])
+			} else {
+				w.pushFrom(n.children[i+1])
+			}
+		}
+
+		return n.items[i], true
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// walk returns an iterator over the tree elements in the given direction,
+// starting from the from value (or from the very edge of the tree when it is nil)
+// and lasting while within reports true.
+//
+// As for tree.Tree, the read lock is not held during yield, so the loop body
+// is free to modify the tree: then the walker re-seeks after the last yielded element.
+func (t *BTree[T]) walk(desc bool, from *T, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.mu.RLock()
+
+		w := walker[T]{tree: t, stack: make([]frame[T], 0, 8), desc: desc}
+		if from == nil {
+			w.pushFrom(t.root)
+		} else {
+			w.seek(*from, true)
+		}
+
+		version := t.version
+
+		for {
+			val, ok := w.next()
+			t.mu.RUnlock()
+
+			if !ok || within != nil && !within(val) || !yield(val) {
+				return
+			}
+
+			t.mu.RLock()
+
+			if t.version != version {
+				w.seek(val, false)
+				version = t.version
+			}
+		}
+	}
+}
+
+// All returns an iterator over all elements in ascending order.
+// Asymptotic: O(n) for the full iteration, O(log n / log d) memory.
+func (t *BTree[T]) All() iter.Seq[T] {
+	return t.walk(false, nil, nil)
+}
+
+// Backward returns an iterator over all elements in descending order.
+// Asymptotic: O(n) for the full iteration, O(log n / log d) memory.
+func (t *BTree[T]) Backward() iter.Seq[T] {
+	return t.walk(true, nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *BTree[T]) Ascend(from T) iter.Seq[T] {
+	return t.walk(false, &from, nil)
+}
+
+// Descend returns an iterator over elements less than or equal to from in descending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *BTree[T]) Descend(from T) iter.Seq[T] {
+	return t.walk(true, &from, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *BTree[T]) Range(lo, hi T) iter.Seq[T] {
+	return t.walk(false, &lo, func(val T) bool { return t.compare(val, hi) < 0 })
+}
+
+// SortedAsc returns all elements of the tree in ascending order.
+// Asymptotic: O(n)
+func (t *BTree[T]) SortedAsc() []T {
+	return t.sorted(false)
+}
+
+// SortedDesc returns all elements of the tree in descending order.
+// Asymptotic: O(n)
+func (t *BTree[T]) SortedDesc() []T {
+	return t.sorted(true)
+}
+
+func (t *BTree[T]) sorted(desc bool) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	result := make([]T, 0, t.size)
+	w := walker[T]{tree: t, desc: desc}
+	w.pushFrom(t.root)
+
+	for val, ok := w.next(); ok; val, ok = w.next() {
+		result = append(result, val)
+	}
+
+	return result
+}
@@ -0,0 +1,150 @@
+package btree
+
+// value unwraps the (value, ok) form used by the public API.
+func value[T any](n *node[T], i int) (T, bool) {
+	if n == nil {
+		var zero T
+
+		return zero, false
+	}
+
+	return n.items[i], true
+}
+
+// edge returns the node and position of the least (or the greatest when desc is set) element.
+func (t *BTree[T]) edge(desc bool) (*node[T], int) {
+	n := t.root
+	if n == nil {
+		return nil, 0
+	}
+
+	for !n.leaf() {
+		if desc {
+			n = n.children[len(n.children)-1]
+		} else {
+			n = n.children[0]
+		}
+	}
+
+	if desc {
+		return n, len(n.items) - 1
+	}
+
+	return n, 0
+}
+
+// floor returns the node and position of the greatest element less than elem
+// (or equal to it when inclusive is set).
+func (t *BTree[T]) floor(elem T, inclusive bool) (*node[T], int) {
+	var (
+		candidate *node[T]
+		at        int
+	)
+
+	for n := t.root; n != nil; {
+		i, found := t.search(n, elem)
+		if found && inclusive {
+			return n, i
+		}
+
+		if i > 0 {
+			candidate, at = n, i-1
+		}
+
+		if n.leaf() {
+			break
+		}
+
+		n = n.children[i]
+	}
+
+	return candidate, at
+}
+
+// ceiling returns the node and position of the least element greater than elem
+// (or equal to it when inclusive is set).
+func (t *BTree[T]) ceiling(elem T, inclusive bool) (*node[T], int) {
+	var (
+		candidate *node[T]
+		at        int
+	)
+
+	for n := t.root; n != nil; {
+		i, found := t.search(n, elem)
+		if found {
+			if inclusive {
+				return n, i
+			}
+
+			i++
+		}
+
+		if i < len(n.items) {
+			candidate, at = n, i
+		}
+
+		if n.leaf() {
+			break
+		}
+
+		n = n.children[i]
+	}
+
+	return candidate, at
+}
+
+// Min returns the least element of the tree if presented.
+// Asymptotic: O(log n / log d)
+func (t *BTree[T]) Min() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.edge(false))
+}
+
+// Max returns the greatest element of the tree if presented.
+// Asymptotic: O(log n / log d)
+func (t *BTree[T]) Max() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.edge(true))
+}
+
+// Floor returns the greatest element less than or equal to elem.
+// Asymptotic: O(log n)
+func (t *BTree[T]) Floor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.floor(elem, true))
+}
+
+// Ceiling returns the least element greater than or equal to elem.
+// Asymptotic: O(log n)
+func (t *BTree[T]) Ceiling(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.ceiling(elem, true))
+}
+
+// Predecessor returns the greatest element strictly less than elem.
+// elem itself does not have to be present in the tree.
+// Asymptotic: O(log n)
+func (t *BTree[T]) Predecessor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.floor(elem, false))
+}
+
+// Successor returns the least element strictly greater than elem.
+// elem itself does not have to be present in the tree.
+// Asymptotic: O(log n)
+func (t *BTree[T]) Successor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.ceiling(elem, false))
+}
@@ -1,3 +1,7 @@
 module github.com/dzianismaroz/marathon/tree
 
 go 1.23.2
+
+require github.com/dzianismaroz/marathon/queue v0.0.0
+
+replace github.com/dzianismaroz/marathon/queue => ../queue
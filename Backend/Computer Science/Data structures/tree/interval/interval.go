@@ -0,0 +1,118 @@
+// Package interval implements an interval tree: a self-balancing (AVL) binary search tree
+// of intervals ordered by their lower bounds, where every node is augmented with
+// the greatest upper bound of its subtree, so overlap queries skip subtrees ending too early.
+//
+// Endpoints are ordered by a comparison function and treated as points of a dense order,
+// so (1, 2) is not empty even for integers.
+package interval
+
+import (
+	"fmt"
+)
+
+// Interval is a range of endpoint values, each bound of it is either closed
+// (includes the endpoint) or open. Use Closed, Open, ClosedOpen or OpenClosed to create one.
+type Interval[E any] struct {
+	Lo, Hi E
+	LoOpen bool // whether Lo itself is excluded.
+	HiOpen bool // whether Hi itself is excluded.
+}
+
+// Closed returns the interval [lo, hi].
+func Closed[E any](lo, hi E) Interval[E] {
+	return Interval[E]{Lo: lo, Hi: hi}
+}
+
+// Open returns the interval (lo, hi).
+func Open[E any](lo, hi E) Interval[E] {
+	return Interval[E]{Lo: lo, Hi: hi, LoOpen: true, HiOpen: true}
+}
+
+// ClosedOpen returns the interval [lo, hi), which suits bookings: one ending at t
+// does not overlap another one starting at t.
+func ClosedOpen[E any](lo, hi E) Interval[E] {
+	return Interval[E]{Lo: lo, Hi: hi, HiOpen: true}
+}
+
+// OpenClosed returns the interval (lo, hi].
+func OpenClosed[E any](lo, hi E) Interval[E] {
+	return Interval[E]{Lo: lo, Hi: hi, LoOpen: true}
+}
+
+// String formats the interval in the math notation, e.g. [1, 5).
+func (iv Interval[E]) String() string {
+	lo, hi := "[", "]"
+	if iv.LoOpen {
+		lo = "("
+	}
+
+	if iv.HiOpen {
+		hi = ")"
+	}
+
+	return fmt.Sprintf("%s%v, %v%s", lo, iv.Lo, iv.Hi, hi)
+}
+
+// compareLo orders lower bounds: a closed bound goes before an open one at the same endpoint.
+func compareLo[E any](compare func(a, b E) int, a, b Interval[E]) int {
+	if c := compare(a.Lo, b.Lo); c != 0 {
+		return c
+	}
+
+	return boolCompare(a.LoOpen, b.LoOpen)
+}
+
+// compareHi orders upper bounds: an open bound goes before a closed one at the same endpoint.
+func compareHi[E any](compare func(a, b E) int, a, b Interval[E]) int {
+	if c := compare(a.Hi, b.Hi); c != 0 {
+		return c
+	}
+
+	return boolCompare(b.HiOpen, a.HiOpen)
+}
+
+// order sorts intervals by lower bounds, then by upper bounds.
+func order[E any](compare func(a, b E) int, a, b Interval[E]) int {
+	if c := compareLo(compare, a, b); c != 0 {
+		return c
+	}
+
+	return compareHi(compare, a, b)
+}
+
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// startsBeforeEnd reports whether a point of a at its lower bound may reach b at its upper bound,
+// i.e. the lower bound of a does not lie past the upper bound of b.
+func startsBeforeEnd[E any](compare func(a, b E) int, a, b Interval[E]) bool {
+	c := compare(a.Lo, b.Hi)
+
+	return c < 0 || c == 0 && !a.LoOpen && !b.HiOpen
+}
+
+// empty reports whether the interval holds no point.
+func empty[E any](compare func(a, b E) int, iv Interval[E]) bool {
+	return !startsBeforeEnd(compare, iv, iv)
+}
+
+// overlaps reports whether a and b share at least one point.
+func overlaps[E any](compare func(a, b E) int, a, b Interval[E]) bool {
+	return startsBeforeEnd(compare, a, b) && startsBeforeEnd(compare, b, a)
+}
+
+// joins reports whether the union of a and b, where a starts not after b, is a single interval:
+// they overlap or touch at an endpoint included by at least one of them.
+func joins[E any](compare func(a, b E) int, a, b Interval[E]) bool {
+	c := compare(b.Lo, a.Hi)
+
+	return c < 0 || c == 0 && (!b.LoOpen || !a.HiOpen)
+}
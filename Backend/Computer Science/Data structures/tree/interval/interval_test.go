@@ -0,0 +1,410 @@
+package interval_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dzianismaroz/marathon/tree/interval"
+)
+
+func TestIntervalString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		iv       interval.Interval[int]
+		expected string
+	}{
+		{iv: interval.Closed(1, 5), expected: "[1, 5]"},
+		{iv: interval.Open(1, 5), expected: "(1, 5)"},
+		{iv: interval.ClosedOpen(1, 5), expected: "[1, 5)"},
+		{iv: interval.OpenClosed(1, 5), expected: "(1, 5]"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.iv.String(); got != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestInsertEmpty(t *testing.T) {
+	t.Parallel()
+
+	sut := interval.New[int, string]()
+
+	for _, iv := range []interval.Interval[int]{
+		interval.Closed(2, 1), interval.ClosedOpen(1, 1), interval.OpenClosed(1, 1), interval.Open(1, 1),
+	} {
+		if err := sut.Insert(iv, "empty"); !errors.Is(err, interval.ErrEmpty) {
+			t.Errorf("Insert(%v): expected %v, got %v", iv, interval.ErrEmpty, err)
+		}
+	}
+
+	if err := sut.Insert(interval.Closed(1, 1), "point"); err != nil {
+		t.Errorf("Insert([1, 1]): %v", err)
+	}
+
+	if sut.Size() != 1 {
+		t.Errorf("expected size 1, got %d", sut.Size())
+	}
+}
+
+func TestInsertDeleteGet(t *testing.T) {
+	t.Parallel()
+
+	sut := interval.New[int, string]()
+
+	mustInsert(t, sut, interval.Closed(1, 3), "a")
+	mustInsert(t, sut, interval.ClosedOpen(1, 3), "b")
+	mustInsert(t, sut, interval.Closed(1, 3), "c")
+
+	if sut.Size() != 2 {
+		t.Errorf("expected size 2, got %d", sut.Size())
+	}
+
+	if val, ok := sut.Get(interval.Closed(1, 3)); !ok || val != "c" {
+		t.Errorf("expected replaced value c, got %q, %v", val, ok)
+	}
+
+	if sut.Delete(interval.Open(1, 3)) {
+		t.Error("deleted an absent interval with other bounds")
+	}
+
+	if !sut.Delete(interval.ClosedOpen(1, 3)) {
+		t.Error("[1, 3) is not deleted")
+	}
+
+	if _, ok := sut.Get(interval.ClosedOpen(1, 3)); ok {
+		t.Error("[1, 3) is found after deletion")
+	}
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOverlappingBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		stored   interval.Interval[int]
+		query    interval.Interval[int]
+		expected bool
+	}{
+		{name: "nested", stored: interval.Closed(1, 10), query: interval.Closed(3, 4), expected: true},
+		{name: "closed ends touch", stored: interval.Closed(1, 3), query: interval.Closed(3, 5), expected: true},
+		{name: "booking ends as next starts", stored: interval.ClosedOpen(1, 3), query: interval.ClosedOpen(3, 5), expected: false},
+		{name: "open start at closed end", stored: interval.Closed(1, 3), query: interval.OpenClosed(3, 5), expected: false},
+		{name: "closed start at open end", stored: interval.ClosedOpen(1, 3), query: interval.Closed(3, 5), expected: false},
+		{name: "query before", stored: interval.Closed(5, 7), query: interval.Closed(1, 4), expected: false},
+		{name: "query after", stored: interval.Closed(5, 7), query: interval.Closed(8, 9), expected: false},
+		{name: "point on open bound", stored: interval.Open(1, 3), query: interval.Closed(3, 3), expected: false},
+		{name: "point inside open interval", stored: interval.Open(1, 3), query: interval.Closed(2, 2), expected: true},
+	}
+
+	for _, tt := range tests {
+		sut := interval.New[int, string]()
+		mustInsert(t, sut, tt.stored, tt.name)
+
+		var got bool
+		for range sut.Overlapping(tt.query) {
+			got = true
+		}
+
+		if got != tt.expected {
+			t.Errorf("%s: Overlapping(%v) with %v stored: expected %v, got %v", tt.name, tt.query, tt.stored, tt.expected, got)
+		}
+
+		if overlaps := sut.Overlaps(tt.query); overlaps != tt.expected {
+			t.Errorf("%s: Overlaps(%v) with %v stored: expected %v, got %v", tt.name, tt.query, tt.stored, tt.expected, overlaps)
+		}
+	}
+}
+
+func TestStabbing(t *testing.T) {
+	t.Parallel()
+
+	sut := interval.New[int, string]()
+	mustInsert(t, sut, interval.ClosedOpen(0, 10), "morning")
+	mustInsert(t, sut, interval.Closed(5, 15), "lunch")
+	mustInsert(t, sut, interval.OpenClosed(10, 20), "evening")
+
+	tests := []struct {
+		point    int
+		expected []string
+	}{
+		{point: -1, expected: nil},
+		{point: 0, expected: []string{"morning"}},
+		{point: 7, expected: []string{"morning", "lunch"}},
+		{point: 10, expected: []string{"lunch"}},
+		{point: 12, expected: []string{"lunch", "evening"}},
+		{point: 20, expected: []string{"evening"}},
+		{point: 21, expected: nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, val := range sut.Stabbing(tt.point) {
+			got = append(got, val)
+		}
+
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Stabbing(%d): expected %v, got %v", tt.point, tt.expected, got)
+		}
+	}
+}
+
+func TestMergeAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    []interval.Interval[int]
+		expected []interval.Interval[int]
+	}{
+		{name: "empty", input: nil, expected: nil},
+		{
+			// leetcode 57: insert [4, 8] into [[1, 2], [3, 5], [6, 7], [8, 10], [12, 16]].
+			name: "leetcode",
+			input: []interval.Interval[int]{
+				interval.Closed(1, 2), interval.Closed(3, 5), interval.Closed(6, 7),
+				interval.Closed(8, 10), interval.Closed(12, 16), interval.Closed(4, 8),
+			},
+			expected: []interval.Interval[int]{interval.Closed(1, 2), interval.Closed(3, 10), interval.Closed(12, 16)},
+		},
+		{
+			name:     "touching at an included endpoint",
+			input:    []interval.Interval[int]{interval.ClosedOpen(1, 3), interval.ClosedOpen(3, 5)},
+			expected: []interval.Interval[int]{interval.ClosedOpen(1, 5)},
+		},
+		{
+			name:     "touching at an excluded endpoint",
+			input:    []interval.Interval[int]{interval.ClosedOpen(1, 3), interval.OpenClosed(3, 5)},
+			expected: []interval.Interval[int]{interval.ClosedOpen(1, 3), interval.OpenClosed(3, 5)},
+		},
+		{
+			name:     "nested keeps the outer bound",
+			input:    []interval.Interval[int]{interval.Closed(1, 10), interval.Open(2, 10), interval.Closed(3, 4)},
+			expected: []interval.Interval[int]{interval.Closed(1, 10)},
+		},
+		{
+			name:     "closed end wins at the same endpoint",
+			input:    []interval.Interval[int]{interval.ClosedOpen(1, 5), interval.Closed(2, 5)},
+			expected: []interval.Interval[int]{interval.Closed(1, 5)},
+		},
+	}
+
+	for _, tt := range tests {
+		sut := interval.New[int, struct{}]()
+		for _, iv := range tt.input {
+			mustInsert(t, sut, iv, struct{}{})
+		}
+
+		if got := sut.MergeAll(); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestBookingCalendar(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	slot := func(from, to int) interval.Interval[time.Time] {
+		return interval.ClosedOpen(day.Add(time.Duration(from)*time.Hour), day.Add(time.Duration(to)*time.Hour))
+	}
+
+	calendar := interval.NewFunc[time.Time, string](time.Time.Compare)
+
+	for _, booking := range []struct {
+		slot interval.Interval[time.Time]
+		name string
+	}{
+		{slot(9, 10), "standup"},
+		{slot(10, 12), "review"},
+		{slot(14, 15), "interview"},
+	} {
+		if calendar.Overlaps(booking.slot) {
+			t.Fatalf("%s: slot %v is taken", booking.name, booking.slot)
+		}
+
+		mustInsert(t, calendar, booking.slot, booking.name)
+	}
+
+	if !calendar.Overlaps(slot(11, 13)) {
+		t.Error("11:00-13:00 overlaps the review")
+	}
+
+	if calendar.Overlaps(slot(12, 14)) {
+		t.Error("12:00-14:00 is free")
+	}
+
+	var busy []string
+	for _, name := range calendar.Stabbing(day.Add(10 * time.Hour)) {
+		busy = append(busy, name)
+	}
+
+	if expected := []string{"review"}; !reflect.DeepEqual(busy, expected) {
+		t.Errorf("at 10:00 expected %v, got %v", expected, busy)
+	}
+}
+
+func TestRandomAgainstModel(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(18))
+	randomInterval := func() interval.Interval[int] {
+		lo := rnd.Intn(200)
+		iv := interval.Closed(lo, lo+rnd.Intn(30))
+		iv.LoOpen, iv.HiOpen = rnd.Intn(2) == 0, rnd.Intn(2) == 0
+
+		if iv.Lo == iv.Hi {
+			iv.LoOpen, iv.HiOpen = false, false
+		}
+
+		return iv
+	}
+
+	sut := interval.New[int, int]()
+	model := map[interval.Interval[int]]int{}
+
+	for i := 0; i < 5000; i++ {
+		iv := randomInterval()
+
+		if rnd.Intn(3) == 0 {
+			_, found := model[iv]
+			if deleted := sut.Delete(iv); deleted != found {
+				t.Fatalf("step %d: Delete(%v) = %v, expected %v", i, iv, deleted, found)
+			}
+
+			delete(model, iv)
+		} else {
+			mustInsert(t, sut, iv, i)
+			model[iv] = i
+		}
+
+		if i%250 == 0 {
+			if err := sut.CheckInvariants(); err != nil {
+				t.Fatalf("step %d: %v", i, err)
+			}
+		}
+
+		q := randomInterval()
+
+		var got []int
+		for _, val := range sut.Overlapping(q) {
+			got = append(got, val)
+		}
+
+		expected := naiveOverlapping(model, q)
+		slices.Sort(got)
+
+		if !slices.Equal(got, expected) {
+			t.Fatalf("step %d: Overlapping(%v): expected %v, got %v", i, q, expected, got)
+		}
+
+		if overlaps := sut.Overlaps(q); overlaps != (len(expected) > 0) {
+			t.Fatalf("step %d: Overlaps(%v) = %v, expected %v", i, q, overlaps, len(expected) > 0)
+		}
+
+		if sut.Size() != uint(len(model)) {
+			t.Fatalf("step %d: expected size %d, got %d", i, len(model), sut.Size())
+		}
+	}
+
+	checkMergeAll(t, sut, model)
+}
+
+// naiveOverlapping returns sorted values of intervals sharing a point with q by checking
+// integer and half-integer points, which distinguish open bounds of integer endpoints.
+func naiveOverlapping(model map[interval.Interval[int]]int, q interval.Interval[int]) []int {
+	var result []int
+
+	for iv, val := range model {
+		for p := 2 * max(iv.Lo, q.Lo); p <= 2*min(iv.Hi, q.Hi); p++ {
+			if contains(iv, p) && contains(q, p) {
+				result = append(result, val)
+				break
+			}
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// contains reports whether iv contains the point p/2.
+func contains(iv interval.Interval[int], p int) bool {
+	lo, hi := 2*iv.Lo, 2*iv.Hi
+
+	return (p > lo || p == lo && !iv.LoOpen) && (p < hi || p == hi && !iv.HiOpen)
+}
+
+// checkMergeAll verifies that merged intervals are disjoint and cover exactly the points of the model.
+func checkMergeAll(t *testing.T, sut *interval.Tree[int, int], model map[interval.Interval[int]]int) {
+	t.Helper()
+
+	merged := sut.MergeAll()
+
+	for p := -2; p <= 2*240; p++ {
+		var covered, inMerged int
+
+		for iv := range model {
+			if contains(iv, p) {
+				covered++
+			}
+		}
+
+		for _, iv := range merged {
+			if contains(iv, p) {
+				inMerged++
+			}
+		}
+
+		if inMerged > 1 || (covered > 0) != (inMerged > 0) {
+			t.Fatalf("point %d/2 is covered by %d intervals, but by %d merged ones", p, covered, inMerged)
+		}
+	}
+
+	for i := 1; i < len(merged); i++ {
+		prev, cur := merged[i-1], merged[i]
+		if cur.Lo < prev.Hi || cur.Lo == prev.Hi && (!cur.LoOpen || !prev.HiOpen) {
+			t.Fatalf("%v and %v are not merged", prev, cur)
+		}
+	}
+}
+
+func mustInsert[E, V any](t *testing.T, sut *interval.Tree[E, V], iv interval.Interval[E], val V) {
+	t.Helper()
+
+	if err := sut.Insert(iv, val); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkOverlapping(b *testing.B) {
+	const size = 100_000
+
+	rnd := rand.New(rand.NewSource(1))
+	sut := interval.New[int, int]()
+
+	for i := range size {
+		lo := rnd.Intn(10 * size)
+		_ = sut.Insert(interval.ClosedOpen(lo, lo+1+rnd.Intn(100)), i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		lo := rnd.Intn(10 * size)
+		for range sut.Overlapping(interval.ClosedOpen(lo, lo+50)) {
+		}
+	}
+}
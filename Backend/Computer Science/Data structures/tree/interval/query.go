@@ -0,0 +1,96 @@
+package interval
+
+import (
+	"iter"
+)
+
+// overlapping returns an iterator over entries overlapping q in ascending order.
+// Subtrees reaching not far enough to q are skipped, and iteration stops
+// at the first interval starting after q ends.
+func (t *Tree[E, V]) overlapping(q Interval[E]) iter.Seq[entry[E, V]] {
+	return func(yield func(entry[E, V]) bool) {
+		candidates := t.tree.Search(
+			func(e entry[E, V]) bool { return startsBeforeEnd(t.compare, q, e.reach) },
+			func(e entry[E, V]) bool { return startsBeforeEnd(t.compare, e.iv, q) },
+		)
+
+		for e := range candidates {
+			if startsBeforeEnd(t.compare, q, e.iv) && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Overlapping returns an iterator over intervals sharing at least one point with q
+// together with their values, in ascending order of lower bounds.
+// As for tree.Tree, the loop body is free to modify the tree.
+// Asymptotic: O(min(n, k log n)) for k overlapping intervals.
+func (t *Tree[E, V]) Overlapping(q Interval[E]) iter.Seq2[Interval[E], V] {
+	return func(yield func(Interval[E], V) bool) {
+		for e := range t.overlapping(q) {
+			if !yield(e.iv, e.val) {
+				return
+			}
+		}
+	}
+}
+
+// Stabbing returns an iterator over intervals containing point together with their values,
+// in ascending order of lower bounds.
+// Asymptotic: O(min(n, k log n)) for k found intervals.
+func (t *Tree[E, V]) Stabbing(point E) iter.Seq2[Interval[E], V] {
+	return t.Overlapping(Closed(point, point))
+}
+
+// Overlaps reports whether any interval of the tree shares a point with q,
+// e.g. whether a time slot is already booked. Every interval visited before
+// the first overlapping one ends before q, while some interval of its right subtree
+// reaches q, so the search goes down a single path.
+// Asymptotic: O(log n)
+func (t *Tree[E, V]) Overlaps(q Interval[E]) bool {
+	for range t.overlapping(q) {
+		return true
+	}
+
+	return false
+}
+
+// All returns an iterator over all intervals with their values in ascending order of lower bounds.
+// Asymptotic: O(n) for the full iteration.
+func (t *Tree[E, V]) All() iter.Seq2[Interval[E], V] {
+	return func(yield func(Interval[E], V) bool) {
+		for e := range t.tree.All() {
+			if !yield(e.iv, e.val) {
+				return
+			}
+		}
+	}
+}
+
+// MergeAll returns the union of all intervals of the tree as the sorted list
+// of disjoint intervals: overlapping ones and those touching at an endpoint
+// included by either of them are merged, so [1, 3) and [3, 5] make [1, 5],
+// while [1, 3) and (3, 5] stay apart.
+// Asymptotic: O(n)
+func (t *Tree[E, V]) MergeAll() []Interval[E] {
+	var merged []Interval[E]
+
+	for _, e := range t.tree.SortedAsc() {
+		if len(merged) == 0 {
+			merged = append(merged, e.iv)
+			continue
+		}
+
+		last := &merged[len(merged)-1]
+
+		switch {
+		case !joins(t.compare, *last, e.iv):
+			merged = append(merged, e.iv)
+		case compareHi(t.compare, e.iv, *last) > 0:
+			last.Hi, last.HiOpen = e.iv.Hi, e.iv.HiOpen
+		}
+	}
+
+	return merged
+}
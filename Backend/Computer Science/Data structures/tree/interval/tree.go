@@ -0,0 +1,126 @@
+package interval
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+var (
+	// ErrEmpty is wrapped by errors of Insert of an interval holding no point.
+	ErrEmpty = errors.New("interval holds no point")
+	// ErrInvariant is wrapped by CheckInvariants errors, which also wrap tree.ErrInvariant
+	// when the underlying tree is broken rather than an interval is empty.
+	ErrInvariant = errors.New("interval tree invariant violated")
+)
+
+type (
+	// entry is an interval with its value, ordered by the interval only.
+	entry[E, V any] struct {
+		iv  Interval[E]
+		val V
+		// reach is the interval with the greatest upper bound in the subtree rooted at the entry.
+		reach Interval[E]
+	}
+
+	// Tree maps distinct intervals to values and answers which of them overlap
+	// a given interval or contain a given point. It is an augmented tree.Tree,
+	// so it shares its balancing, locking and iteration semantics.
+	// Use New or NewFunc to create a tree.
+	Tree[E, V any] struct {
+		compare func(a, b E) int // orders endpoints.
+		tree    *tree.Tree[entry[E, V]]
+	}
+)
+
+// New creates an empty interval tree with naturally ordered endpoints.
+func New[E cmp.Ordered, V any]() *Tree[E, V] {
+	return NewFunc[E, V](cmp.Compare[E])
+}
+
+// NewFunc creates an empty interval tree with endpoints ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+// For example, time.Time.Compare orders time slots of a booking calendar.
+func NewFunc[E, V any](compare func(a, b E) int) *Tree[E, V] {
+	t := &Tree[E, V]{compare: compare}
+	t.tree = tree.NewFunc(
+		func(a, b entry[E, V]) int { return order(compare, a.iv, b.iv) },
+		tree.WithAugment(t.augment),
+	)
+
+	return t
+}
+
+// augment recalculates reach of e from reaches of its children and reports whether it has changed.
+func (t *Tree[E, V]) augment(e, left, right *entry[E, V]) bool {
+	reach := e.iv
+
+	for _, child := range [...]*entry[E, V]{left, right} {
+		if child != nil && compareHi(t.compare, child.reach, reach) > 0 {
+			reach = child.reach
+		}
+	}
+
+	changed := compareHi(t.compare, e.reach, reach) != 0
+	e.reach = reach
+
+	return changed
+}
+
+// Insert associates val with iv, replacing the previous value of the same interval if any.
+// It returns ErrEmpty for an interval holding no point, such as [2, 1] or [1, 1).
+// Asymptotic: O(log n)
+func (t *Tree[E, V]) Insert(iv Interval[E], val V) error {
+	if empty(t.compare, iv) {
+		return fmt.Errorf("insert %v: %w", iv, ErrEmpty)
+	}
+
+	t.tree.Put(entry[E, V]{iv: iv, val: val})
+
+	return nil
+}
+
+// Delete removes iv from the tree and reports whether it was present.
+// Only an interval equal to iv, including its bounds, is removed.
+// Asymptotic: O(log n)
+func (t *Tree[E, V]) Delete(iv Interval[E]) bool {
+	return t.tree.Delete(entry[E, V]{iv: iv})
+}
+
+// Get returns the value associated with iv if presented.
+// Asymptotic: O(log n)
+func (t *Tree[E, V]) Get(iv Interval[E]) (V, bool) {
+	if e, ok := t.tree.Floor(entry[E, V]{iv: iv}); ok && order(t.compare, e.iv, iv) == 0 {
+		return e.val, true
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// Size returns count of intervals in the tree.
+// Asymptotic: O(1)
+func (t *Tree[E, V]) Size() uint {
+	return t.tree.Size()
+}
+
+// CheckInvariants verifies ordering of the intervals, cached heights and reaches,
+// AVL balance of every node and the tree size, and that no interval is empty.
+// It is intended to be called from tests.
+// Asymptotic: O(n)
+func (t *Tree[E, V]) CheckInvariants() error {
+	if err := t.tree.CheckInvariants(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvariant, err)
+	}
+
+	for _, e := range t.tree.SortedAsc() {
+		if empty(t.compare, e.iv) {
+			return fmt.Errorf("%w: %v is empty", ErrInvariant, e.iv)
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,26 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 
 	"github.com/dzianismaroz/marathon/tree/tree"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		if err := render(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "render:", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	t := tree.New[int]()
 
 	t.Add(44)
@@ -22,3 +36,46 @@ func main() {
 	fmt.Println(t.SortedAsc())
 
 }
+
+// render builds a tree from whitespace separated numbers read from in
+// and draws its structure to out:
+//
+//	echo 44 18 1 2 10 8 | go run . render [-dot] [-multiset]
+func render(args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	dot := flags.Bool("dot", false, "write Graphviz DOT instead of ASCII drawing")
+	multiset := flags.Bool("multiset", false, "keep duplicated numbers")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var opts []tree.Option
+	if *multiset {
+		opts = append(opts, tree.Multiset())
+	}
+
+	t := tree.New[float64](opts...)
+
+	scanner := bufio.NewScanner(in)
+	scanner.Split(bufio.ScanWords)
+
+	for scanner.Scan() {
+		num, err := strconv.ParseFloat(scanner.Text(), 64)
+		if err != nil {
+			return err
+		}
+
+		t.Add(num)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if *dot {
+		return t.WriteDOT(out)
+	}
+
+	return t.WriteASCII(out)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ascii",
+			in:   "3 1\n2",
+			want: "2 h=2 size=3\n├── L: 1 h=1 size=1\n└── R: 3 h=1 size=1\n",
+		},
+		{
+			name: "multiset",
+			args: []string{"-multiset"},
+			in:   "1.5 1.5",
+			want: "1.5 ×2 h=1 size=2\n",
+		},
+		{
+			name: "dot",
+			args: []string{"-dot"},
+			in:   "7",
+			want: "digraph tree {\n\tnode [shape=box, fontname=monospace];\n\tn0 [label=\"7 h=1 size=1\"];\n}\n",
+		},
+		{
+			name:    "not a number",
+			in:      "1 two",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out strings.Builder
+
+			err := render(tc.args, strings.NewReader(tc.in), &out)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("%s failed. err=%v, wantErr=%v", tc.name, err, tc.wantErr)
+			}
+
+			if got := out.String(); !tc.wantErr && got != tc.want {
+				t.Errorf("%s failed. got=%q, want=%q", tc.name, got, tc.want)
+			}
+		})
+	}
+}
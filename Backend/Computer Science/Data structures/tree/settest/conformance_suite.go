@@ -0,0 +1,393 @@
+// Package settest provides the conformance suite for implementations of tree.OrderedSet.
+package settest
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+// checker is implemented by sets able to verify their internal invariants.
+type checker interface {
+	CheckInvariants() error
+}
+
+// Run verifies that sets created by newSet behave as an ordered set of distinct integers:
+// every operation is compared against a sorted slice. newSet must return an empty set.
+func Run(t *testing.T, newSet func() tree.OrderedSet[int]) {
+	t.Helper()
+
+	t.Run("basic operations", func(t *testing.T) {
+		t.Parallel()
+		testBasic(t, newSet())
+	})
+
+	t.Run("empty set", func(t *testing.T) {
+		t.Parallel()
+		testEmpty(t, newSet())
+	})
+
+	t.Run("iterators", func(t *testing.T) {
+		t.Parallel()
+		testIterators(t, newSet)
+	})
+
+	t.Run("modification while iterating", func(t *testing.T) {
+		t.Parallel()
+		testIterationWithModification(t, newSet)
+	})
+
+	t.Run("sequential insertion and deletion", func(t *testing.T) {
+		t.Parallel()
+		testSequential(t, newSet())
+	})
+
+	t.Run("random operations", func(t *testing.T) {
+		t.Parallel()
+		testRandom(t, newSet())
+	})
+}
+
+func check(t *testing.T, set tree.OrderedSet[int]) {
+	t.Helper()
+
+	if c, ok := set.(checker); ok {
+		if err := c.CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testBasic(t *testing.T, set tree.OrderedSet[int]) {
+	for _, v := range []int{44, 18, 1, 2, 10, 8, 18, 1} {
+		set.Add(v)
+		check(t, set)
+	}
+
+	if got, expected := set.SortedAsc(), []int{1, 2, 8, 10, 18, 44}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("SortedAsc: expected %v, got %v", expected, got)
+	}
+
+	if got, expected := set.SortedDesc(), []int{44, 18, 10, 8, 2, 1}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("SortedDesc: expected %v, got %v", expected, got)
+	}
+
+	if size := set.Size(); size != 6 {
+		t.Errorf("expected size 6, got %d", size)
+	}
+
+	tests := []struct {
+		name     string
+		query    func(elem int) (int, bool)
+		elem     int
+		expected int
+		ok       bool
+	}{
+		{"Floor of present element", set.Floor, 8, 8, true},
+		{"Floor of absent element", set.Floor, 9, 8, true},
+		{"Floor below min", set.Floor, 0, 0, false},
+		{"Ceiling of present element", set.Ceiling, 10, 10, true},
+		{"Ceiling of absent element", set.Ceiling, 11, 18, true},
+		{"Ceiling above max", set.Ceiling, 45, 0, false},
+		{"Predecessor of present element", set.Predecessor, 8, 2, true},
+		{"Predecessor of min", set.Predecessor, 1, 0, false},
+		{"Successor of present element", set.Successor, 18, 44, true},
+		{"Successor of absent element", set.Successor, 19, 44, true},
+		{"Successor of max", set.Successor, 44, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := tt.query(tt.elem); got != tt.expected || ok != tt.ok {
+			t.Errorf("%s %d: expected %d, %v, got %d, %v", tt.name, tt.elem, tt.expected, tt.ok, got, ok)
+		}
+	}
+
+	if v, ok := set.Min(); v != 1 || !ok {
+		t.Errorf("expected min 1, got %d, %v", v, ok)
+	}
+
+	if v, ok := set.Max(); v != 44 || !ok {
+		t.Errorf("expected max 44, got %d, %v", v, ok)
+	}
+
+	if !set.Contains(10) || set.Contains(11) {
+		t.Error("Contains reports wrong membership")
+	}
+
+	if !set.Delete(10) || set.Delete(10) || set.Delete(11) {
+		t.Error("Delete reports wrong presence")
+	}
+
+	check(t, set)
+
+	if got, expected := set.SortedAsc(), []int{1, 2, 8, 18, 44}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("after Delete: expected %v, got %v", expected, got)
+	}
+}
+
+func testEmpty(t *testing.T, set tree.OrderedSet[int]) {
+	if set.Size() != 0 || set.Contains(0) || set.Delete(0) || set.SortedAsc() != nil {
+		t.Error("empty set is not empty")
+	}
+
+	for name, query := range map[string]func() (int, bool){
+		"Min":         set.Min,
+		"Max":         set.Max,
+		"Floor":       func() (int, bool) { return set.Floor(1) },
+		"Ceiling":     func() (int, bool) { return set.Ceiling(1) },
+		"Predecessor": func() (int, bool) { return set.Predecessor(1) },
+		"Successor":   func() (int, bool) { return set.Successor(1) },
+	} {
+		if v, ok := query(); v != 0 || ok {
+			t.Errorf("%s of empty set: expected 0, false, got %d, %v", name, v, ok)
+		}
+	}
+
+	if got := slices.Collect(set.All()); got != nil {
+		t.Errorf("expected no elements, got %v", got)
+	}
+
+	set.Add(1)
+	set.Delete(1)
+
+	if set.Size() != 0 {
+		t.Errorf("expected empty set after deleting the only element, got size %d", set.Size())
+	}
+
+	check(t, set)
+}
+
+func testIterators(t *testing.T, newSet func() tree.OrderedSet[int]) {
+	set := newSet()
+	model := make([]int, 0, 500)
+
+	for v := range 500 {
+		set.Add(v * 3)
+		model = append(model, v*3)
+	}
+
+	for _, from := range []int{-1, 0, 1, 3, 700, 1497, 1498, 2000} {
+		lo, _ := slices.BinarySearch(model, from)
+		hi, found := slices.BinarySearch(model, from)
+
+		if found {
+			hi++
+		}
+
+		ascending := model[lo:]
+		descending := slices.Clone(model[:hi])
+		slices.Reverse(descending)
+
+		if got := slices.Collect(set.Ascend(from)); !equal(got, ascending) {
+			t.Errorf("Ascend(%d): expected %v, got %v", from, ascending, got)
+		}
+
+		if got := slices.Collect(set.Descend(from)); !equal(got, descending) {
+			t.Errorf("Descend(%d): expected %v, got %v", from, descending, got)
+		}
+
+		end, _ := slices.BinarySearch(model, from+100)
+		if got := slices.Collect(set.Range(from, from+100)); !equal(got, model[lo:end]) {
+			t.Errorf("Range(%d, %d): expected %v, got %v", from, from+100, model[lo:end], got)
+		}
+	}
+
+	if got := slices.Collect(set.All()); !equal(got, model) {
+		t.Errorf("All: expected %v, got %v", model, got)
+	}
+
+	backward := slices.Clone(model)
+	slices.Reverse(backward)
+
+	if got := slices.Collect(set.Backward()); !equal(got, backward) {
+		t.Errorf("Backward: expected %v, got %v", backward, got)
+	}
+
+	var got []int
+
+	for v := range set.All() {
+		if got = append(got, v); len(got) == 3 {
+			break
+		}
+	}
+
+	if expected := []int{0, 3, 6}; !equal(got, expected) {
+		t.Errorf("break: expected %v, got %v", expected, got)
+	}
+}
+
+func testIterationWithModification(t *testing.T, newSet func() tree.OrderedSet[int]) {
+	tests := []struct {
+		name     string
+		body     func(set tree.OrderedSet[int], v int)
+		expected []int
+		after    []int
+	}{
+		{
+			name: "delete every yielded element",
+			body: func(set tree.OrderedSet[int], v int) {
+				set.Delete(v)
+			},
+			expected: between(0, 100),
+			after:    nil,
+		},
+		{
+			name: "delete upcoming elements",
+			body: func(set tree.OrderedSet[int], v int) {
+				set.Delete(v + 1)
+			},
+			expected: evens(0, 100),
+			after:    evens(0, 100),
+		},
+		{
+			name: "add upcoming and past elements",
+			body: func(set tree.OrderedSet[int], v int) {
+				set.Add(v - 1000)
+				if v < 100 {
+					set.Add(v + 100)
+				}
+			},
+			expected: between(0, 200),
+			after:    append(between(-1000, -800), between(0, 200)...),
+		},
+	}
+
+	for _, tt := range tests {
+		set := newSet()
+		for v := range 100 {
+			set.Add(v)
+		}
+
+		var got []int
+
+		for v := range set.All() {
+			got = append(got, v)
+			tt.body(set, v)
+		}
+
+		if !equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+
+		if after := set.SortedAsc(); !equal(after, tt.after) {
+			t.Errorf("%s: expected set %v after iteration, got %v", tt.name, tt.after, after)
+		}
+
+		check(t, set)
+	}
+}
+
+func testSequential(t *testing.T, set tree.OrderedSet[int]) {
+	const n = 10_000
+
+	for v := range n {
+		set.Add(v)
+	}
+
+	for v := n - 1; v >= 0; v -= 2 {
+		if !set.Delete(v) {
+			t.Fatalf("%d is not deleted", v)
+		}
+	}
+
+	check(t, set)
+
+	if got := set.SortedAsc(); !equal(got, evens(0, n)) {
+		t.Errorf("expected evens below %d, got %v", n, got)
+	}
+
+	for v := 0; v < n; v += 2 {
+		set.Delete(v)
+	}
+
+	if set.Size() != 0 {
+		t.Errorf("expected empty set, got size %d", set.Size())
+	}
+
+	check(t, set)
+}
+
+func testRandom(t *testing.T, set tree.OrderedSet[int]) {
+	rnd := rand.New(rand.NewSource(16))
+
+	var model []int
+
+	for i := 0; i < 20_000; i++ {
+		v := rnd.Intn(1000)
+		idx, found := slices.BinarySearch(model, v)
+
+		if rnd.Intn(3) == 0 {
+			if deleted := set.Delete(v); deleted != found {
+				t.Fatalf("step %d: Delete(%d) = %v, expected %v", i, v, deleted, found)
+			}
+
+			if found {
+				model = slices.Delete(model, idx, idx+1)
+			}
+		} else {
+			set.Add(v)
+
+			if !found {
+				model = slices.Insert(model, idx, v)
+			}
+		}
+
+		if i%1000 == 0 {
+			check(t, set)
+		}
+
+		floor, floorOK := set.Floor(v)
+		expectedFloor, expectedFloorOK := modelFloor(model, v)
+
+		if floor != expectedFloor || floorOK != expectedFloorOK {
+			t.Fatalf("step %d: Floor(%d) = %d, %v, expected %d, %v", i, v, floor, floorOK, expectedFloor, expectedFloorOK)
+		}
+
+		if set.Size() != uint(len(model)) {
+			t.Fatalf("step %d: expected size %d, got %d", i, len(model), set.Size())
+		}
+	}
+
+	check(t, set)
+
+	if got := set.SortedAsc(); !equal(got, model) {
+		t.Errorf("expected %v, got %v", model, got)
+	}
+}
+
+// modelFloor returns the greatest element of sorted less than or equal to v.
+func modelFloor(sorted []int, v int) (int, bool) {
+	idx, found := slices.BinarySearch(sorted, v)
+	if found {
+		return v, true
+	}
+
+	if idx == 0 {
+		return 0, false
+	}
+
+	return sorted[idx-1], true
+}
+
+// equal compares slices treating nil and empty ones as equal.
+func equal(a, b []int) bool {
+	return len(a) == 0 && len(b) == 0 || reflect.DeepEqual(a, b)
+}
+
+// between returns integers of the half-open interval [lo, hi).
+func between(lo, hi int) []int {
+	result := make([]int, 0, hi-lo)
+	for i := lo; i < hi; i++ {
+		result = append(result, i)
+	}
+
+	return result
+}
+
+// evens returns even integers of the half-open interval [lo, hi).
+func evens(lo, hi int) []int {
+	return slices.DeleteFunc(between(lo, hi), func(v int) bool { return v%2 != 0 })
+}
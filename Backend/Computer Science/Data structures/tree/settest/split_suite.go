@@ -0,0 +1,233 @@
+package settest
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+// Splitter is an ordered set able to split by key and concatenate with another set of the same kind.
+type Splitter[S any] interface {
+	tree.OrderedSet[int]
+
+	// Split moves elements greater than or equal to key into a new set and returns it.
+	Split(key int) S
+	// Merge moves all elements of other, which must be greater than all elements of the set, to the set.
+	Merge(other S) error
+}
+
+// RunSplit verifies Split and Merge of sets created by newSet, which must return an empty set.
+func RunSplit[S Splitter[S]](t *testing.T, newSet func() S) {
+	t.Helper()
+
+	filled := func(elems []int) S {
+		set := newSet()
+		for _, v := range elems {
+			set.Add(v)
+		}
+
+		return set
+	}
+
+	t.Run("split", func(t *testing.T) {
+		t.Parallel()
+		testSplit(t, filled)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		t.Parallel()
+		testMerge(t, newSet, filled)
+	})
+
+	t.Run("move range to another shard", func(t *testing.T) {
+		t.Parallel()
+		testMoveRange(t, newSet, filled)
+	})
+
+	t.Run("random splits and merges", func(t *testing.T) {
+		t.Parallel()
+		testRandomSplitMerge(t, filled)
+	})
+
+	t.Run("concurrent merges into each other", func(t *testing.T) {
+		t.Parallel()
+		testCrossMerge(t, filled)
+	})
+}
+
+func expectElems(t *testing.T, name string, set tree.OrderedSet[int], expected []int) {
+	t.Helper()
+	check(t, set)
+
+	if got := set.SortedAsc(); !equal(got, expected) {
+		t.Errorf("%s: expected %v, got %v", name, expected, got)
+	}
+
+	if set.Size() != uint(len(expected)) {
+		t.Errorf("%s: expected size %d, got %d", name, len(expected), set.Size())
+	}
+}
+
+func testSplit[S Splitter[S]](t *testing.T, filled func([]int) S) {
+	tests := []struct {
+		name string
+		key  int
+	}{
+		{name: "below the least element", key: -5},
+		{name: "at the least element", key: 0},
+		{name: "at a present element", key: 40},
+		{name: "at an absent element", key: 41},
+		{name: "at the greatest element", key: 98},
+		{name: "above the greatest element", key: 150},
+	}
+
+	for _, tt := range tests {
+		set := filled(evens(0, 100))
+		rest := set.Split(tt.key)
+
+		expectElems(t, tt.name+": kept", set, evens(0, min(max(tt.key, 0), 100)))
+		expectElems(t, tt.name+": split off", rest, evens(min(max(tt.key, 0), 100), 100))
+
+		// Both sets stay independent after splitting.
+		set.Add(tt.key - 1)
+		rest.Add(tt.key + 1)
+
+		if set.Contains(tt.key+1) || rest.Contains(tt.key-1) {
+			t.Errorf("%s: sets share elements after splitting", tt.name)
+		}
+
+		check(t, set)
+		check(t, rest)
+	}
+}
+
+func testMerge[S Splitter[S]](t *testing.T, newSet func() S, filled func([]int) S) {
+	set, other := filled(between(0, 50)), filled(between(50, 100))
+	if err := set.Merge(other); err != nil {
+		t.Fatalf("merging adjacent sets: %v", err)
+	}
+
+	expectElems(t, "merged", set, between(0, 100))
+	expectElems(t, "merged other", other, nil)
+
+	overlapping := filled([]int{99, 150})
+	if err := set.Merge(overlapping); err == nil {
+		t.Error("expected an error merging overlapping sets")
+	}
+
+	expectElems(t, "after failed merge", set, between(0, 100))
+	expectElems(t, "other after failed merge", overlapping, []int{99, 150})
+
+	if err := set.Merge(set); err == nil {
+		t.Error("expected an error merging a set into itself")
+	}
+
+	if err := set.Merge(newSet()); err != nil {
+		t.Errorf("merging an empty set: %v", err)
+	}
+
+	empty := newSet()
+	if err := empty.Merge(set); err != nil {
+		t.Errorf("merging into an empty set: %v", err)
+	}
+
+	expectElems(t, "merged into empty", empty, between(0, 100))
+	expectElems(t, "merged into empty source", set, nil)
+
+	if err := set.Merge(set); err != nil {
+		t.Errorf("merging an empty set into itself: %v", err)
+	}
+}
+
+func testMoveRange[S Splitter[S]](t *testing.T, newSet func() S, filled func([]int) S) {
+	const lo, hi = 300, 700
+
+	shard := filled(between(0, 1000))
+	target := newSet()
+
+	// Cut [lo, hi) out of the shard and glue the ends back together.
+	moved := shard.Split(lo)
+	tail := moved.Split(hi)
+
+	if err := shard.Merge(tail); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := target.Merge(moved); err != nil {
+		t.Fatal(err)
+	}
+
+	expectElems(t, "shard", shard, append(between(0, lo), between(hi, 1000)...))
+	expectElems(t, "target", target, between(lo, hi))
+}
+
+func testRandomSplitMerge[S Splitter[S]](t *testing.T, filled func([]int) S) {
+	const n = 2000
+
+	rnd := rand.New(rand.NewSource(17))
+	set := filled(rnd.Perm(n))
+
+	for i := 0; i < 500; i++ {
+		key := rnd.Intn(n+20) - 10
+		rest := set.Split(key)
+
+		if got, ok := set.Max(); ok && got >= key {
+			t.Fatalf("step %d: Split(%d) kept %d", i, key, got)
+		}
+
+		if got, ok := rest.Min(); ok && got < key {
+			t.Fatalf("step %d: Split(%d) moved %d", i, key, got)
+		}
+
+		if set.Size()+rest.Size() != n {
+			t.Fatalf("step %d: Split(%d) lost elements: %d + %d", i, key, set.Size(), rest.Size())
+		}
+
+		if err := set.Merge(rest); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+
+		if i%50 == 0 {
+			check(t, set)
+		}
+	}
+
+	expectElems(t, "after splits and merges", set, between(0, n))
+}
+
+func testCrossMerge[S Splitter[S]](t *testing.T, filled func([]int) S) {
+	for i := 0; i < 500; i++ {
+		a, b := filled(between(0, 50)), filled(between(50, 100))
+
+		// Whichever goes first, one set ends up with all elements and the other one empty.
+		var wg sync.WaitGroup
+
+		start := make(chan struct{})
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			<-start
+			_ = a.Merge(b)
+		}()
+
+		go func() {
+			defer wg.Done()
+			<-start
+			_ = b.Merge(a)
+		}()
+
+		close(start)
+		wg.Wait()
+
+		if a.Size() == 0 {
+			a, b = b, a
+		}
+
+		expectElems(t, "merged", a, between(0, 100))
+		expectElems(t, "merged other", b, nil)
+	}
+}
@@ -0,0 +1,322 @@
+package skiplist
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	cnode[T any] struct {
+		val  T
+		next []atomic.Pointer[cnode[T]] // successors on every level the node is promoted to.
+		mu   sync.Mutex                 // guards links to successors of the node.
+		// marked is set once the node is logically deleted, before it is unlinked.
+		marked atomic.Bool
+		// linked is set once the node is linked on all its levels, which makes it present.
+		linked atomic.Bool
+	}
+
+	// Concurrent is a lazy skip list (Herlihy, Lev, Luchangco, Shavit): an ordered set
+	// of distinct elements where Add and Delete lock only the predecessors of the changed
+	// element on its levels, so writers of different parts of the list do not contend,
+	// while Contains and iterators take no locks at all.
+	//
+	// Iterators are weakly consistent: they yield elements in ascending order,
+	// each present at some moment of the iteration, and never block writers.
+	// Use NewConcurrent or NewConcurrentFunc to create a list.
+	Concurrent[T any] struct {
+		compare func(a, b T) int
+		head    *cnode[T] // sentinel before the first element, linked on every level.
+		size    atomic.Int64
+	}
+)
+
+// NewConcurrent creates an empty concurrent skip list of naturally ordered elements.
+func NewConcurrent[T cmp.Ordered]() *Concurrent[T] {
+	return NewConcurrentFunc(cmp.Compare[T])
+}
+
+// NewConcurrentFunc creates an empty concurrent skip list ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewConcurrentFunc[T any](compare func(a, b T) int) *Concurrent[T] {
+	head := &cnode[T]{next: make([]atomic.Pointer[cnode[T]], maxLevel)}
+	head.linked.Store(true)
+
+	return &Concurrent[T]{compare: compare, head: head}
+}
+
+// find fills preds and succs with the last nodes less than elem and the nodes following them
+// on every level. It returns the top level where elem is found, -1 when it is absent.
+func (l *Concurrent[T]) find(elem T, preds, succs *[maxLevel]*cnode[T]) int {
+	found := -1
+	pred := l.head
+
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+		for curr != nil && l.compare(curr.val, elem) < 0 {
+			pred, curr = curr, curr.next[level].Load()
+		}
+
+		if found == -1 && curr != nil && l.compare(curr.val, elem) == 0 {
+			found = level
+		}
+
+		preds[level], succs[level] = pred, curr
+	}
+
+	return found
+}
+
+// lockPreds locks distinct predecessors on levels up to top and reports whether each of them
+// is still present and followed on its level by succs, which are not being deleted
+// unless it is the victim. Otherwise the caller has to retry.
+// Predecessors are locked bottom-up, i.e. from greater elements to less ones, by all writers,
+// so they never deadlock. It returns the highest locked level to pass to unlockPreds.
+func lockPreds[T any](top int, preds, succs *[maxLevel]*cnode[T], victim *cnode[T]) (int, bool) {
+	for level := 0; level <= top; level++ {
+		pred, succ := preds[level], succs[level]
+		if level == 0 || pred != preds[level-1] {
+			pred.mu.Lock()
+		}
+
+		if pred.marked.Load() || pred.next[level].Load() != succ || succ != nil && succ != victim && succ.marked.Load() {
+			return level, false
+		}
+	}
+
+	return top, true
+}
+
+// unlockPreds unlocks predecessors locked by lockPreds up to the highest level.
+// A node is the predecessor on adjacent levels only, so it is unlocked once.
+func unlockPreds[T any](highest int, preds *[maxLevel]*cnode[T]) {
+	for level := 0; level <= highest; level++ {
+		if level == 0 || preds[level] != preds[level-1] {
+			preds[level].mu.Unlock()
+		}
+	}
+}
+
+// Add inserts elem into the list, duplicates are ignored.
+// Asymptotic: O(log n) expected.
+func (l *Concurrent[T]) Add(elem T) {
+	top := randomLevel() - 1
+
+	var preds, succs [maxLevel]*cnode[T]
+
+	for {
+		if found := l.find(elem, &preds, &succs); found != -1 {
+			n := succs[found]
+			if n.marked.Load() {
+				// Being deleted: retry once it is unlinked.
+				runtime.Gosched()
+				continue
+			}
+
+			// Being added by another goroutine: the element is present once it is linked.
+			for !n.linked.Load() {
+				runtime.Gosched()
+			}
+
+			return
+		}
+
+		highest, valid := lockPreds(top, &preds, &succs, nil)
+		if !valid {
+			unlockPreds(highest, &preds)
+			continue
+		}
+
+		n := &cnode[T]{val: elem, next: make([]atomic.Pointer[cnode[T]], top+1)}
+		for level := 0; level <= top; level++ {
+			n.next[level].Store(succs[level])
+		}
+
+		for level := 0; level <= top; level++ {
+			preds[level].next[level].Store(n)
+		}
+
+		n.linked.Store(true)
+		unlockPreds(highest, &preds)
+		l.size.Add(1)
+
+		return
+	}
+}
+
+// Delete removes elem from the list and reports whether it was present.
+// Asymptotic: O(log n) expected.
+func (l *Concurrent[T]) Delete(elem T) bool {
+	var (
+		preds, succs [maxLevel]*cnode[T]
+		victim       *cnode[T]
+	)
+
+	for {
+		found := l.find(elem, &preds, &succs)
+
+		if victim == nil {
+			// Only a fully linked node found on its top level is safe to delete,
+			// otherwise its insertion is not over yet or it is deleted already.
+			if found == -1 {
+				return false
+			}
+
+			n := succs[found]
+			if !n.linked.Load() || len(n.next)-1 != found || n.marked.Load() {
+				return false
+			}
+
+			n.mu.Lock()
+
+			if n.marked.Load() {
+				n.mu.Unlock()
+				return false
+			}
+
+			// From now on elem is absent: the node is unlinked below.
+			n.marked.Store(true)
+			victim = n
+		}
+
+		top := len(victim.next) - 1
+
+		highest, valid := lockPreds(top, &preds, &succs, victim)
+		for level := 0; valid && level <= top; level++ {
+			valid = succs[level] == victim
+		}
+
+		if !valid {
+			unlockPreds(highest, &preds)
+			continue
+		}
+
+		for level := top; level >= 0; level-- {
+			preds[level].next[level].Store(victim.next[level].Load())
+		}
+
+		victim.mu.Unlock()
+		unlockPreds(highest, &preds)
+		l.size.Add(-1)
+
+		return true
+	}
+}
+
+// Contains reports whether elem is present in the list. It never blocks.
+// Asymptotic: O(log n) expected.
+func (l *Concurrent[T]) Contains(elem T) bool {
+	var preds, succs [maxLevel]*cnode[T]
+
+	found := l.find(elem, &preds, &succs)
+
+	return found != -1 && succs[found].linked.Load() && !succs[found].marked.Load()
+}
+
+// Size returns count of elements in the list, exact when no modification is in progress.
+// Asymptotic: O(1)
+func (l *Concurrent[T]) Size() uint {
+	return uint(max(l.size.Load(), 0))
+}
+
+// walk returns an iterator over present elements of the bottom level starting from the least
+// one not less than from (or from the very first one when it is nil) and lasting while within reports true.
+func (l *Concurrent[T]) walk(from *T, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		n := l.head.next[0].Load()
+		if from != nil {
+			var preds, succs [maxLevel]*cnode[T]
+
+			l.find(*from, &preds, &succs)
+			n = succs[0]
+		}
+
+		for ; n != nil; n = n.next[0].Load() {
+			if !n.linked.Load() || n.marked.Load() {
+				continue
+			}
+
+			if within != nil && !within(n.val) || !yield(n.val) {
+				return
+			}
+		}
+	}
+}
+
+// Min returns the least element of the list if presented.
+// Asymptotic: O(1) unless many least elements are being deleted.
+func (l *Concurrent[T]) Min() (T, bool) {
+	for val := range l.walk(nil, nil) {
+		return val, true
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// All returns an iterator over all elements in ascending order.
+// Asymptotic: O(n) for the full iteration, O(1) memory.
+func (l *Concurrent[T]) All() iter.Seq[T] {
+	return l.walk(nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (l *Concurrent[T]) Ascend(from T) iter.Seq[T] {
+	return l.walk(&from, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (l *Concurrent[T]) Range(lo, hi T) iter.Seq[T] {
+	return l.walk(&lo, func(val T) bool { return l.compare(val, hi) < 0 })
+}
+
+// CheckInvariants verifies ordering of the elements on every level, that every level
+// is a sublist of the one below and the list size. It must be called with no modification
+// in progress and is intended to be called from tests.
+// Asymptotic: O(n)
+func (l *Concurrent[T]) CheckInvariants() error {
+	var (
+		count int64
+		prev  *cnode[T]
+	)
+
+	for n := l.head.next[0].Load(); n != nil; prev, n = n, n.next[0].Load() {
+		if prev != nil && l.compare(prev.val, n.val) >= 0 {
+			return fmt.Errorf("%w: %v is out of order", ErrInvariant, n.val)
+		}
+
+		if !n.linked.Load() || n.marked.Load() {
+			return fmt.Errorf("%w: %v is reachable, but not present", ErrInvariant, n.val)
+		}
+
+		count++
+	}
+
+	if size := l.size.Load(); count != size {
+		return fmt.Errorf("%w: list holds %d elements, but size is %d", ErrInvariant, count, size)
+	}
+
+	// Upper levels are sorted as sublists of the levels below.
+	for level := 1; level < maxLevel; level++ {
+		below := l.head.next[level-1].Load()
+
+		for n := l.head.next[level].Load(); n != nil; n = n.next[level].Load() {
+			for below != nil && below != n {
+				below = below.next[level-1].Load()
+			}
+
+			if below == nil {
+				return fmt.Errorf("%w: %v is linked on level %d, but not below", ErrInvariant, n.val, level)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package skiplist
+
+import (
+	"iter"
+)
+
+// step returns the node following n in the walking direction.
+func step[T any](n *node[T], desc bool) *node[T] {
+	if desc {
+		return n.prev
+	}
+
+	return n.next[0]
+}
+
+// walk returns an iterator over the list elements in the given direction,
+// starting from the from value (or from the very edge of the list when it is nil)
+// and lasting while within reports true.
+//
+// As for tree.Tree, the read lock is not held during yield, so the loop body
+// is free to modify the list: then the walk re-seeks after the last yielded element.
+func (l *SkipList[T]) walk(desc bool, from *T, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		l.mu.RLock()
+
+		var n *node[T]
+
+		switch {
+		case from != nil && desc:
+			n = l.floor(*from, true)
+		case from != nil:
+			n = l.ceiling(*from, true)
+		case desc:
+			n = l.tail
+		default:
+			n = l.head.next[0]
+		}
+
+		version := l.version
+
+		for n != nil {
+			val := n.val
+			l.mu.RUnlock()
+
+			if within != nil && !within(val) || !yield(val) {
+				return
+			}
+
+			l.mu.RLock()
+
+			switch {
+			case l.version == version:
+				n = step(n, desc)
+			case desc:
+				n = l.floor(val, false)
+			default:
+				n = l.ceiling(val, false)
+			}
+
+			version = l.version
+		}
+
+		l.mu.RUnlock()
+	}
+}
+
+// All returns an iterator over all elements in ascending order.
+// Asymptotic: O(n) for the full iteration, O(1) memory.
+func (l *SkipList[T]) All() iter.Seq[T] {
+	return l.walk(false, nil, nil)
+}
+
+// Backward returns an iterator over all elements in descending order.
+// Asymptotic: O(n) for the full iteration, O(1) memory.
+func (l *SkipList[T]) Backward() iter.Seq[T] {
+	return l.walk(true, nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (l *SkipList[T]) Ascend(from T) iter.Seq[T] {
+	return l.walk(false, &from, nil)
+}
+
+// Descend returns an iterator over elements less than or equal to from in descending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (l *SkipList[T]) Descend(from T) iter.Seq[T] {
+	return l.walk(true, &from, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (l *SkipList[T]) Range(lo, hi T) iter.Seq[T] {
+	return l.walk(false, &lo, func(val T) bool { return l.compare(val, hi) < 0 })
+}
+
+// SortedAsc returns all elements of the list in ascending order.
+// Asymptotic: O(n)
+func (l *SkipList[T]) SortedAsc() []T {
+	return l.sorted(false)
+}
+
+// SortedDesc returns all elements of the list in descending order.
+// Asymptotic: O(n)
+func (l *SkipList[T]) SortedDesc() []T {
+	return l.sorted(true)
+}
+
+func (l *SkipList[T]) sorted(desc bool) []T {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.size == 0 {
+		return nil
+	}
+
+	result := make([]T, 0, l.size)
+
+	n := l.head.next[0]
+	if desc {
+		n = l.tail
+	}
+
+	for ; n != nil; n = step(n, desc) {
+		result = append(result, n.val)
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package skiplist
+
+// value unwraps the (value, ok) form used by the public API.
+func value[T any](n *node[T]) (T, bool) {
+	if n == nil {
+		var zero T
+
+		return zero, false
+	}
+
+	return n.val, true
+}
+
+// floor returns the node of the greatest element less than elem (or equal to it when inclusive is set).
+func (l *SkipList[T]) floor(elem T, inclusive bool) *node[T] {
+	pred := l.search(elem, nil)
+	if next := pred.next[0]; inclusive && next != nil && l.compare(next.val, elem) == 0 {
+		return next
+	}
+
+	if pred == &l.head {
+		return nil
+	}
+
+	return pred
+}
+
+// ceiling returns the node of the least element greater than elem (or equal to it when inclusive is set).
+func (l *SkipList[T]) ceiling(elem T, inclusive bool) *node[T] {
+	next := l.search(elem, nil).next[0]
+	if !inclusive && next != nil && l.compare(next.val, elem) == 0 {
+		next = next.next[0]
+	}
+
+	return next
+}
+
+// Min returns the least element of the list if presented.
+// Asymptotic: O(1)
+func (l *SkipList[T]) Min() (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return value(l.head.next[0])
+}
+
+// Max returns the greatest element of the list if presented.
+// Asymptotic: O(1)
+func (l *SkipList[T]) Max() (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return value(l.tail)
+}
+
+// Floor returns the greatest element less than or equal to elem.
+// Asymptotic: O(log n) expected.
+func (l *SkipList[T]) Floor(elem T) (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return value(l.floor(elem, true))
+}
+
+// Ceiling returns the least element greater than or equal to elem.
+// Asymptotic: O(log n) expected.
+func (l *SkipList[T]) Ceiling(elem T) (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return value(l.ceiling(elem, true))
+}
+
+// Predecessor returns the greatest element strictly less than elem.
+// elem itself does not have to be present in the list.
+// Asymptotic: O(log n) expected.
+func (l *SkipList[T]) Predecessor(elem T) (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return value(l.floor(elem, false))
+}
+
+// Successor returns the least element strictly greater than elem.
+// elem itself does not have to be present in the list.
+// Asymptotic: O(log n) expected.
+func (l *SkipList[T]) Successor(elem T) (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return value(l.ceiling(elem, false))
+}
@@ -0,0 +1,212 @@
+// Package skiplist implements ordered sets on top of skip lists: sorted linked lists
+// with express lanes, where every element is promoted to the next level with probability 1/4,
+// so searches take O(log n) expected steps without any rebalancing.
+//
+// SkipList guards the whole list with a single lock like tree.Tree does, while Concurrent
+// locks only the neighbours of a changed element, so writers of different parts of the list
+// proceed in parallel and readers never block.
+package skiplist
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+)
+
+// maxLevel bounds count of levels: 4^16 elements are needed to fill them in expectation.
+const maxLevel = 16
+
+// ErrInvariant is wrapped by CheckInvariants errors of both lists naming an element
+// out of order, a broken link or an element linked on a level but missing below it.
+var ErrInvariant = errors.New("skip list invariant violated")
+
+type (
+	node[T any] struct {
+		val  T
+		next []*node[T] // successors on every level the node is promoted to.
+		prev *node[T]   // predecessor on the bottom level, nil for the first node.
+	}
+
+	// SkipList is an ordered set of distinct elements implementing tree.OrderedSet
+	// with the same semantics as tree.Tree, including modification while iterating.
+	// Use New or NewFunc to create a list.
+	SkipList[T any] struct {
+		mu      sync.RWMutex
+		compare func(a, b T) int
+		head    node[T]  // sentinel before the first element, linked on every level.
+		tail    *node[T] // the last node of the bottom level, nil for an empty list.
+		size    uint
+		version uint64 // incremented on every modification.
+	}
+)
+
+// New creates an empty skip list of naturally ordered elements.
+func New[T cmp.Ordered]() *SkipList[T] {
+	return NewFunc(cmp.Compare[T])
+}
+
+// NewFunc creates an empty skip list ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewFunc[T any](compare func(a, b T) int) *SkipList[T] {
+	return &SkipList[T]{compare: compare, head: node[T]{next: make([]*node[T], maxLevel)}}
+}
+
+// randomLevel returns count of levels for a new node: each two random bits set to zero
+// promote it one level higher.
+func randomLevel() int {
+	return min(bits.TrailingZeros64(rand.Uint64())/2+1, maxLevel)
+}
+
+// search returns the last node less than elem on the bottom level, which may be the head,
+// and fills preds with the last nodes less than elem on every level.
+func (l *SkipList[T]) search(elem T, preds *[maxLevel]*node[T]) *node[T] {
+	pred := &l.head
+
+	for level := maxLevel - 1; level >= 0; level-- {
+		for next := pred.next[level]; next != nil && l.compare(next.val, elem) < 0; next = pred.next[level] {
+			pred = next
+		}
+
+		if preds != nil {
+			preds[level] = pred
+		}
+	}
+
+	return pred
+}
+
+// Add inserts elem into the list, duplicates are ignored.
+// Asymptotic: O(log n) expected.
+func (l *SkipList[T]) Add(elem T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var preds [maxLevel]*node[T]
+
+	pred := l.search(elem, &preds)
+	if next := pred.next[0]; next != nil && l.compare(next.val, elem) == 0 {
+		return
+	}
+
+	n := &node[T]{val: elem, next: make([]*node[T], randomLevel())}
+	for level := range n.next {
+		n.next[level] = preds[level].next[level]
+		preds[level].next[level] = n
+	}
+
+	if pred != &l.head {
+		n.prev = pred
+	}
+
+	if n.next[0] != nil {
+		n.next[0].prev = n
+	} else {
+		l.tail = n
+	}
+
+	l.size++
+	l.version++
+}
+
+// Delete removes elem from the list and reports whether it was present.
+// Asymptotic: O(log n) expected.
+func (l *SkipList[T]) Delete(elem T) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var preds [maxLevel]*node[T]
+
+	n := l.search(elem, &preds).next[0]
+	if n == nil || l.compare(n.val, elem) != 0 {
+		return false
+	}
+
+	for level := range n.next {
+		preds[level].next[level] = n.next[level]
+	}
+
+	if n.next[0] != nil {
+		n.next[0].prev = n.prev
+	} else {
+		l.tail = n.prev
+	}
+
+	l.size--
+	l.version++
+
+	return true
+}
+
+// Contains reports whether elem is present in the list.
+// Asymptotic: O(log n) expected.
+func (l *SkipList[T]) Contains(elem T) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	n := l.search(elem, nil).next[0]
+
+	return n != nil && l.compare(n.val, elem) == 0
+}
+
+// Size returns count of elements in the list.
+// Asymptotic: O(1)
+func (l *SkipList[T]) Size() uint {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.size
+}
+
+// CheckInvariants verifies ordering of the elements on every level, that every level
+// is a sublist of the one below, the backward links and the list size.
+// It is intended to be called from tests.
+// Asymptotic: O(n)
+func (l *SkipList[T]) CheckInvariants() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var (
+		count uint
+		prev  *node[T]
+	)
+
+	for n := l.head.next[0]; n != nil; prev, n = n, n.next[0] {
+		if prev != nil && l.compare(prev.val, n.val) >= 0 {
+			return fmt.Errorf("%w: %v is out of order", ErrInvariant, n.val)
+		}
+
+		if n.prev != prev {
+			return fmt.Errorf("%w: %v has a wrong backward link", ErrInvariant, n.val)
+		}
+
+		count++
+	}
+
+	if prev != l.tail {
+		return fmt.Errorf("%w: tail is not the last node", ErrInvariant)
+	}
+
+	if count != l.size {
+		return fmt.Errorf("%w: list holds %d elements, but size is %d", ErrInvariant, count, l.size)
+	}
+
+	// Upper levels are sorted as sublists of the levels below.
+	for level := 1; level < maxLevel; level++ {
+		below := l.head.next[level-1]
+
+		for n := l.head.next[level]; n != nil; n = n.next[level] {
+			for below != nil && below != n {
+				below = below.next[level-1]
+			}
+
+			if below == nil {
+				return fmt.Errorf("%w: %v is linked on level %d, but not below", ErrInvariant, n.val, level)
+			}
+		}
+	}
+
+	return nil
+}
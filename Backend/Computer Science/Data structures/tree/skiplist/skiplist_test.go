@@ -0,0 +1,270 @@
+package skiplist_test
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/settest"
+	"github.com/dzianismaroz/marathon/tree/skiplist"
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+var _ tree.OrderedSet[int] = (*skiplist.SkipList[int])(nil)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	settest.Run(t, func() tree.OrderedSet[int] { return skiplist.New[int]() })
+}
+
+func TestSkipListCustomOrder(t *testing.T) {
+	t.Parallel()
+
+	sut := skiplist.NewFunc(func(a, b string) int { return len(a) - len(b) })
+	for _, s := range []string{"ccc", "a", "bb", "dd"} {
+		sut.Add(s)
+	}
+
+	if got, expected := sut.SortedDesc(), []string{"ccc", "bb", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestConcurrentSequential(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(19))
+	sut := skiplist.NewConcurrent[int]()
+
+	var model []int
+
+	for i := 0; i < 20_000; i++ {
+		v := rnd.Intn(1000)
+		at, found := slices.BinarySearch(model, v)
+
+		if rnd.Intn(3) == 0 {
+			if deleted := sut.Delete(v); deleted != found {
+				t.Fatalf("step %d: Delete(%d) = %v, expected %v", i, v, deleted, found)
+			}
+
+			if found {
+				model = slices.Delete(model, at, at+1)
+			}
+		} else {
+			sut.Add(v)
+
+			if !found {
+				model = slices.Insert(model, at, v)
+			}
+		}
+
+		if contains := sut.Contains(v); contains != slices.Contains(model, v) {
+			t.Fatalf("step %d: Contains(%d) = %v", i, v, contains)
+		}
+
+		if sut.Size() != uint(len(model)) {
+			t.Fatalf("step %d: expected size %d, got %d", i, len(model), sut.Size())
+		}
+	}
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := slices.Collect(sut.All()); !slices.Equal(got, model) {
+		t.Errorf("expected %v, got %v", model, got)
+	}
+
+	lo, hi := 250, 750
+	expected := model[idx(model, lo):idx(model, hi)]
+
+	if got := slices.Collect(sut.Range(lo, hi)); !slices.Equal(got, expected) {
+		t.Errorf("Range(%d, %d): expected %v, got %v", lo, hi, expected, got)
+	}
+
+	if got := slices.Collect(sut.Ascend(hi)); !slices.Equal(got, model[idx(model, hi):]) {
+		t.Errorf("Ascend(%d): expected %v, got %v", hi, model[idx(model, hi):], got)
+	}
+
+	if got, ok := sut.Min(); !ok || got != model[0] {
+		t.Errorf("expected min %d, got %d, %v", model[0], got, ok)
+	}
+}
+
+func idx(sorted []int, v int) int {
+	i, _ := slices.BinarySearch(sorted, v)
+
+	return i
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	t.Parallel()
+
+	const (
+		writers = 8
+		perEach = 5_000
+	)
+
+	sut := skiplist.NewConcurrent[int]()
+
+	// Writers add interleaved ranges and delete odd elements of their own, contending
+	// for the same neighbourhoods, while readers iterate over the list.
+	var wg sync.WaitGroup
+
+	for w := range writers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range perEach {
+				sut.Add(i*writers + w)
+			}
+
+			for i := 1; i < perEach; i += 2 {
+				if !sut.Delete(i*writers + w) {
+					t.Errorf("%d is not deleted", i*writers+w)
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for range 20 {
+			prev := -1
+			for v := range sut.All() {
+				if v <= prev {
+					t.Errorf("iteration yields %d after %d", v, prev)
+				}
+
+				prev = v
+			}
+		}
+	}()
+
+	wg.Wait()
+	<-done
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []int
+
+	for i := 0; i < perEach; i += 2 {
+		for w := range writers {
+			expected = append(expected, i*writers+w)
+		}
+	}
+
+	if got := slices.Collect(sut.All()); !slices.Equal(got, expected) {
+		t.Errorf("expected %d elements, got %d", len(expected), len(got))
+	}
+
+	if sut.Size() != uint(len(expected)) {
+		t.Errorf("expected size %d, got %d", len(expected), sut.Size())
+	}
+}
+
+func TestConcurrentSameElement(t *testing.T) {
+	t.Parallel()
+
+	sut := skiplist.NewConcurrent[int]()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		deleted int
+	)
+
+	// Many goroutines add and delete the same few elements: every successful Delete
+	// must be matched by an Add, so the size never drifts.
+	for g := range 16 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range 2_000 {
+				v := (g + i) % 4
+				sut.Add(v)
+
+				if sut.Delete(v) {
+					mu.Lock()
+					deleted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted == 0 {
+		t.Error("nothing is deleted")
+	}
+}
+
+const benchSize = 100_000
+
+// set is the part of the ordered set API exercised by benchmarks.
+type set interface {
+	Add(elem int)
+	Delete(elem int) bool
+	Contains(elem int) bool
+}
+
+// backends lists sets compared by benchmarks.
+var backends = []struct {
+	name string
+	new  func() set
+}{
+	{name: "Tree", new: func() set { return tree.New[int]() }},
+	{name: "SkipList", new: func() set { return skiplist.New[int]() }},
+	{name: "Concurrent", new: func() set { return skiplist.NewConcurrent[int]() }},
+}
+
+// BenchmarkParallelMixed runs all goroutines against one set with the given share of writes,
+// half of them adding and half deleting random elements.
+func BenchmarkParallelMixed(b *testing.B) {
+	for _, writes := range []int{0, 10, 50, 100} {
+		for _, backend := range backends {
+			b.Run(fmt.Sprintf("writes=%d%%/%s", writes, backend.name), func(b *testing.B) {
+				sut := backend.new()
+				for _, v := range rand.New(rand.NewSource(1)).Perm(2 * benchSize)[:benchSize] {
+					sut.Add(v)
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				b.RunParallel(func(pb *testing.PB) {
+					rnd := rand.New(rand.NewSource(rand.Int63()))
+
+					for pb.Next() {
+						v := rnd.Intn(2 * benchSize)
+
+						switch op := rnd.Intn(200); {
+						case op >= 2*writes:
+							sut.Contains(v)
+						case op%2 == 0:
+							sut.Add(v)
+						default:
+							sut.Delete(v)
+						}
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package splay
+
+import (
+	"iter"
+)
+
+// walker performs lazy in-order traversal with an explicit stack of nodes
+// whose elements are still to be yielded. It never splays, so it only needs the read lock.
+type walker[T any] struct {
+	tree  *Tree[T]
+	stack []*node[T]
+	desc  bool
+}
+
+// first returns the child of n visited before n itself.
+func (w *walker[T]) first(n *node[T]) *node[T] {
+	if w.desc {
+		return n.right
+	}
+
+	return n.left
+}
+
+// second returns the child of n visited after n itself.
+func (w *walker[T]) second(n *node[T]) *node[T] {
+	if w.desc {
+		return n.left
+	}
+
+	return n.right
+}
+
+// pushFrom pushes n and the chain of its first-visited descendants.
+func (w *walker[T]) pushFrom(n *node[T]) {
+	for ; n != nil; n = w.first(n) {
+		w.stack = append(w.stack, n)
+	}
+}
+
+// seek positions the walker on the first element not before from in the walking direction.
+// from itself is skipped unless inclusive is set.
+func (w *walker[T]) seek(from T, inclusive bool) {
+	w.stack = w.stack[:0]
+
+	for n := w.tree.root; n != nil; {
+		c := w.tree.compare(n.val, from)
+		if w.desc {
+			c = -c
+		}
+
+		switch {
+		case c == 0 && inclusive:
+			w.stack = append(w.stack, n)
+			return
+		case c > 0:
+			w.stack = append(w.stack, n)
+			n = w.first(n)
+		default:
+			n = w.second(n)
+		}
+	}
+}
+
+// advance returns the next node of the walk, nil when the walk is over.
+func (w *walker[T]) advance() *node[T] {
+	if len(w.stack) == 0 {
+		return nil
+	}
+
+	top := len(w.stack) - 1
+	n := w.stack[top]
+	w.stack = w.stack[:top]
+	w.pushFrom(w.second(n))
+
+	return n
+}
+
+// walk returns an iterator over the tree elements in the given direction,
+// starting from the from value (or from the very edge of the tree when it is nil)
+// and lasting while within reports true.
+//
+// As for tree.Tree, the read lock is not held during yield, so the loop body
+// is free to modify or query the tree: then the walker re-seeks after the last yielded element.
+func (t *Tree[T]) walk(desc bool, from *T, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.mu.RLock()
+
+		w := walker[T]{tree: t, stack: make([]*node[T], 0, 32), desc: desc}
+		if from == nil {
+			w.pushFrom(t.root)
+		} else {
+			w.seek(*from, true)
+		}
+
+		version := t.version
+
+		for {
+			n := w.advance()
+			t.mu.RUnlock()
+
+			if n == nil {
+				return
+			}
+
+			// Nodes never change their elements, so n.val is safe to read without the lock.
+			val := n.val
+			if within != nil && !within(val) || !yield(val) {
+				return
+			}
+
+			t.mu.RLock()
+
+			if t.version != version {
+				w.seek(val, false)
+				version = t.version
+			}
+		}
+	}
+}
+
+// All returns an iterator over all elements in ascending order.
+// Asymptotic: O(n) for the full iteration, O(h) memory for the tree height h.
+func (t *Tree[T]) All() iter.Seq[T] {
+	return t.walk(false, nil, nil)
+}
+
+// Backward returns an iterator over all elements in descending order.
+// Asymptotic: O(n) for the full iteration, O(h) memory for the tree height h.
+func (t *Tree[T]) Backward() iter.Seq[T] {
+	return t.walk(true, nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+// Asymptotic: O(h + k) for the tree height h and k yielded elements.
+func (t *Tree[T]) Ascend(from T) iter.Seq[T] {
+	return t.walk(false, &from, nil)
+}
+
+// Descend returns an iterator over elements less than or equal to from in descending order.
+// Asymptotic: O(h + k) for the tree height h and k yielded elements.
+func (t *Tree[T]) Descend(from T) iter.Seq[T] {
+	return t.walk(true, &from, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+// Asymptotic: O(h + k) for the tree height h and k yielded elements.
+func (t *Tree[T]) Range(lo, hi T) iter.Seq[T] {
+	return t.walk(false, &lo, func(val T) bool { return t.compare(val, hi) < 0 })
+}
+
+// SortedAsc returns all elements of the tree in ascending order.
+// Asymptotic: O(n)
+func (t *Tree[T]) SortedAsc() []T {
+	return t.sorted(false)
+}
+
+// SortedDesc returns all elements of the tree in descending order.
+// Asymptotic: O(n)
+func (t *Tree[T]) SortedDesc() []T {
+	return t.sorted(true)
+}
+
+func (t *Tree[T]) sorted(desc bool) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	result := make([]T, 0, t.root.size)
+	w := walker[T]{tree: t, desc: desc}
+	w.pushFrom(t.root)
+
+	for n := w.advance(); n != nil; n = w.advance() {
+		result = append(result, n.val)
+	}
+
+	return result
+}
@@ -0,0 +1,140 @@
+package splay
+
+// min returns the node of the least element of the subtree.
+func (n *node[T]) min() *node[T] {
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+// max returns the node of the greatest element of the subtree.
+func (n *node[T]) max() *node[T] {
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+// found splays n and unwraps it into the (value, ok) form used by the public API.
+func (t *Tree[T]) found(n *node[T]) (T, bool) {
+	if n == nil {
+		var zero T
+
+		return zero, false
+	}
+
+	t.splay(n)
+
+	return n.val, true
+}
+
+// bound returns the node of the greatest element less than elem when below is set,
+// otherwise the least element greater than it; elem itself matches when inclusive is set.
+// The last node of the search path is splayed to pay for the whole path
+// as the found element may lie much higher.
+func (t *Tree[T]) bound(elem T, below, inclusive bool) *node[T] {
+	var candidate, last *node[T]
+
+	for n := t.root; n != nil; {
+		last = n
+
+		c := t.compare(n.val, elem)
+		if below {
+			c = -c
+		}
+
+		switch {
+		case c == 0 && inclusive:
+			return n
+		case c > 0:
+			candidate = n
+			n = n.forward(below)
+		default:
+			n = n.backward(below)
+		}
+	}
+
+	if last != nil {
+		t.splay(last)
+	}
+
+	return candidate
+}
+
+// forward returns the child of n toward elements closer to a bound searched
+// from below or above.
+func (n *node[T]) forward(below bool) *node[T] {
+	if below {
+		return n.right
+	}
+
+	return n.left
+}
+
+// backward returns the child of n opposite to forward.
+func (n *node[T]) backward(below bool) *node[T] {
+	if below {
+		return n.left
+	}
+
+	return n.right
+}
+
+// Min returns the least element of the tree if presented.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Min() (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.found(t.root.min())
+}
+
+// Max returns the greatest element of the tree if presented.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Max() (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.found(t.root.max())
+}
+
+// Floor returns the greatest element less than or equal to elem.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Floor(elem T) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.found(t.bound(elem, true, true))
+}
+
+// Ceiling returns the least element greater than or equal to elem.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Ceiling(elem T) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.found(t.bound(elem, false, true))
+}
+
+// Predecessor returns the greatest element strictly less than elem.
+// elem itself does not have to be present in the tree.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Predecessor(elem T) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.found(t.bound(elem, true, false))
+}
+
+// Successor returns the least element strictly greater than elem.
+// elem itself does not have to be present in the tree.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Successor(elem T) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.found(t.bound(elem, false, false))
+}
@@ -0,0 +1,427 @@
+// Package splay implements a splay tree: a self-adjusting binary search tree
+// which moves every accessed element to the root, so recently used elements
+// are cheap to reach and splitting by key and concatenation are O(log n) amortized.
+package splay
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrInvariant is wrapped by CheckInvariants errors naming a node out of order,
+	// with a broken parent link or with a wrong size.
+	ErrInvariant = errors.New("splay tree invariant violated")
+	// ErrOverlap is returned by Merge when the merged tree holds an element
+	// not greater than every element of the receiver; neither tree is changed then.
+	ErrOverlap = errors.New("merged tree has elements not greater than the elements of the receiver")
+)
+
+// lastID is the id of the last created splay tree.
+var lastID atomic.Uint64
+
+type (
+	node[T any] struct {
+		val    T
+		left   *node[T] // less than val.
+		right  *node[T] // greater than val.
+		parent *node[T]
+		size   uint // count of elements in the subtree rooted at this node.
+	}
+
+	// Tree is an ordered set of distinct elements implementing tree.OrderedSet
+	// with the same semantics as tree.Tree, extended with Split and Merge.
+	// Lookups restructure the tree, so unlike other ordered sets they take
+	// the exclusive lock and invalidate running iterators, which re-seek.
+	// Use New or NewFunc to create a tree.
+	Tree[T any] struct {
+		mu      sync.RWMutex
+		compare func(a, b T) int
+		root    *node[T]
+		version uint64 // incremented on every restructuring, including splaying by lookups.
+		id      uint64 // orders locks of trees merged into each other.
+	}
+)
+
+// New creates an empty splay tree of naturally ordered elements.
+func New[T cmp.Ordered]() *Tree[T] {
+	return NewFunc(cmp.Compare[T])
+}
+
+// NewFunc creates an empty splay tree ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewFunc[T any](compare func(a, b T) int) *Tree[T] {
+	return &Tree[T]{compare: compare, id: lastID.Add(1)}
+}
+
+func (n *node[T]) getSize() uint {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// fix recalculates cached size of the node from its children.
+func (n *node[T]) fix() {
+	n.size = 1 + n.left.getSize() + n.right.getSize()
+}
+
+// setLeft links child as the left subtree of n.
+func (n *node[T]) setLeft(child *node[T]) {
+	n.left = child
+	if child != nil {
+		child.parent = n
+	}
+}
+
+// setRight links child as the right subtree of n.
+func (n *node[T]) setRight(child *node[T]) {
+	n.right = child
+	if child != nil {
+		child.parent = n
+	}
+}
+
+// rotate lifts x above its parent.
+func (t *Tree[T]) rotate(x *node[T]) {
+	p := x.parent
+	g := p.parent
+
+	if p.left == x {
+		p.setLeft(x.right)
+		x.setRight(p)
+	} else {
+		p.setRight(x.left)
+		x.setLeft(p)
+	}
+
+	x.parent = g
+
+	switch {
+	case g == nil:
+		t.root = x
+	case g.left == p:
+		g.left = x
+	default:
+		g.right = x
+	}
+
+	p.fix()
+	x.fix()
+}
+
+// splay moves x to the root by zig-zig and zig-zag steps.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) splay(x *node[T]) {
+	for x.parent != nil {
+		p := x.parent
+		if g := p.parent; g != nil {
+			if (g.left == p) == (p.left == x) {
+				t.rotate(p)
+			} else {
+				t.rotate(x)
+			}
+		}
+
+		t.rotate(x)
+	}
+
+	t.version++
+}
+
+// access searches for elem and splays the found node or, when absent,
+// the last node of the search path, which holds a neighbour of elem.
+// It returns the new root, nil for an empty tree.
+func (t *Tree[T]) access(elem T) *node[T] {
+	var last *node[T]
+
+	for n := t.root; n != nil; {
+		last = n
+
+		switch c := t.compare(elem, n.val); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			n = nil
+		}
+	}
+
+	if last != nil {
+		t.splay(last)
+	}
+
+	return last
+}
+
+// Add inserts elem into the tree, duplicates are ignored.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Add(elem T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	root := t.access(elem)
+	if root != nil && t.compare(elem, root.val) == 0 {
+		return
+	}
+
+	// The root is a neighbour of elem, so it goes to one side of the new node
+	// together with its subtree on that side.
+	n := &node[T]{val: elem}
+
+	switch {
+	case root == nil:
+	case t.compare(elem, root.val) < 0:
+		n.setLeft(root.left)
+		root.left = nil
+		root.fix()
+		n.setRight(root)
+	default:
+		n.setRight(root.right)
+		root.right = nil
+		root.fix()
+		n.setLeft(root)
+	}
+
+	n.fix()
+	t.root = n
+	t.version++
+}
+
+// Delete removes elem from the tree and reports whether it was present.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Delete(elem T) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	root := t.access(elem)
+	if root == nil || t.compare(elem, root.val) != 0 {
+		return false
+	}
+
+	less, rest := t.detach(root)
+	t.root = t.join(less, rest)
+	t.version++
+
+	return true
+}
+
+// detach cuts both subtrees off the root n and returns them.
+func (t *Tree[T]) detach(n *node[T]) (*node[T], *node[T]) {
+	less, rest := n.left, n.right
+	if less != nil {
+		less.parent = nil
+	}
+
+	if rest != nil {
+		rest.parent = nil
+	}
+
+	n.left, n.right = nil, nil
+	n.fix()
+
+	return less, rest
+}
+
+// join concatenates two detached subtrees, every element of less is less than
+// every element of rest, and returns the new root.
+func (t *Tree[T]) join(less, rest *node[T]) *node[T] {
+	if less == nil {
+		return rest
+	}
+
+	// With the greatest element at the root of less, its right subtree is empty.
+	t.root = less
+	t.splay(less.max())
+	t.root.setRight(rest)
+	t.root.fix()
+
+	return t.root
+}
+
+// Split moves elements greater than or equal to key into a new tree and returns it,
+// so the tree keeps only elements less than key.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Split(key T) *Tree[T] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rest := NewFunc(t.compare)
+
+	root := t.access(key)
+	if root == nil {
+		return rest
+	}
+
+	less, greater := t.detach(root)
+	if t.compare(root.val, key) < 0 {
+		// The root is the greatest element less than key.
+		root.setLeft(less)
+		root.fix()
+		t.root, rest.root = root, greater
+	} else {
+		// The root is the least element greater than or equal to key.
+		root.setRight(greater)
+		root.fix()
+		t.root, rest.root = less, root
+	}
+
+	t.version++
+
+	return rest
+}
+
+// Merge moves all elements of other to the end of the tree, leaving other empty.
+// Every element of other must be greater than every element of the tree,
+// otherwise ErrOverlap is returned and neither tree is changed.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Merge(other *Tree[T]) error {
+	if other == t {
+		if t.Size() == 0 {
+			return nil
+		}
+
+		return ErrOverlap
+	}
+
+	// Locks are taken in order of ids, so merging two trees into each other
+	// concurrently does not deadlock.
+	first, second := t, other
+	if first.id > second.id {
+		first, second = second, first
+	}
+
+	first.mu.Lock()
+	defer first.mu.Unlock()
+
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	if other.root == nil {
+		return nil
+	}
+
+	// The bounds are found without splaying, so a failed merge leaves both trees as they were.
+	if t.root != nil && t.compare(t.root.max().val, other.root.min().val) >= 0 {
+		return ErrOverlap
+	}
+
+	t.root = t.join(t.root, other.root)
+	other.root = nil
+	t.version++
+	other.version++
+
+	return nil
+}
+
+// Contains reports whether elem is present in the tree.
+// Asymptotic: O(log n) amortized.
+func (t *Tree[T]) Contains(elem T) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	root := t.access(elem)
+
+	return root != nil && t.compare(elem, root.val) == 0
+}
+
+// Size returns count of elements in the tree.
+// Asymptotic: O(1)
+func (t *Tree[T]) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.getSize()
+}
+
+// Height returns count of nodes on the longest path from the root to a leaf.
+// Asymptotic: O(n)
+func (t *Tree[T]) Height() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	height := 0
+
+	type level struct {
+		node  *node[T]
+		depth int
+	}
+
+	for stack := []level{{t.root, 1}}; len(stack) > 0; {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if top.node == nil {
+			continue
+		}
+
+		height = max(height, top.depth)
+		stack = append(stack, level{top.node.left, top.depth + 1}, level{top.node.right, top.depth + 1})
+	}
+
+	return height
+}
+
+// CheckInvariants verifies ordering of the elements, parent links
+// and cached subtree sizes. It is intended to be called from tests.
+// Asymptotic: O(n)
+func (t *Tree[T]) CheckInvariants() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root != nil && t.root.parent != nil {
+		return fmt.Errorf("%w: root %v has a parent", ErrInvariant, t.root.val)
+	}
+
+	// Nodes are checked without recursion as the tree may degenerate into a long path.
+	var prev *node[T]
+
+	w := walker[T]{tree: t}
+	w.pushFrom(t.root)
+
+	for n := w.advance(); n != nil; n = w.advance() {
+		if prev != nil && t.compare(prev.val, n.val) >= 0 {
+			return fmt.Errorf("%w: %v is out of order", ErrInvariant, n.val)
+		}
+
+		for _, child := range []*node[T]{n.left, n.right} {
+			if child != nil && child.parent != n {
+				return fmt.Errorf("%w: child %v of %v links to another parent", ErrInvariant, child.val, n.val)
+			}
+		}
+
+		prev = n
+	}
+
+	return checkSizes(t.root)
+}
+
+// checkSizes verifies cached subtree sizes bottom-up with an explicit stack.
+func checkSizes[T any](root *node[T]) error {
+	var order []*node[T]
+
+	for stack := []*node[T]{root}; len(stack) > 0; {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if n != nil {
+			order = append(order, n)
+			stack = append(stack, n.left, n.right)
+		}
+	}
+
+	// Children follow their parents in order, so walk it backwards.
+	for i := len(order) - 1; i >= 0; i-- {
+		n := order[i]
+		if want := 1 + n.left.getSize() + n.right.getSize(); n.size != want {
+			return fmt.Errorf("%w: node %v has size %d, want %d", ErrInvariant, n.val, n.size, want)
+		}
+	}
+
+	return nil
+}
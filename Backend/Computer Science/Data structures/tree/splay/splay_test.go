@@ -0,0 +1,122 @@
+package splay_test
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/settest"
+	"github.com/dzianismaroz/marathon/tree/splay"
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+var _ tree.OrderedSet[int] = (*splay.Tree[int])(nil)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	settest.Run(t, func() tree.OrderedSet[int] { return splay.New[int]() })
+}
+
+func TestSplitMerge(t *testing.T) {
+	t.Parallel()
+	settest.RunSplit(t, splay.New[int])
+}
+
+func TestSplayMergeOverlap(t *testing.T) {
+	t.Parallel()
+
+	sut, other := splay.New[int](), splay.New[int]()
+	sut.Add(10)
+	other.Add(10)
+
+	if err := sut.Merge(other); !errors.Is(err, splay.ErrOverlap) {
+		t.Errorf("expected %v, got %v", splay.ErrOverlap, err)
+	}
+}
+
+func TestSplayMergeOverlapKeepsShape(t *testing.T) {
+	t.Parallel()
+
+	const size = 100
+
+	// Both trees are paths with the bounds Merge compares at the bottom.
+	sut, other := splay.New[int](), splay.New[int]()
+	for i := range size {
+		sut.Add(size - i)
+		other.Add(i)
+	}
+
+	if err := sut.Merge(other); !errors.Is(err, splay.ErrOverlap) {
+		t.Fatalf("expected %v, got %v", splay.ErrOverlap, err)
+	}
+
+	for name, tree := range map[string]*splay.Tree[int]{"receiver": sut, "other": other} {
+		if height := tree.Height(); height != size {
+			t.Errorf("expected height %d of %s after failed merge, got %d", size, name, height)
+		}
+	}
+}
+
+func TestSplayAccessHalvesDepth(t *testing.T) {
+	t.Parallel()
+
+	const size = 1000
+
+	// Sorted insertion leaves a path: every new element is the root with the rest on its left.
+	sut := splay.New[int]()
+	for i := range size {
+		sut.Add(i)
+	}
+
+	if height := sut.Height(); height != size {
+		t.Fatalf("expected height %d after sorted insertion, got %d", size, height)
+	}
+
+	// Splaying the deepest element roughly halves depth of every node on its path.
+	if !sut.Contains(0) {
+		t.Fatal("0 is not found")
+	}
+
+	if height := sut.Height(); height > size/2+2 {
+		t.Errorf("expected height about %d after accessing the deepest element, got %d", size/2, height)
+	}
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSplayCustomOrder(t *testing.T) {
+	t.Parallel()
+
+	sut := splay.NewFunc(func(a, b string) int { return len(a) - len(b) })
+	for _, s := range []string{"ccc", "a", "bb", "dd"} {
+		sut.Add(s)
+	}
+
+	if got, expected := sut.SortedDesc(), []string{"ccc", "bb", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+const benchSize = 1_000_000
+
+func BenchmarkSplitMerge(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchSize)
+
+	sut := splay.New[int]()
+	for _, key := range keys {
+		sut.Add(key)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		rest := sut.Split(keys[i%benchSize])
+		if err := sut.Merge(rest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
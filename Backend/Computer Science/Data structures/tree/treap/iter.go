@@ -0,0 +1,182 @@
+package treap
+
+import (
+	"iter"
+)
+
+// walker performs lazy in-order traversal with an explicit stack of nodes
+// whose elements are still to be yielded.
+type walker[T any] struct {
+	treap *Treap[T]
+	stack []*node[T]
+	desc  bool
+}
+
+// pushFrom pushes n and the chain of its first-visited descendants.
+func (w *walker[T]) pushFrom(n *node[T]) {
+	for n != nil {
+		w.stack = append(w.stack, n)
+
+		if w.desc {
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+}
+
+// seek positions the walker on the first element not before from in the walking direction.
+// from itself is skipped unless inclusive is set.
+func (w *walker[T]) seek(from T, inclusive bool) {
+	w.stack = w.stack[:0]
+
+	for n := w.treap.root; n != nil; {
+		c := w.treap.compare(n.val, from)
+		if w.desc {
+			c = -c
+		}
+
+		switch {
+		case c == 0 && inclusive:
+			w.stack = append(w.stack, n)
+			return
+		case c > 0:
+			w.stack = append(w.stack, n)
+			n = w.first(n)
+		default:
+			n = w.second(n)
+		}
+	}
+}
+
+// first returns the child of n visited before n itself.
+func (w *walker[T]) first(n *node[T]) *node[T] {
+	if w.desc {
+		return n.right
+	}
+
+	return n.left
+}
+
+// second returns the child of n visited after n itself.
+func (w *walker[T]) second(n *node[T]) *node[T] {
+	if w.desc {
+		return n.left
+	}
+
+	return n.right
+}
+
+// next returns the next element of the walk and reports whether there is one.
+func (w *walker[T]) next() (T, bool) {
+	if len(w.stack) == 0 {
+		var zero T
+
+		return zero, false
+	}
+
+	top := len(w.stack) - 1
+	n := w.stack[top]
+	w.stack = w.stack[:top]
+	w.pushFrom(w.second(n))
+
+	return n.val, true
+}
+
+// walk returns an iterator over the treap elements in the given direction,
+// starting from the from value (or from the very edge of the treap when it is nil)
+// and lasting while within reports true.
+//
+// As for tree.Tree, the read lock is not held during yield, so the loop body
+// is free to modify the treap: then the walker re-seeks after the last yielded element.
+func (t *Treap[T]) walk(desc bool, from *T, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.mu.RLock()
+
+		w := walker[T]{treap: t, stack: make([]*node[T], 0, 32), desc: desc}
+		if from == nil {
+			w.pushFrom(t.root)
+		} else {
+			w.seek(*from, true)
+		}
+
+		version := t.version
+
+		for {
+			val, ok := w.next()
+			t.mu.RUnlock()
+
+			if !ok || within != nil && !within(val) || !yield(val) {
+				return
+			}
+
+			t.mu.RLock()
+
+			if t.version != version {
+				w.seek(val, false)
+				version = t.version
+			}
+		}
+	}
+}
+
+// All returns an iterator over all elements in ascending order.
+// Asymptotic: O(n) for the full iteration, O(log n) expected memory.
+func (t *Treap[T]) All() iter.Seq[T] {
+	return t.walk(false, nil, nil)
+}
+
+// Backward returns an iterator over all elements in descending order.
+// Asymptotic: O(n) for the full iteration, O(log n) expected memory.
+func (t *Treap[T]) Backward() iter.Seq[T] {
+	return t.walk(true, nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (t *Treap[T]) Ascend(from T) iter.Seq[T] {
+	return t.walk(false, &from, nil)
+}
+
+// Descend returns an iterator over elements less than or equal to from in descending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (t *Treap[T]) Descend(from T) iter.Seq[T] {
+	return t.walk(true, &from, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+// Asymptotic: O(log n + k) expected for k yielded elements.
+func (t *Treap[T]) Range(lo, hi T) iter.Seq[T] {
+	return t.walk(false, &lo, func(val T) bool { return t.compare(val, hi) < 0 })
+}
+
+// SortedAsc returns all elements of the treap in ascending order.
+// Asymptotic: O(n)
+func (t *Treap[T]) SortedAsc() []T {
+	return t.sorted(false)
+}
+
+// SortedDesc returns all elements of the treap in descending order.
+// Asymptotic: O(n)
+func (t *Treap[T]) SortedDesc() []T {
+	return t.sorted(true)
+}
+
+func (t *Treap[T]) sorted(desc bool) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	result := make([]T, 0, t.root.size)
+	w := walker[T]{treap: t, desc: desc}
+	w.pushFrom(t.root)
+
+	for val, ok := w.next(); ok; val, ok = w.next() {
+		result = append(result, val)
+	}
+
+	return result
+}
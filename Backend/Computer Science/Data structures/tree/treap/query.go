@@ -0,0 +1,122 @@
+package treap
+
+// value unwraps the (value, ok) form used by the public API.
+func value[T any](n *node[T]) (T, bool) {
+	if n == nil {
+		var zero T
+
+		return zero, false
+	}
+
+	return n.val, true
+}
+
+// min returns the node of the least element of the subtree.
+func (n *node[T]) min() *node[T] {
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+// max returns the node of the greatest element of the subtree.
+func (n *node[T]) max() *node[T] {
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+// floor returns the node of the greatest element less than elem (or equal to it when inclusive is set).
+func (t *Treap[T]) floor(elem T, inclusive bool) *node[T] {
+	var candidate *node[T]
+
+	for n := t.root; n != nil; {
+		switch c := t.compare(n.val, elem); {
+		case c == 0 && inclusive:
+			return n
+		case c < 0:
+			candidate, n = n, n.right
+		default:
+			n = n.left
+		}
+	}
+
+	return candidate
+}
+
+// ceiling returns the node of the least element greater than elem (or equal to it when inclusive is set).
+func (t *Treap[T]) ceiling(elem T, inclusive bool) *node[T] {
+	var candidate *node[T]
+
+	for n := t.root; n != nil; {
+		switch c := t.compare(n.val, elem); {
+		case c == 0 && inclusive:
+			return n
+		case c > 0:
+			candidate, n = n, n.left
+		default:
+			n = n.right
+		}
+	}
+
+	return candidate
+}
+
+// Min returns the least element of the treap if presented.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Min() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.root.min())
+}
+
+// Max returns the greatest element of the treap if presented.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Max() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.root.max())
+}
+
+// Floor returns the greatest element less than or equal to elem.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Floor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.floor(elem, true))
+}
+
+// Ceiling returns the least element greater than or equal to elem.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Ceiling(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.ceiling(elem, true))
+}
+
+// Predecessor returns the greatest element strictly less than elem.
+// elem itself does not have to be present in the treap.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Predecessor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.floor(elem, false))
+}
+
+// Successor returns the least element strictly greater than elem.
+// elem itself does not have to be present in the treap.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Successor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return value(t.ceiling(elem, false))
+}
@@ -0,0 +1,297 @@
+// Package treap implements a randomized treap: a binary search tree by elements
+// and a max-heap by random priorities, which keeps it balanced in expectation
+// and makes splitting by key and concatenation O(log n).
+package treap
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrInvariant is wrapped by CheckInvariants errors naming a node out of order,
+	// with a priority above its parent or with a wrong size.
+	ErrInvariant = errors.New("treap invariant violated")
+	// ErrOverlap is returned by Merge when the merged treap holds an element
+	// not greater than every element of the receiver.
+	ErrOverlap = errors.New("merged treap has elements not greater than the elements of the receiver")
+)
+
+// lastID is the id of the last created treap.
+var lastID atomic.Uint64
+
+type (
+	node[T any] struct {
+		val      T
+		priority uint64
+		left     *node[T] // less than val.
+		right    *node[T] // greater than val.
+		size     uint     // count of elements in the subtree rooted at this node.
+	}
+
+	// Treap is an ordered set of distinct elements implementing tree.OrderedSet
+	// with the same semantics as tree.Tree, extended with Split and Merge.
+	// Use New or NewFunc to create a treap.
+	Treap[T any] struct {
+		mu      sync.RWMutex
+		compare func(a, b T) int
+		root    *node[T]
+		version uint64 // incremented on every modification.
+		id      uint64 // orders locks of treaps merged into each other.
+	}
+)
+
+// New creates an empty treap of naturally ordered elements.
+func New[T cmp.Ordered]() *Treap[T] {
+	return NewFunc(cmp.Compare[T])
+}
+
+// NewFunc creates an empty treap ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewFunc[T any](compare func(a, b T) int) *Treap[T] {
+	return &Treap[T]{compare: compare, id: lastID.Add(1)}
+}
+
+func (n *node[T]) getSize() uint {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// fix recalculates cached size of the node from its children.
+func (n *node[T]) fix() {
+	n.size = 1 + n.left.getSize() + n.right.getSize()
+}
+
+// split divides the subtree into elements less than key and the rest.
+// Asymptotic: O(log n) expected.
+func split[T any](n *node[T], compare func(a, b T) int, key T) (*node[T], *node[T]) {
+	if n == nil {
+		return nil, nil
+	}
+
+	if compare(n.val, key) < 0 {
+		less, rest := split(n.right, compare, key)
+		n.right = less
+		n.fix()
+
+		return n, rest
+	}
+
+	less, rest := split(n.left, compare, key)
+	n.left = rest
+	n.fix()
+
+	return less, n
+}
+
+// merge concatenates two subtrees, every element of less is less than every element of rest.
+// Asymptotic: O(log n) expected.
+func merge[T any](less, rest *node[T]) *node[T] {
+	switch {
+	case less == nil:
+		return rest
+	case rest == nil:
+		return less
+	case less.priority > rest.priority:
+		less.right = merge(less.right, rest)
+		less.fix()
+
+		return less
+	default:
+		rest.left = merge(less, rest.left)
+		rest.fix()
+
+		return rest
+	}
+}
+
+// find returns the node holding elem or nil.
+func (t *Treap[T]) find(elem T) *node[T] {
+	for n := t.root; n != nil; {
+		switch c := t.compare(elem, n.val); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+// Add inserts elem into the treap, duplicates are ignored.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Add(elem T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.find(elem) != nil {
+		return
+	}
+
+	less, rest := split(t.root, t.compare, elem)
+	t.root = merge(merge(less, &node[T]{val: elem, priority: rand.Uint64(), size: 1}), rest)
+	t.version++
+}
+
+// delete removes elem from the subtree and returns its new root.
+func (n *node[T]) delete(compare func(a, b T) int, elem T) *node[T] {
+	switch c := compare(elem, n.val); {
+	case c < 0:
+		n.left = n.left.delete(compare, elem)
+	case c > 0:
+		n.right = n.right.delete(compare, elem)
+	default:
+		return merge(n.left, n.right)
+	}
+
+	n.fix()
+
+	return n
+}
+
+// Delete removes elem from the treap and reports whether it was present.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Delete(elem T) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.find(elem) == nil {
+		return false
+	}
+
+	t.root = t.root.delete(t.compare, elem)
+	t.version++
+
+	return true
+}
+
+// Split moves elements greater than or equal to key into a new treap and returns it,
+// so the treap keeps only elements less than key.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Split(key T) *Treap[T] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rest := NewFunc(t.compare)
+	t.root, rest.root = split(t.root, t.compare, key)
+	t.version++
+
+	return rest
+}
+
+// Merge moves all elements of other to the end of the treap, leaving other empty.
+// Every element of other must be greater than every element of the treap,
+// otherwise ErrOverlap is returned and neither treap is changed.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Merge(other *Treap[T]) error {
+	if other == t {
+		if t.Size() == 0 {
+			return nil
+		}
+
+		return ErrOverlap
+	}
+
+	// Locks are taken in order of ids, so merging two treaps into each other
+	// concurrently does not deadlock.
+	first, second := t, other
+	if first.id > second.id {
+		first, second = second, first
+	}
+
+	first.mu.Lock()
+	defer first.mu.Unlock()
+
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	if t.root != nil && other.root != nil && t.compare(t.root.max().val, other.root.min().val) >= 0 {
+		return ErrOverlap
+	}
+
+	t.root, other.root = merge(t.root, other.root), nil
+	t.version++
+	other.version++
+
+	return nil
+}
+
+// Contains reports whether elem is present in the treap.
+// Asymptotic: O(log n) expected.
+func (t *Treap[T]) Contains(elem T) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.find(elem) != nil
+}
+
+// Size returns count of elements in the treap.
+// Asymptotic: O(1)
+func (t *Treap[T]) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.getSize()
+}
+
+// Height returns count of nodes on the longest path from the root to a leaf.
+// Asymptotic: O(n)
+func (t *Treap[T]) Height() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.height()
+}
+
+func (n *node[T]) height() int {
+	if n == nil {
+		return 0
+	}
+
+	return 1 + max(n.left.height(), n.right.height())
+}
+
+// check validates the subtree bounded by (lo, hi) with priorities not above top.
+func (n *node[T]) check(compare func(a, b T) int, lo, hi *T, top uint64) error {
+	if n == nil {
+		return nil
+	}
+
+	if lo != nil && compare(n.val, *lo) <= 0 || hi != nil && compare(n.val, *hi) >= 0 {
+		return fmt.Errorf("%w: %v is out of order", ErrInvariant, n.val)
+	}
+
+	if n.priority > top {
+		return fmt.Errorf("%w: node %v has priority above its parent", ErrInvariant, n.val)
+	}
+
+	if want := 1 + n.left.getSize() + n.right.getSize(); n.size != want {
+		return fmt.Errorf("%w: node %v has size %d, want %d", ErrInvariant, n.val, n.size, want)
+	}
+
+	if err := n.left.check(compare, lo, &n.val, n.priority); err != nil {
+		return err
+	}
+
+	return n.right.check(compare, &n.val, hi, n.priority)
+}
+
+// CheckInvariants verifies ordering of the elements, heap order of priorities
+// and cached subtree sizes. It is intended to be called from tests.
+// Asymptotic: O(n)
+func (t *Treap[T]) CheckInvariants() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.check(t.compare, nil, nil, ^uint64(0))
+}
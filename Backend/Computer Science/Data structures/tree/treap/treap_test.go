@@ -0,0 +1,86 @@
+package treap_test
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/settest"
+	"github.com/dzianismaroz/marathon/tree/treap"
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+var _ tree.OrderedSet[int] = (*treap.Treap[int])(nil)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	settest.Run(t, func() tree.OrderedSet[int] { return treap.New[int]() })
+}
+
+func TestSplitMerge(t *testing.T) {
+	t.Parallel()
+	settest.RunSplit(t, treap.New[int])
+}
+
+func TestTreapMergeOverlap(t *testing.T) {
+	t.Parallel()
+
+	sut, other := treap.New[int](), treap.New[int]()
+	sut.Add(10)
+	other.Add(10)
+
+	if err := sut.Merge(other); !errors.Is(err, treap.ErrOverlap) {
+		t.Errorf("expected %v, got %v", treap.ErrOverlap, err)
+	}
+}
+
+func TestTreapHeight(t *testing.T) {
+	t.Parallel()
+
+	const size = 100_000
+
+	// Sorted insertion degenerates an unbalanced tree, random priorities keep the height logarithmic.
+	sut := treap.New[int]()
+	for i := range size {
+		sut.Add(i)
+	}
+
+	if height := sut.Height(); height > 60 {
+		t.Errorf("expected height about 2.99 log n (50) for %d elements, got %d", size, height)
+	}
+}
+
+func TestTreapCustomOrder(t *testing.T) {
+	t.Parallel()
+
+	sut := treap.NewFunc(func(a, b string) int { return len(a) - len(b) })
+	for _, s := range []string{"ccc", "a", "bb", "dd"} {
+		sut.Add(s)
+	}
+
+	if got, expected := sut.SortedDesc(), []string{"ccc", "bb", "a"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+const benchSize = 1_000_000
+
+func BenchmarkSplitMerge(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(benchSize)
+
+	sut := treap.New[int]()
+	for _, key := range keys {
+		sut.Add(key)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		rest := sut.Split(keys[i%benchSize])
+		if err := sut.Merge(rest); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,853 @@
+package tree
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"sync/atomic"
+)
+
+// null is the index of the sentinel node standing for a missing child.
+const null int32 = 0
+
+type (
+	// slot is a node of ArenaTree addressed by its index in the arena.
+	slot[T any] struct {
+		val    T
+		left   int32 // less than val, or the next free slot of the free list.
+		right  int32 // greater than val.
+		height int32
+		count  uint32 // occurrences of val, always 1 unless the tree is a multiset.
+		size   uint   // count of elements in the subtree rooted at this node.
+		// expires is the deadline of val in Unix nanoseconds, 0 when it does not expire.
+		expires int64
+	}
+
+	// ArenaTree is the AVL tree of Tree that keeps its nodes in a single slice
+	// and links them by int32 indices instead of pointers. Deleted nodes are put
+	// on a free list and reused by later insertions, so a tree of any size
+	// costs a handful of allocations and gives the garbage collector no pointers
+	// to scan unless elements hold them.
+	//
+	// It has the API of Tree with the same semantics, except that slots are modified
+	// in place and never shared: the Persistent option does not apply, and Snapshot
+	// copies the nodes, so it costs O(n) on the first call after a modification.
+	// Use NewArena or NewArenaFunc to create a tree.
+	ArenaTree[T any] struct {
+		core[T]
+		slots     []slot[T] // slots[0] is the sentinel of zero height and size.
+		root      int32
+		free      int32                       // head of the free list linked through left, null when empty.
+		total     uint                        // total count of elements.
+		published atomic.Pointer[Snapshot[T]] // the latest snapshot taken.
+	}
+)
+
+// NewArena creates an empty arena-backed tree of naturally ordered elements.
+// All options but Persistent apply to it.
+func NewArena[T cmp.Ordered](opts ...Option) *ArenaTree[T] {
+	return NewArenaFunc(cmp.Compare[T], opts...)
+}
+
+// NewArenaFunc creates an empty arena-backed tree ordered by compare, see NewFunc.
+func NewArenaFunc[T any](compare func(a, b T) int, opts ...Option) *ArenaTree[T] {
+	t := &ArenaTree[T]{slots: make([]slot[T], 1)}
+	t.setup(compare, t, opts)
+
+	return t
+}
+
+// Grow makes room for n more elements without reallocating the arena.
+func (t *ArenaTree[T]) Grow(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n > cap(t.slots)-len(t.slots) {
+		slots := make([]slot[T], len(t.slots), len(t.slots)+n)
+		copy(slots, t.slots)
+		t.slots = slots
+	}
+}
+
+// alloc takes a slot for elem from the free list or appends a new one to the arena.
+func (t *ArenaTree[T]) alloc(elem T) int32 {
+	s := slot[T]{val: elem, height: 1, count: 1, size: 1}
+
+	if i := t.free; i != null {
+		t.free = t.slots[i].left
+		t.slots[i] = s
+
+		return i
+	}
+
+	if len(t.slots) > 1<<31-1 {
+		panic("tree: arena is out of int32 indices")
+	}
+
+	t.slots = append(t.slots, s)
+
+	return int32(len(t.slots) - 1)
+}
+
+// release puts slot i on the free list, dropping its value for the garbage collector.
+func (t *ArenaTree[T]) release(i int32) {
+	t.slots[i] = slot[T]{left: t.free}
+	t.free = i
+}
+
+// elem returns pointer to value of slot i, nil for the sentinel.
+func (t *ArenaTree[T]) elem(i int32) *T {
+	if i == null {
+		return nil
+	}
+
+	return &t.slots[i].val
+}
+
+// fix recalculates cached height and size of slot i from its children,
+// as well as the summary of an augmented tree.
+func (t *ArenaTree[T]) fix(i int32) {
+	s := &t.slots[i]
+	l, r := &t.slots[s.left], &t.slots[s.right]
+	s.height = 1 + max(l.height, r.height)
+	s.size = uint(s.count) + l.size + r.size
+
+	if t.augment != nil {
+		t.augment(&s.val, t.elem(s.left), t.elem(s.right))
+	}
+}
+
+func (t *ArenaTree[T]) balanceFactor(i int32) int32 {
+	return t.slots[t.slots[i].left].height - t.slots[t.slots[i].right].height
+}
+
+// rotateRight lifts the left child over slot i, see node.rotateRight.
+func (t *ArenaTree[T]) rotateRight(i int32) int32 {
+	l := t.slots[i].left
+	t.slots[i].left = t.slots[l].right
+	t.slots[l].right = i
+	t.fix(i)
+	t.fix(l)
+
+	return l
+}
+
+// rotateLeft lifts the right child over slot i, see node.rotateLeft.
+func (t *ArenaTree[T]) rotateLeft(i int32) int32 {
+	r := t.slots[i].right
+	t.slots[i].right = t.slots[r].left
+	t.slots[r].left = i
+	t.fix(i)
+	t.fix(r)
+
+	return r
+}
+
+// rebalance restores AVL property of slot i and returns the new root of the subtree.
+func (t *ArenaTree[T]) rebalance(i int32) int32 {
+	t.fix(i)
+
+	switch bf := t.balanceFactor(i); {
+	case bf > 1:
+		if t.balanceFactor(t.slots[i].left) < 0 {
+			t.slots[i].left = t.rotateLeft(t.slots[i].left)
+		}
+
+		return t.rotateRight(i)
+	case bf < -1:
+		if t.balanceFactor(t.slots[i].right) > 0 {
+			t.slots[i].right = t.rotateRight(t.slots[i].right)
+		}
+
+		return t.rotateLeft(i)
+	}
+
+	return i
+}
+
+// addAt inserts elem into the subtree of slot i and returns its new root.
+// The flag reports whether a new occurrence of elem has been inserted.
+func (t *ArenaTree[T]) addAt(i int32, elem T, dup duplicates) (int32, bool) {
+	if i == null {
+		i = t.alloc(elem)
+		t.fix(i)
+
+		return i, true
+	}
+
+	var added bool
+
+	switch c := t.compare(elem, t.slots[i].val); {
+	case c < 0:
+		var left int32
+		left, added = t.addAt(t.slots[i].left, elem, dup)
+		t.slots[i].left = left
+	case c > 0:
+		var right int32
+		right, added = t.addAt(t.slots[i].right, elem, dup)
+		t.slots[i].right = right
+	case dup == replaceDuplicates:
+		t.slots[i].val = elem
+	case dup == countDuplicates:
+		t.slots[i].count++
+		added = true
+	default:
+		return i, false
+	}
+
+	if !added {
+		// The shape is the same, but a replaced element may change summaries.
+		if t.augment != nil {
+			t.fix(i)
+		}
+
+		return i, false
+	}
+
+	return t.rebalance(i), true
+}
+
+// Add inserts elem into the tree. Duplicates are ignored unless
+// the tree is a multiset, which counts every occurrence.
+// Asymptotic: O(log n), amortized O(1) allocations.
+func (t *ArenaTree[T]) Add(elem T) {
+	t.lock()
+	defer t.unlock()
+
+	t.add(elem, t.duplicates())
+}
+
+// Put inserts elem into the tree, replacing an equal element if any, see Tree.Put.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Put(elem T) {
+	t.lock()
+	defer t.unlock()
+
+	t.add(elem, replaceDuplicates)
+}
+
+// add inserts elem under the write lock held by the caller.
+func (t *ArenaTree[T]) add(elem T, dup duplicates) bool {
+	root, added := t.addAt(t.root, elem, dup)
+	t.root = root
+
+	if added {
+		t.total++
+	}
+
+	if added || dup == replaceDuplicates {
+		t.version++
+	}
+
+	if added {
+		t.changed(EventAdd, elem)
+	}
+
+	return added
+}
+
+// detachMin unlinks the leftmost slot of the subtree of slot i
+// and returns the new root of the subtree with the detached slot.
+func (t *ArenaTree[T]) detachMin(i int32) (int32, int32) {
+	if t.slots[i].left == null {
+		return t.slots[i].right, i
+	}
+
+	left, detached := t.detachMin(t.slots[i].left)
+	t.slots[i].left = left
+
+	return t.rebalance(i), detached
+}
+
+// deleteAt removes elem from the subtree of slot i and returns its new root
+// with count of removed occurrences. Only one occurrence is removed unless all is set.
+func (t *ArenaTree[T]) deleteAt(i int32, elem T, all bool) (int32, uint) {
+	if i == null {
+		return null, 0
+	}
+
+	var deleted uint
+
+	switch c := t.compare(elem, t.slots[i].val); {
+	case c < 0:
+		var left int32
+		if left, deleted = t.deleteAt(t.slots[i].left, elem, all); deleted == 0 {
+			return i, 0
+		}
+
+		t.slots[i].left = left
+	case c > 0:
+		var right int32
+		if right, deleted = t.deleteAt(t.slots[i].right, elem, all); deleted == 0 {
+			return i, 0
+		}
+
+		t.slots[i].right = right
+	case !all && t.slots[i].count > 1:
+		t.slots[i].count--
+		deleted = 1
+	default:
+		deleted = uint(t.slots[i].count)
+		left, right := t.slots[i].left, t.slots[i].right
+		t.release(i)
+
+		if left == null {
+			return right, deleted
+		}
+
+		if right == null {
+			return left, deleted
+		}
+
+		// Both children are present: the in-order successor slot takes the place of i.
+		right, i = t.detachMin(right)
+		t.slots[i].left, t.slots[i].right = left, right
+	}
+
+	return t.rebalance(i), deleted
+}
+
+// Delete removes elem with all its occurrences from the tree and reports whether it was present.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Delete(elem T) bool {
+	t.lock()
+	defer t.unlock()
+
+	return t.delete(elem, true) > 0
+}
+
+// Remove removes a single occurrence of elem and reports whether it was present.
+// For a multiset it decrements count of elem, otherwise it is the same as Delete.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Remove(elem T) bool {
+	t.lock()
+	defer t.unlock()
+
+	return t.delete(elem, false) > 0
+}
+
+// delete removes elem under the write lock held by the caller.
+func (t *ArenaTree[T]) delete(elem T, all bool) uint {
+	var deleted uint
+	if t.root, deleted = t.deleteAt(t.root, elem, all); deleted > 0 {
+		t.total -= deleted
+		t.version++
+		t.changed(EventDelete, elem)
+	}
+
+	return deleted
+}
+
+// lookup returns the stored element equal to elem with its occurrences and deadline.
+func (t *ArenaTree[T]) lookup(elem T) (T, uint, int64, bool) {
+	i := t.find(elem)
+	s := &t.slots[i]
+
+	return s.val, uint(s.count), s.expires, i != null
+}
+
+// setDeadline sets the deadline of elem if it is present.
+func (t *ArenaTree[T]) setDeadline(elem T, at int64) {
+	if i := t.find(elem); i != null {
+		t.slots[i].expires = at
+	}
+}
+
+// length returns count of elements under the lock held by the caller.
+func (t *ArenaTree[T]) length() uint {
+	return t.total
+}
+
+// find returns slot holding elem or null.
+func (t *ArenaTree[T]) find(elem T) int32 {
+	for i := t.root; i != null; {
+		switch c := t.compare(elem, t.slots[i].val); {
+		case c < 0:
+			i = t.slots[i].left
+		case c > 0:
+			i = t.slots[i].right
+		default:
+			return i
+		}
+	}
+
+	return null
+}
+
+// edge returns the leftmost (or the rightmost when desc is set) slot of the tree.
+func (t *ArenaTree[T]) edge(desc bool) int32 {
+	i := t.root
+
+	for i != null {
+		next := t.slots[i].left
+		if desc {
+			next = t.slots[i].right
+		}
+
+		if next == null {
+			break
+		}
+
+		i = next
+	}
+
+	return i
+}
+
+// bound returns the slot with the greatest value less than elem (or the least one
+// greater than elem when above is set), accepting elem itself when inclusive is set.
+func (t *ArenaTree[T]) bound(elem T, above, inclusive bool) int32 {
+	candidate := null
+
+	for i := t.root; i != null; {
+		c := t.compare(t.slots[i].val, elem)
+		toward, away := t.slots[i].left, t.slots[i].right
+
+		if above {
+			c = -c
+			toward, away = away, toward
+		}
+
+		if c < 0 || inclusive && c == 0 {
+			candidate = i
+			i = away
+		} else {
+			i = toward
+		}
+	}
+
+	return candidate
+}
+
+// value unwraps value of slot i in the (value, ok) form used by the public API.
+func (t *ArenaTree[T]) value(i int32) (T, bool) {
+	return t.slots[i].val, i != null
+}
+
+// Contains reports whether elem is present in the tree.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Contains(elem T) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.find(elem) != null
+}
+
+// Count returns count of occurrences of elem in the tree.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Count(elem T) uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return uint(t.slots[t.find(elem)].count)
+}
+
+// Size returns count of elements in the tree, including duplicates of a multiset.
+// Asymptotic: O(1)
+func (t *ArenaTree[T]) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.total
+}
+
+// Height returns count of nodes on the longest path from the root to a leaf.
+// Asymptotic: O(1)
+func (t *ArenaTree[T]) Height() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return int(t.slots[t.root].height)
+}
+
+// Min returns the least element of the tree if presented.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Min() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.value(t.edge(false))
+}
+
+// Max returns the greatest element of the tree if presented.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Max() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.value(t.edge(true))
+}
+
+// Floor returns the greatest element less than or equal to elem.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Floor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.value(t.bound(elem, false, true))
+}
+
+// Ceiling returns the least element greater than or equal to elem.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Ceiling(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.value(t.bound(elem, true, true))
+}
+
+// Predecessor returns the greatest element strictly less than elem.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Predecessor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.value(t.bound(elem, false, false))
+}
+
+// Successor returns the least element strictly greater than elem.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Successor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.value(t.bound(elem, true, false))
+}
+
+// Select returns k-th (0-based) smallest element of the tree, see Tree.Select.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Select(k uint) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for i := t.root; i != null; {
+		s := &t.slots[i]
+		leftSize := t.slots[s.left].size
+
+		switch {
+		case k < leftSize:
+			i = s.left
+		case k >= leftSize+uint(s.count):
+			k -= leftSize + uint(s.count)
+			i = s.right
+		default:
+			return s.val, true
+		}
+	}
+
+	return t.value(null)
+}
+
+// rank returns count of elements strictly less than elem.
+func (t *ArenaTree[T]) rank(elem T) uint {
+	var rank uint
+
+	for i := t.root; i != null; {
+		s := &t.slots[i]
+		if t.compare(elem, s.val) <= 0 {
+			i = s.left
+			continue
+		}
+
+		rank += t.slots[s.left].size + uint(s.count)
+		i = s.right
+	}
+
+	return rank
+}
+
+// Rank returns count of elements strictly less than elem.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) Rank(elem T) uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.rank(elem)
+}
+
+// CountRange returns count of elements of the half-open interval [lo, hi).
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) CountRange(lo, hi T) uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.compare(hi, lo) <= 0 {
+		return 0
+	}
+
+	return t.rank(hi) - t.rank(lo)
+}
+
+// SortedAsc returns all elements of the tree in ascending order.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) SortedAsc() []T {
+	return t.sorted(false)
+}
+
+// SortedDesc returns all elements of the tree in descending order.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) SortedDesc() []T {
+	return t.sorted(true)
+}
+
+func (t *ArenaTree[T]) sorted(desc bool) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root == null {
+		return nil
+	}
+
+	result := make([]T, 0, t.total)
+	w := arenaWalker[T]{tree: t, stack: make([]int32, 0, t.slots[t.root].height), desc: desc}
+	w.pushFrom(t.root)
+
+	for i := w.pop(); i != null; i = w.pop() {
+		for range t.slots[i].count {
+			result = append(result, t.slots[i].val)
+		}
+	}
+
+	return result
+}
+
+// arenaWalker is the walker of ArenaTree, see walker.
+type arenaWalker[T any] struct {
+	tree  *ArenaTree[T]
+	stack []int32
+	desc  bool
+	enter func(T) bool // skips subtrees whose root elements it rejects, nil to walk all.
+}
+
+// enters reports whether the walk goes into the subtree of slot i.
+func (w *arenaWalker[T]) enters(i int32) bool {
+	return i != null && (w.enter == nil || w.enter(w.tree.slots[i].val))
+}
+
+// children returns children of slot i in the walking direction.
+func (w *arenaWalker[T]) children(i int32) (int32, int32) {
+	if w.desc {
+		return w.tree.slots[i].right, w.tree.slots[i].left
+	}
+
+	return w.tree.slots[i].left, w.tree.slots[i].right
+}
+
+func (w *arenaWalker[T]) pushFrom(i int32) {
+	for w.enters(i) {
+		w.stack = append(w.stack, i)
+		i, _ = w.children(i)
+	}
+}
+
+func (w *arenaWalker[T]) seek(from T, inclusive bool) {
+	w.stack = w.stack[:0]
+
+	for i := w.tree.root; w.enters(i); {
+		c := w.tree.compare(from, w.tree.slots[i].val)
+		if w.desc {
+			c = -c
+		}
+
+		first, last := w.children(i)
+		if c < 0 || inclusive && c == 0 {
+			w.stack = append(w.stack, i)
+			i = first
+		} else {
+			i = last
+		}
+	}
+}
+
+func (w *arenaWalker[T]) peek() int32 {
+	if len(w.stack) == 0 {
+		return null
+	}
+
+	return w.stack[len(w.stack)-1]
+}
+
+func (w *arenaWalker[T]) pop() int32 {
+	if len(w.stack) == 0 {
+		return null
+	}
+
+	i := w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+	_, last := w.children(i)
+	w.pushFrom(last)
+
+	return i
+}
+
+// walk returns an iterator over the tree elements with the semantics of Tree.walk:
+// the loop body is free to modify the tree, the walker re-seeks after modifications.
+func (t *ArenaTree[T]) walk(desc bool, from *T, enter, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.mu.RLock()
+
+		w := arenaWalker[T]{tree: t, stack: make([]int32, 0, t.slots[t.root].height), desc: desc, enter: enter}
+		if from == nil {
+			w.pushFrom(t.root)
+		} else {
+			w.seek(*from, true)
+		}
+
+		var (
+			version = t.version
+			current = null // slot whose occurrences are being yielded.
+			yielded uint32 // occurrences of current already yielded.
+		)
+
+		for {
+			if current == null || yielded >= t.slots[current].count {
+				if current, yielded = w.pop(), 0; current == null {
+					t.mu.RUnlock()
+					return
+				}
+			}
+
+			val := t.slots[current].val
+			yielded++
+			t.mu.RUnlock()
+
+			if within != nil && !within(val) || !yield(val) {
+				return
+			}
+
+			t.mu.RLock()
+
+			if t.version != version {
+				w.seek(val, true)
+				version = t.version
+
+				if current = w.peek(); current != null && t.compare(t.slots[current].val, val) == 0 {
+					w.pop()
+				} else {
+					current = null
+				}
+			}
+		}
+	}
+}
+
+// All returns an iterator over all elements in ascending order.
+// Asymptotic: O(n) for the full iteration, O(log n) memory.
+func (t *ArenaTree[T]) All() iter.Seq[T] {
+	return t.walk(false, nil, nil, nil)
+}
+
+// Backward returns an iterator over all elements in descending order.
+// Asymptotic: O(n) for the full iteration, O(log n) memory.
+func (t *ArenaTree[T]) Backward() iter.Seq[T] {
+	return t.walk(true, nil, nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *ArenaTree[T]) Ascend(from T) iter.Seq[T] {
+	return t.walk(false, &from, nil, nil)
+}
+
+// Descend returns an iterator over elements less than or equal to from in descending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *ArenaTree[T]) Descend(from T) iter.Seq[T] {
+	return t.walk(true, &from, nil, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *ArenaTree[T]) Range(lo, hi T) iter.Seq[T] {
+	return t.walk(false, &lo, nil, func(val T) bool { return t.compare(val, hi) < 0 })
+}
+
+// Search returns an iterator over elements in ascending order, skipping every subtree
+// whose root element is rejected by enter and stopping at the first element rejected by within,
+// see Tree.Search.
+// Asymptotic: O(log n) per yielded element at most.
+func (t *ArenaTree[T]) Search(enter, within func(elem T) bool) iter.Seq[T] {
+	return t.walk(false, nil, enter, within)
+}
+
+// check validates the subtree of slot i bounded by (lo, hi) and returns the count of its elements.
+func (t *ArenaTree[T]) check(i int32, lo, hi *T) (uint, error) {
+	if i == null {
+		return 0, nil
+	}
+
+	s := t.slots[i]
+
+	if lo != nil && t.compare(s.val, *lo) <= 0 || hi != nil && t.compare(s.val, *hi) >= 0 {
+		return 0, fmt.Errorf("%w: %v is out of order", ErrInvariant, s.val)
+	}
+
+	leftCount, err := t.check(s.left, lo, &s.val)
+	if err != nil {
+		return 0, err
+	}
+
+	rightCount, err := t.check(s.right, &s.val, hi)
+	if err != nil {
+		return 0, err
+	}
+
+	if want := 1 + max(t.slots[s.left].height, t.slots[s.right].height); s.height != want {
+		return 0, fmt.Errorf("%w: node %v has height %d, want %d", ErrInvariant, s.val, s.height, want)
+	}
+
+	if bf := t.balanceFactor(i); bf < -1 || bf > 1 {
+		return 0, fmt.Errorf("%w: node %v has balance factor %d", ErrInvariant, s.val, bf)
+	}
+
+	if s.count == 0 || s.count > 1 && !t.opts.multiset {
+		return 0, fmt.Errorf("%w: node %v has count %d", ErrInvariant, s.val, s.count)
+	}
+
+	if t.augment != nil {
+		// Recalculating a summary in a copy must not change it.
+		if val := s.val; t.augment(&val, t.elem(s.left), t.elem(s.right)) {
+			return 0, fmt.Errorf("%w: node %v has a stale summary", ErrInvariant, s.val)
+		}
+	}
+
+	count := uint(s.count) + leftCount + rightCount
+	if s.size != count {
+		return 0, fmt.Errorf("%w: node %v has size %d, want %d", ErrInvariant, s.val, s.size, count)
+	}
+
+	return count, nil
+}
+
+// CheckInvariants verifies the same invariants as Tree.CheckInvariants
+// and that every slot of the arena is either reachable or free.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) CheckInvariants() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	count, err := t.check(t.root, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if count != t.total {
+		return fmt.Errorf("%w: tree holds %d elements, but size is %d", ErrInvariant, count, t.total)
+	}
+
+	var free, reachable int
+
+	for i := t.free; i != null; i = t.slots[i].left {
+		free++
+	}
+
+	w := arenaWalker[T]{tree: t}
+	for w.pushFrom(t.root); w.pop() != null; {
+		reachable++
+	}
+
+	if used := len(t.slots) - 1 - free; used != reachable {
+		return fmt.Errorf("%w: %d slots are used by %d nodes", ErrInvariant, used, reachable)
+	}
+
+	return nil
+}
@@ -0,0 +1,698 @@
+package tree_test
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestArenaTreeMatchesTree(t *testing.T) {
+	t.Parallel()
+
+	for _, multiset := range []bool{false, true} {
+		var opts []tree.Option
+		if multiset {
+			opts = append(opts, tree.Multiset())
+		}
+
+		rnd := rand.New(rand.NewSource(15))
+		model := tree.New[int](opts...)
+		sut := tree.NewArena[int](opts...)
+
+		for i := 0; i < 5000; i++ {
+			v := rnd.Intn(300)
+
+			switch rnd.Intn(4) {
+			case 0:
+				if model.Delete(v) != sut.Delete(v) {
+					t.Fatalf("step %d: Delete(%d) differs", i, v)
+				}
+			case 1:
+				if model.Remove(v) != sut.Remove(v) {
+					t.Fatalf("step %d: Remove(%d) differs", i, v)
+				}
+			default:
+				model.Add(v)
+				sut.Add(v)
+			}
+
+			if err := sut.CheckInvariants(); err != nil {
+				t.Fatalf("step %d: %v", i, err)
+			}
+
+			type query func(elem int) (int, bool)
+
+			for name, q := range map[string][2]query{
+				"Floor":       {model.Floor, sut.Floor},
+				"Ceiling":     {model.Ceiling, sut.Ceiling},
+				"Predecessor": {model.Predecessor, sut.Predecessor},
+				"Successor":   {model.Successor, sut.Successor},
+				"Select": {
+					func(k int) (int, bool) { return model.Select(uint(k)) },
+					func(k int) (int, bool) { return sut.Select(uint(k)) },
+				},
+			} {
+				expected, expectedOK := q[0](v)
+				if got, ok := q[1](v); got != expected || ok != expectedOK {
+					t.Fatalf("step %d: %s(%d) = %d, %v, expected %d, %v", i, name, v, got, ok, expected, expectedOK)
+				}
+			}
+
+			if model.Count(v) != sut.Count(v) || model.Rank(v) != sut.Rank(v) ||
+				model.CountRange(v, v+50) != sut.CountRange(v, v+50) {
+				t.Fatalf("step %d: counts of %d differ", i, v)
+			}
+		}
+
+		if got, expected := sut.SortedAsc(), model.SortedAsc(); !reflect.DeepEqual(got, expected) {
+			t.Errorf("multiset %v: expected %v, got %v", multiset, expected, got)
+		}
+
+		if got, expected := slices.Collect(sut.Backward()), model.SortedDesc(); !reflect.DeepEqual(got, expected) {
+			t.Errorf("multiset %v: expected %v, got %v", multiset, expected, got)
+		}
+
+		if sut.Size() != model.Size() || sut.Height() != model.Height() {
+			t.Errorf("multiset %v: expected size %d and height %d, got %d and %d",
+				multiset, model.Size(), model.Height(), sut.Size(), sut.Height())
+		}
+	}
+}
+
+func TestArenaTreeIterators(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.NewArena[int]()
+	for _, v := range []int{44, 18, 1, 2, 10, 8} {
+		sut.Add(v)
+	}
+
+	tests := []struct {
+		name     string
+		got      []int
+		expected []int
+	}{
+		{name: "all", got: slices.Collect(sut.All()), expected: []int{1, 2, 8, 10, 18, 44}},
+		{name: "ascend", got: slices.Collect(sut.Ascend(9)), expected: []int{10, 18, 44}},
+		{name: "descend", got: slices.Collect(sut.Descend(17)), expected: []int{10, 8, 2, 1}},
+		{name: "range", got: slices.Collect(sut.Range(2, 18)), expected: []int{2, 8, 10}},
+		{name: "empty", got: slices.Collect(tree.NewArena[int]().All()), expected: nil},
+	}
+
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	// Deleting yielded elements while iterating reuses their slots.
+	var got []int
+
+	for v := range sut.All() {
+		got = append(got, v)
+		sut.Delete(v)
+		sut.Add(v + 100)
+
+		if v > 100 {
+			break
+		}
+	}
+
+	if expected := []int{1, 2, 8, 10, 18, 44, 101}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestArenaTreeAllocations(t *testing.T) {
+	sut := tree.NewArena[int]()
+	sut.Grow(1000)
+
+	allocs := testing.AllocsPerRun(1, func() {
+		for i := range 1000 {
+			sut.Add(i)
+		}
+
+		for i := range 1000 {
+			sut.Delete(i)
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations within grown arena, got %v", allocs)
+	}
+
+	// Freed slots are reused instead of growing the arena.
+	allocs = testing.AllocsPerRun(10, func() {
+		for i := range 1000 {
+			sut.Add(i)
+		}
+
+		for i := range 1000 {
+			sut.Delete(i)
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations for reused slots, got %v", allocs)
+	}
+}
+
+func TestArenaTreeMatchesTreeShape(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(150))
+	model := tree.New[int](tree.Multiset())
+	sut := tree.NewArena[int](tree.Multiset())
+
+	// render draws a tree with both renderers.
+	render := func(tr interface {
+		WriteDOT(w io.Writer) error
+		WriteASCII(w io.Writer) error
+	}) string {
+		var buf bytes.Buffer
+		if err := tr.WriteDOT(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := tr.WriteASCII(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		return buf.String()
+	}
+
+	for step := range 300 {
+		// The same operations keep the same shapes, as both trees balance the same way.
+		v := rnd.Intn(100)
+		if rnd.Intn(3) == 0 {
+			model.Remove(v)
+			sut.Remove(v)
+		} else {
+			model.Add(v)
+			sut.Add(v)
+		}
+
+		if step%30 != 0 {
+			continue
+		}
+
+		levels := func(seq func(func(int, int) bool)) [][2]int {
+			var got [][2]int
+			for level, val := range seq {
+				got = append(got, [2]int{level, val})
+			}
+
+			return got
+		}
+
+		for name, pair := range map[string][2]any{
+			"PreOrder":   {slices.Collect(model.PreOrder()), slices.Collect(sut.PreOrder())},
+			"PostOrder":  {slices.Collect(model.PostOrder()), slices.Collect(sut.PostOrder())},
+			"LevelOrder": {levels(model.LevelOrder()), levels(sut.LevelOrder())},
+			"Diameter":   {model.Diameter(), sut.Diameter()},
+			"IsBalanced": {model.IsBalanced(), sut.IsBalanced()},
+			"render":     {render(model), render(sut)},
+			"snapshot":   {slices.Collect(model.Snapshot().All()), slices.Collect(sut.Snapshot().All())},
+		} {
+			if !reflect.DeepEqual(pair[0], pair[1]) {
+				t.Fatalf("step %d: %s: expected %v, got %v", step, name, pair[0], pair[1])
+			}
+		}
+
+		a, b := rnd.Intn(100), rnd.Intn(100)
+
+		expectedPath, expectedOK := model.PathTo(a)
+		if got, ok := sut.PathTo(a); !reflect.DeepEqual(got, expectedPath) || ok != expectedOK {
+			t.Fatalf("step %d: PathTo(%d) = %v, %v, expected %v, %v", step, a, got, ok, expectedPath, expectedOK)
+		}
+
+		expected, expectedOK := model.LowestCommonAncestor(a, b)
+		if got, ok := sut.LowestCommonAncestor(a, b); got != expected || ok != expectedOK {
+			t.Fatalf("step %d: LowestCommonAncestor(%d, %d) = %d, %v, expected %d, %v",
+				step, a, b, got, ok, expected, expectedOK)
+		}
+	}
+}
+
+func TestArenaTreeSnapshot(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.NewArena[int]()
+	for _, v := range []int{3, 1, 2} {
+		sut.Add(v)
+	}
+
+	s := sut.Snapshot()
+	if again := sut.Snapshot(); again != s {
+		t.Error("expected an unchanged tree to return the same snapshot")
+	}
+
+	sut.Delete(1)
+	sut.Add(4)
+
+	if got, expected := s.SortedAsc(), []int{1, 2, 3}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected snapshot to keep %v, got %v", expected, got)
+	}
+
+	if s.Size() != 3 || !s.Contains(1) || s.Contains(4) {
+		t.Errorf("expected snapshot of size 3 holding 1 but not 4, got size %d", s.Size())
+	}
+
+	if got, expected := sut.Snapshot().SortedAsc(), []int{2, 3, 4}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected a new snapshot to hold %v, got %v", expected, got)
+	}
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestArenaCursor(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(151))
+	model := tree.New[int]()
+	sut := tree.NewArena[int]()
+
+	for range 100 {
+		v := rnd.Intn(200)
+		model.Add(v)
+		sut.Add(v)
+	}
+
+	expected, got := model.Cursor(), sut.Cursor()
+	if expected.Valid() != got.Valid() || got.Key() != 0 {
+		t.Fatalf("expected a new cursor to be invalid, got key %d", got.Key())
+	}
+
+	for step := range 500 {
+		var expectedOK, ok bool
+
+		switch op := rnd.Intn(10); {
+		case op == 0:
+			expectedOK, ok = expected.First(), got.First()
+		case op == 1:
+			expectedOK, ok = expected.Last(), got.Last()
+		case op == 2:
+			key := rnd.Intn(200)
+			expectedOK, ok = expected.Seek(key), got.Seek(key)
+		case op == 3:
+			key := rnd.Intn(200)
+			expectedOK, ok = expected.SeekLast(key), got.SeekLast(key)
+		case op == 4:
+			// Modifications between moves make both cursors re-seek.
+			v := rnd.Intn(200)
+			model.Delete(v)
+			sut.Delete(v)
+			model.Add(v + 1)
+			sut.Add(v + 1)
+
+			continue
+		case op < 7:
+			expectedOK, ok = expected.Prev(), got.Prev()
+		default:
+			expectedOK, ok = expected.Next(), got.Next()
+		}
+
+		if ok != expectedOK || got.Valid() != expected.Valid() || got.Key() != expected.Key() {
+			t.Fatalf("step %d: expected %d, %v, got %d, %v", step, expected.Key(), expectedOK, got.Key(), ok)
+		}
+	}
+}
+
+func TestArenaTreeEncoding(t *testing.T) {
+	t.Parallel()
+
+	elems := []int{5, -3, 5, 8, 0, 5, 13}
+	model := tree.New[int](tree.Multiset())
+	sut := tree.NewArena[int](tree.Multiset())
+
+	for _, v := range elems {
+		model.Add(v)
+		sut.Add(v)
+	}
+
+	// Either tree decodes what the other one encodes.
+	data, err := sut.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, _ := model.MarshalBinary(); !bytes.Equal(data, expected) {
+		t.Errorf("expected the binary layout of Tree %v, got %v", expected, data)
+	}
+
+	decoded := tree.NewArena[int](tree.Multiset())
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := decoded.SortedAsc(), model.SortedAsc(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("binary: expected %v, got %v", expected, got)
+	}
+
+	if err := decoded.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	text, err := json.Marshal(sut)
+	if err != nil || string(text) != `[-3,0,5,5,5,8,13]` {
+		t.Errorf("expected a sorted json array, got %s (%v)", text, err)
+	}
+
+	// Decoding replaces content, an unsorted array is accepted.
+	set := tree.NewArena[int]()
+	set.Add(100)
+
+	if err := json.Unmarshal([]byte(`[3, 1, 2, 1]`), set); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := set.SortedAsc(), []int{1, 2, 3}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("json: expected %v, got %v", expected, got)
+	}
+
+	if err := set.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+
+	var zero tree.ArenaTree[int]
+	if err := zero.UnmarshalBinary(data); !errors.Is(err, tree.ErrNoComparator) {
+		t.Errorf("expected ErrNoComparator, got %v", err)
+	}
+
+	// Occurrences are counted by uint32 in the arena.
+	huge := binary.AppendUvarint(binary.AppendVarint([]byte{1, 1}, 7), 1<<32)
+	if err := tree.NewArena[int](tree.Multiset()).UnmarshalBinary(huge); !errors.Is(err, tree.ErrMalformed) {
+		t.Errorf("expected ErrMalformed for too many occurrences, got %v", err)
+	}
+}
+
+func TestArenaTreeSetAlgebra(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(152))
+
+	for range 20 {
+		var elems [2][]int
+		for i := range elems {
+			for range rnd.Intn(30) {
+				elems[i] = append(elems[i], rnd.Intn(20))
+			}
+		}
+
+		ma := tree.BuildFromSorted(elems[0], tree.Multiset())
+		mb := tree.BuildFromSorted(elems[1], tree.Multiset())
+		a := tree.BuildArenaFromSorted(elems[0], tree.Multiset())
+		b := tree.BuildArenaFromSorted(elems[1], tree.Multiset())
+
+		for name, pair := range map[string][2]any{
+			"Union":        {ma.Union(mb).SortedAsc(), a.Union(b).SortedAsc()},
+			"Intersection": {ma.Intersection(mb).SortedAsc(), a.Intersection(b).SortedAsc()},
+			"Difference":   {ma.Difference(mb).SortedAsc(), a.Difference(b).SortedAsc()},
+			"IsSubset":     {ma.IsSubset(mb), a.IsSubset(b)},
+			"Equal":        {ma.Equal(mb), a.Equal(b)},
+			"self Equal":   {true, a.Equal(tree.BuildArenaFromSorted(slices.Sorted(slices.Values(elems[0])), tree.Multiset()))},
+		} {
+			if !reflect.DeepEqual(pair[0], pair[1]) {
+				t.Fatalf("%s of %v and %v: expected %v, got %v", name, elems[0], elems[1], pair[0], pair[1])
+			}
+		}
+
+		if err := a.Union(b).CheckInvariants(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestArenaTreeWatch(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sut := tree.NewArena[int](tree.Multiset())
+	events := sut.Watch(ctx, 10, 20)
+
+	sut.Add(5)
+	sut.Add(10)
+	sut.Add(10)
+	sut.Remove(10)
+	sut.Delete(15)
+	sut.Put(19)
+
+	if err := sut.UnmarshalJSON([]byte(`[5, 12]`)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []tree.Event[int]{
+		{Seq: 1, Kind: tree.EventAdd, Elem: 10, Count: 1},
+		{Seq: 2, Kind: tree.EventAdd, Elem: 10, Count: 2},
+		{Seq: 3, Kind: tree.EventDelete, Elem: 10, Count: 1},
+		{Seq: 4, Kind: tree.EventAdd, Elem: 19, Count: 1},
+		{Seq: 5, Kind: tree.EventDelete, Elem: 10, Count: 0},
+		{Seq: 6, Kind: tree.EventAdd, Elem: 12, Count: 1},
+		{Seq: 7, Kind: tree.EventDelete, Elem: 19, Count: 0},
+	}
+
+	for _, want := range expected {
+		if got := receive(t, events); got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	}
+
+	cancel()
+
+	for range events {
+		t.Error("expected no more events")
+	}
+}
+
+func TestArenaTreeTTL(t *testing.T) {
+	t.Parallel()
+
+	for _, lazy := range []bool{false, true} {
+		clock := newFakeClock()
+
+		opts := []tree.Option{tree.WithClock(clock)}
+		if lazy {
+			opts = append(opts, tree.LazyExpiry())
+		}
+
+		sut := tree.NewArena[int](opts...)
+
+		var expired []int
+
+		sut.OnExpire(func(elem int) {
+			expired = append(expired, elem)
+		})
+
+		sut.AddWithTTL(1, 3*time.Second)
+		sut.AddWithTTL(2, time.Second)
+		sut.AddWithTTL(3, 2*time.Second)
+		sut.Add(4)
+
+		if at, ok := sut.Deadline(2); !ok || !at.Equal(clock.Now().Add(time.Second)) {
+			t.Errorf("lazy %v: expected deadline of 2 in a second, got %v, %v", lazy, at, ok)
+		}
+
+		if _, ok := sut.Deadline(4); ok {
+			t.Errorf("lazy %v: expected no deadline of 4", lazy)
+		}
+
+		clock.Advance(2 * time.Second)
+
+		if lazy {
+			if got := sut.Expire(); got != 2 {
+				t.Errorf("lazy: expected 2 expired elements, got %d", got)
+			}
+		}
+
+		if got, expected := sut.SortedAsc(), []int{1, 4}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("lazy %v: expected %v, got %v", lazy, expected, got)
+		}
+
+		if expected := []int{2, 3}; !reflect.DeepEqual(expired, expected) {
+			t.Errorf("lazy %v: expected %v to expire, got %v", lazy, expected, expired)
+		}
+
+		if err := sut.CheckInvariants(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestArenaTreeAugmentedSearch(t *testing.T) {
+	t.Parallel()
+
+	byID := func(a, b job) int { return cmp.Compare(a.id, b.id) }
+	sut := tree.NewArenaFunc(byID, tree.WithAugment(augmentJob))
+	model := map[int]int{}
+	rnd := rand.New(rand.NewSource(153))
+
+	for step := range 2000 {
+		id := rnd.Intn(200)
+
+		switch rnd.Intn(3) {
+		case 0:
+			sut.Delete(job{id: id})
+			delete(model, id)
+		default:
+			// Put changes priorities of present jobs.
+			priority := rnd.Intn(1000)
+			sut.Put(job{id: id, priority: priority})
+			model[id] = priority
+		}
+
+		if err := sut.CheckInvariants(); err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+	}
+
+	const threshold = 900
+
+	var expected []int
+
+	for id, priority := range model {
+		if priority >= threshold {
+			expected = append(expected, id)
+		}
+	}
+
+	slices.Sort(expected)
+
+	var got []int
+
+	for j := range sut.Search(func(j job) bool { return j.top >= threshold }, nil) {
+		if j.priority >= threshold {
+			got = append(got, j.id)
+		}
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+// orderedSet is the part of the API shared by Tree and ArenaTree used in benchmarks.
+type orderedSet interface {
+	Add(elem int)
+	Delete(elem int) bool
+	Contains(elem int) bool
+	SortedAsc() []int
+}
+
+var backends = []struct {
+	name string
+	new  func() orderedSet
+}{
+	{name: "Pointer", new: func() orderedSet { return tree.New[int]() }},
+	{name: "Arena", new: func() orderedSet { return tree.NewArena[int]() }},
+}
+
+func BenchmarkBackend_AddRandom(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			sut := backend.new()
+			rnd := rand.New(rand.NewSource(1))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				sut.Add(rnd.Int())
+			}
+		})
+	}
+}
+
+func BenchmarkBackend_AddDelete(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			sut := backend.new()
+			for i := range 100_000 {
+				sut.Add(i * 2)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				v := (i % 100_000) * 2
+				sut.Delete(v)
+				sut.Add(v)
+			}
+		})
+	}
+}
+
+func BenchmarkBackend_Contains(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			sut := backend.new()
+			for i := range 1_000_000 {
+				sut.Add(i)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				sut.Contains(i % 1_000_000)
+			}
+		})
+	}
+}
+
+func BenchmarkBackend_SortedAsc(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			sut := backend.new()
+			for i := range 1_000_000 {
+				sut.Add(i)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_ = sut.SortedAsc()
+			}
+		})
+	}
+}
+
+// BenchmarkBackend_GC measures a full garbage collection with a tree of 10^6 elements alive.
+func BenchmarkBackend_GC(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			sut := backend.new()
+			for i := range 1_000_000 {
+				sut.Add(i)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+			}
+
+			runtime.KeepAlive(sut)
+		})
+	}
+}
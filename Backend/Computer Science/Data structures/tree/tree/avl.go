@@ -0,0 +1,197 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvariant is wrapped by CheckInvariants errors naming a node out of order
+// or with a wrong height, balance factor or size.
+var ErrInvariant = errors.New("tree invariant violated")
+
+// getHeight returns height of the subtree, 0 for an empty one.
+func (n *node[T]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+// getSize returns count of nodes in the subtree, 0 for an empty one.
+func (n *node[T]) getSize() uint {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// elem returns pointer to the node element, nil for an empty subtree.
+func (n *node[T]) elem() *T {
+	if n == nil {
+		return nil
+	}
+
+	return &n.val
+}
+
+// fix recalculates cached height and size of the node from its children,
+// and the summary of an augmented tree. The node must be owned by the tree.
+func (n *node[T]) fix(augment Augment[T]) {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+	n.size = n.count + n.left.getSize() + n.right.getSize()
+
+	if augment != nil {
+		augment(&n.val, n.left.elem(), n.right.elem())
+	}
+}
+
+// balanceFactor is positive when the node is left-heavy and negative when it is right-heavy.
+func (n *node[T]) balanceFactor() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.left.getHeight() - n.right.getHeight()
+}
+
+// own returns the node itself when it belongs to generation gen, otherwise its copy
+// that does: nodes of older generations are shared with snapshots and never modified.
+func (n *node[T]) own(gen uint64) *node[T] {
+	if n.gen == gen {
+		return n
+	}
+
+	clone := *n
+	clone.gen = gen
+
+	return &clone
+}
+
+// rotateRight lifts the left child over the node:
+//
+//	    n            l
+//	   / \          / \
+//	  l   c  ==>   a   n
+//	 / \              / \
+//	a   b            b   c
+func (n *node[T]) rotateRight(gen uint64, augment Augment[T]) *node[T] {
+	l := n.left.own(gen)
+	n.left = l.right
+	l.right = n
+	n.fix(augment)
+	l.fix(augment)
+
+	return l
+}
+
+// Both rotations expect the node to be owned by gen.
+
+// rotateLeft lifts the right child over the node:
+//
+//	  n                r
+//	 / \              / \
+//	a   r    ==>     n   c
+//	   / \          / \
+//	  b   c        a   b
+func (n *node[T]) rotateLeft(gen uint64, augment Augment[T]) *node[T] {
+	r := n.right.own(gen)
+	n.right = r.left
+	r.left = n
+	n.fix(augment)
+	r.fix(augment)
+
+	return r
+}
+
+// rebalance restores AVL property of the node whose children are already balanced
+// and returns the new root of the subtree. The node must be owned by gen.
+// Asymptotic: O(1)
+func (n *node[T]) rebalance(gen uint64, augment Augment[T]) *node[T] {
+	n.fix(augment)
+
+	switch bf := n.balanceFactor(); {
+	case bf > 1:
+		if n.left.balanceFactor() < 0 {
+			n.left = n.left.own(gen).rotateLeft(gen, augment)
+		}
+
+		return n.rotateRight(gen, augment)
+	case bf < -1:
+		if n.right.balanceFactor() > 0 {
+			n.right = n.right.own(gen).rotateRight(gen, augment)
+		}
+
+		return n.rotateLeft(gen, augment)
+	}
+
+	return n
+}
+
+// check validates the subtree bounded by (lo, hi) and returns the count of its elements.
+func (n *node[T]) check(compare func(a, b T) int, augment Augment[T], multiset bool, lo, hi *T) (uint, error) {
+	if n == nil {
+		return 0, nil
+	}
+
+	if lo != nil && compare(n.val, *lo) <= 0 || hi != nil && compare(n.val, *hi) >= 0 {
+		return 0, fmt.Errorf("%w: %v is out of order", ErrInvariant, n.val)
+	}
+
+	leftCount, err := n.left.check(compare, augment, multiset, lo, &n.val)
+	if err != nil {
+		return 0, err
+	}
+
+	rightCount, err := n.right.check(compare, augment, multiset, &n.val, hi)
+	if err != nil {
+		return 0, err
+	}
+
+	if want := 1 + max(n.left.getHeight(), n.right.getHeight()); n.height != want {
+		return 0, fmt.Errorf("%w: node %v has height %d, want %d", ErrInvariant, n.val, n.height, want)
+	}
+
+	if bf := n.balanceFactor(); bf < -1 || bf > 1 {
+		return 0, fmt.Errorf("%w: node %v has balance factor %d", ErrInvariant, n.val, bf)
+	}
+
+	if n.count == 0 || n.count > 1 && !multiset {
+		return 0, fmt.Errorf("%w: node %v has count %d", ErrInvariant, n.val, n.count)
+	}
+
+	if augment != nil {
+		// Recalculating a summary in a copy must not change it.
+		if val := n.val; augment(&val, n.left.elem(), n.right.elem()) {
+			return 0, fmt.Errorf("%w: node %v has a stale summary", ErrInvariant, n.val)
+		}
+	}
+
+	count := n.count + leftCount + rightCount
+	if n.size != count {
+		return 0, fmt.Errorf("%w: node %v has size %d, want %d", ErrInvariant, n.val, n.size, count)
+	}
+
+	return count, nil
+}
+
+// CheckInvariants verifies ordering of the elements, cached heights and subtree sizes,
+// occurrence counts, summaries of an augmented tree, AVL balance of every node and the tree size.
+// It is intended to be called from tests after each mutation.
+// Asymptotic: O(n)
+func (t *Tree[T]) CheckInvariants() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	count, err := t.root.check(t.compare, t.augment, t.opts.multiset, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if count != t.nodesCol {
+		return fmt.Errorf("%w: tree holds %d elements, but size is %d", ErrInvariant, count, t.nodesCol)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/settest"
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		newSet func() tree.OrderedSet[int]
+	}{
+		{
+			name:   "Tree",
+			newSet: func() tree.OrderedSet[int] { return tree.New[int]() },
+		},
+		{
+			name:   "persistent Tree",
+			newSet: func() tree.OrderedSet[int] { return tree.New[int](tree.Persistent()) },
+		},
+		{
+			name:   "ArenaTree",
+			newSet: func() tree.OrderedSet[int] { return tree.NewArena[int]() },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			settest.Run(t, tt.newSet)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package tree
+
+import (
+	"fmt"
+	"sync"
+)
+
+type (
+	// storage is the node layout of a tree, which core calls under the write lock.
+	storage[T any] interface {
+		// add inserts elem and reports whether a new occurrence of it has been inserted.
+		add(elem T, dup duplicates) bool
+		// delete removes one or all occurrences of elem and returns their count.
+		delete(elem T, all bool) uint
+		// lookup returns the stored element equal to elem with its occurrences and deadline.
+		lookup(elem T) (stored T, count uint, expires int64, found bool)
+		// setDeadline sets the deadline of elem if it is present.
+		setDeadline(elem T, at int64)
+		// length returns count of elements, including duplicates of a multiset.
+		length() uint
+	}
+
+	// core is the part of a tree independent of how its nodes are stored:
+	// the lock, ordering, options, watches and expiry, shared by Tree and ArenaTree.
+	core[T any] struct {
+		mu       sync.RWMutex
+		compare  func(a, b T) int // orders elements of the tree.
+		opts     options
+		augment  Augment[T] // nil unless the tree is augmented.
+		version  uint64     // incremented on every modification.
+		watchers []*watcher[T]
+		ttl      expiry[T]
+		store    storage[T]
+	}
+)
+
+// setup initializes the core of a tree keeping its nodes in store.
+func (c *core[T]) setup(compare func(a, b T) int, store storage[T], opts []Option) {
+	c.compare, c.store = compare, store
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+
+	if c.opts.augment != nil {
+		augment, ok := c.opts.augment.(Augment[T])
+		if !ok {
+			panic(fmt.Sprintf("tree: %T does not augment elements of type %T", c.opts.augment, *new(T)))
+		}
+
+		c.augment = augment
+	}
+}
+
+// derived initializes the core of a tree derived from another one with the same
+// ordering and options, keeping its nodes in store.
+func (c *core[T]) derived(from *core[T], store storage[T]) {
+	c.compare, c.opts, c.augment, c.store = from.compare, from.opts, from.augment, store
+}
+
+// duplicates tells how Add treats an element equal to a present one.
+func (c *core[T]) duplicates() duplicates {
+	if c.opts.multiset {
+		return countDuplicates
+	}
+
+	return ignoreDuplicates
+}
+
+// normalize drops repeated occurrences of groups loaded into a tree that is not a multiset
+// and returns the total count of their elements.
+func (c *core[T]) normalize(groups []group[T]) uint {
+	var total uint
+
+	for i := range groups {
+		if !c.opts.multiset {
+			groups[i].count = 1
+		}
+
+		total += groups[i].count
+	}
+
+	return total
+}
@@ -0,0 +1,353 @@
+package tree
+
+// Cursor is a bidirectional position over distinct elements of a Tree.
+//
+// A cursor keeps the path from the root to its element, so Next and Prev
+// are amortized O(1) while the tree is unchanged. When the tree has been modified
+// since the previous move, the cursor re-seeks from its key in O(log n) instead:
+// Next moves to the least element greater than the key and Prev to the greatest
+// element less than it, whether or not the key itself is still present.
+// So a cursor never panics or skips elements present for the whole time,
+// and elements added or deleted between moves are observed according to their position.
+//
+// A cursor is safe to use along with concurrent modifications of the tree,
+// but a single cursor must not be moved from several goroutines at once.
+type Cursor[T any] struct {
+	tree    *Tree[T]
+	path    []*node[T] // nodes from the root down to the current one.
+	key     T
+	valid   bool
+	version uint64 // version of the tree the path belongs to.
+}
+
+// Cursor returns a new cursor over the tree, not positioned on any element yet.
+func (t *Tree[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{tree: t}
+}
+
+// Valid reports whether the cursor is positioned on an element.
+func (c *Cursor[T]) Valid() bool {
+	return c.valid
+}
+
+// Key returns the element the cursor is positioned on, zero value when it is not valid.
+func (c *Cursor[T]) Key() T {
+	if !c.valid {
+		var zero T
+
+		return zero
+	}
+
+	return c.key
+}
+
+// settle makes the cursor positioned on the last node of its path, or invalid when it is empty.
+func (c *Cursor[T]) settle() bool {
+	c.version = c.tree.version
+	c.valid = len(c.path) > 0
+
+	if c.valid {
+		c.key = c.path[len(c.path)-1].val
+	}
+
+	return c.valid
+}
+
+// seek builds the path down to the least element greater than key (or the greatest one
+// less than key when desc is set), also accepting key itself when inclusive is set.
+func (c *Cursor[T]) seek(key T, desc, inclusive bool) bool {
+	c.path = c.path[:0]
+	found := 0 // length of the path to the last suitable node.
+
+	for n := c.tree.root; n != nil; {
+		c.path = append(c.path, n)
+
+		diff := c.tree.compare(n.val, key)
+		if desc {
+			diff = -diff
+		}
+
+		if diff > 0 || inclusive && diff == 0 {
+			found = len(c.path)
+			n = first(n, desc)
+		} else {
+			n = last(n, desc)
+		}
+	}
+
+	c.path = c.path[:found]
+
+	return c.settle()
+}
+
+// edge builds the path down to the least (or the greatest when desc is set) element.
+func (c *Cursor[T]) edge(desc bool) bool {
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	c.path = c.path[:0]
+
+	for n := c.tree.root; n != nil; n = first(n, desc) {
+		c.path = append(c.path, n)
+	}
+
+	return c.settle()
+}
+
+// step moves the cursor to the next element (or the previous one when desc is set).
+func (c *Cursor[T]) step(desc bool) bool {
+	if !c.valid {
+		return false
+	}
+
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	if c.version != c.tree.version {
+		return c.seek(c.key, desc, false)
+	}
+
+	if n := last(c.path[len(c.path)-1], desc); n != nil {
+		for ; n != nil; n = first(n, desc) {
+			c.path = append(c.path, n)
+		}
+
+		return c.settle()
+	}
+
+	// Climb up until coming from the first-visited side of a parent.
+	for {
+		child := c.path[len(c.path)-1]
+		c.path = c.path[:len(c.path)-1]
+
+		if len(c.path) == 0 || first(c.path[len(c.path)-1], desc) == child {
+			return c.settle()
+		}
+	}
+}
+
+// Seek positions the cursor on the least element greater than or equal to key
+// and reports whether there is one.
+// Asymptotic: O(log n)
+func (c *Cursor[T]) Seek(key T) bool {
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	return c.seek(key, false, true)
+}
+
+// SeekLast positions the cursor on the greatest element less than or equal to key
+// and reports whether there is one.
+// Asymptotic: O(log n)
+func (c *Cursor[T]) SeekLast(key T) bool {
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	return c.seek(key, true, true)
+}
+
+// First positions the cursor on the least element and reports whether the tree is not empty.
+// Asymptotic: O(log n)
+func (c *Cursor[T]) First() bool {
+	return c.edge(false)
+}
+
+// Last positions the cursor on the greatest element and reports whether the tree is not empty.
+// Asymptotic: O(log n)
+func (c *Cursor[T]) Last() bool {
+	return c.edge(true)
+}
+
+// Next moves the cursor to the following element and reports whether there is one.
+// A cursor moved past the last element becomes invalid.
+// Asymptotic: amortized O(1), O(log n) after the tree modification.
+func (c *Cursor[T]) Next() bool {
+	return c.step(false)
+}
+
+// Prev moves the cursor to the preceding element and reports whether there is one.
+// A cursor moved before the first element becomes invalid.
+// Asymptotic: amortized O(1), O(log n) after the tree modification.
+func (c *Cursor[T]) Prev() bool {
+	return c.step(true)
+}
+
+// ArenaCursor is the Cursor of an ArenaTree, positioned by the path of slots
+// from the root to its element, with the same semantics.
+type ArenaCursor[T any] struct {
+	tree    *ArenaTree[T]
+	path    []int32 // slots from the root down to the current one.
+	key     T
+	valid   bool
+	version uint64 // version of the tree the path belongs to.
+}
+
+// Cursor returns a new cursor over the tree, not positioned on any element yet.
+func (t *ArenaTree[T]) Cursor() *ArenaCursor[T] {
+	return &ArenaCursor[T]{tree: t}
+}
+
+// first returns child of slot i that precedes it in the walking direction.
+func (t *ArenaTree[T]) first(i int32, desc bool) int32 {
+	if desc {
+		return t.slots[i].right
+	}
+
+	return t.slots[i].left
+}
+
+// last returns child of slot i that follows it in the walking direction.
+func (t *ArenaTree[T]) last(i int32, desc bool) int32 {
+	if desc {
+		return t.slots[i].left
+	}
+
+	return t.slots[i].right
+}
+
+// Valid reports whether the cursor is positioned on an element.
+func (c *ArenaCursor[T]) Valid() bool {
+	return c.valid
+}
+
+// Key returns the element the cursor is positioned on, zero value when it is not valid.
+func (c *ArenaCursor[T]) Key() T {
+	if !c.valid {
+		var zero T
+
+		return zero
+	}
+
+	return c.key
+}
+
+// settle makes the cursor positioned on the last slot of its path, or invalid when it is empty.
+func (c *ArenaCursor[T]) settle() bool {
+	c.version = c.tree.version
+	c.valid = len(c.path) > 0
+
+	if c.valid {
+		c.key = c.tree.slots[c.path[len(c.path)-1]].val
+	}
+
+	return c.valid
+}
+
+// seek builds the path down to the least element greater than key (or the greatest one
+// less than key when desc is set), also accepting key itself when inclusive is set.
+func (c *ArenaCursor[T]) seek(key T, desc, inclusive bool) bool {
+	c.path = c.path[:0]
+	found := 0 // length of the path to the last suitable slot.
+
+	for i := c.tree.root; i != null; {
+		c.path = append(c.path, i)
+
+		diff := c.tree.compare(c.tree.slots[i].val, key)
+		if desc {
+			diff = -diff
+		}
+
+		if diff > 0 || inclusive && diff == 0 {
+			found = len(c.path)
+			i = c.tree.first(i, desc)
+		} else {
+			i = c.tree.last(i, desc)
+		}
+	}
+
+	c.path = c.path[:found]
+
+	return c.settle()
+}
+
+// edge builds the path down to the least (or the greatest when desc is set) element.
+func (c *ArenaCursor[T]) edge(desc bool) bool {
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	c.path = c.path[:0]
+
+	for i := c.tree.root; i != null; i = c.tree.first(i, desc) {
+		c.path = append(c.path, i)
+	}
+
+	return c.settle()
+}
+
+// step moves the cursor to the next element (or the previous one when desc is set).
+func (c *ArenaCursor[T]) step(desc bool) bool {
+	if !c.valid {
+		return false
+	}
+
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	if c.version != c.tree.version {
+		return c.seek(c.key, desc, false)
+	}
+
+	if i := c.tree.last(c.path[len(c.path)-1], desc); i != null {
+		for ; i != null; i = c.tree.first(i, desc) {
+			c.path = append(c.path, i)
+		}
+
+		return c.settle()
+	}
+
+	// Climb up until coming from the first-visited side of a parent.
+	for {
+		child := c.path[len(c.path)-1]
+		c.path = c.path[:len(c.path)-1]
+
+		if len(c.path) == 0 || c.tree.first(c.path[len(c.path)-1], desc) == child {
+			return c.settle()
+		}
+	}
+}
+
+// Seek positions the cursor on the least element greater than or equal to key
+// and reports whether there is one.
+// Asymptotic: O(log n)
+func (c *ArenaCursor[T]) Seek(key T) bool {
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	return c.seek(key, false, true)
+}
+
+// SeekLast positions the cursor on the greatest element less than or equal to key
+// and reports whether there is one.
+// Asymptotic: O(log n)
+func (c *ArenaCursor[T]) SeekLast(key T) bool {
+	c.tree.mu.RLock()
+	defer c.tree.mu.RUnlock()
+
+	return c.seek(key, true, true)
+}
+
+// First positions the cursor on the least element and reports whether the tree is not empty.
+// Asymptotic: O(log n)
+func (c *ArenaCursor[T]) First() bool {
+	return c.edge(false)
+}
+
+// Last positions the cursor on the greatest element and reports whether the tree is not empty.
+// Asymptotic: O(log n)
+func (c *ArenaCursor[T]) Last() bool {
+	return c.edge(true)
+}
+
+// Next moves the cursor to the following element and reports whether there is one.
+// A cursor moved past the last element becomes invalid.
+// Asymptotic: amortized O(1), O(log n) after the tree modification.
+func (c *ArenaCursor[T]) Next() bool {
+	return c.step(false)
+}
+
+// Prev moves the cursor to the preceding element and reports whether there is one.
+// A cursor moved before the first element becomes invalid.
+// Asymptotic: amortized O(1), O(log n) after the tree modification.
+func (c *ArenaCursor[T]) Prev() bool {
+	return c.step(true)
+}
@@ -0,0 +1,324 @@
+package tree_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+	for _, v := range []int{44, 18, 1, 2, 10, 8} {
+		sut.Add(v)
+	}
+
+	// collect positions the cursor with start and records keys visited by moves.
+	collect := func(c *tree.Cursor[int], start bool, move func() bool) []int {
+		var got []int
+		for ok := start; ok; ok = move() {
+			got = append(got, c.Key())
+		}
+
+		return got
+	}
+
+	tests := []struct {
+		name     string
+		moves    func(c *tree.Cursor[int]) []int
+		expected []int
+	}{
+		{
+			name: "first then next",
+			moves: func(c *tree.Cursor[int]) []int {
+				return collect(c, c.First(), c.Next)
+			},
+			expected: []int{1, 2, 8, 10, 18, 44},
+		},
+		{
+			name: "last then prev",
+			moves: func(c *tree.Cursor[int]) []int {
+				return collect(c, c.Last(), c.Prev)
+			},
+			expected: []int{44, 18, 10, 8, 2, 1},
+		},
+		{
+			name: "seek present element",
+			moves: func(c *tree.Cursor[int]) []int {
+				return collect(c, c.Seek(8), c.Next)
+			},
+			expected: []int{8, 10, 18, 44},
+		},
+		{
+			name: "seek absent element",
+			moves: func(c *tree.Cursor[int]) []int {
+				return collect(c, c.Seek(11), c.Next)
+			},
+			expected: []int{18, 44},
+		},
+		{
+			name: "seek above max",
+			moves: func(c *tree.Cursor[int]) []int {
+				return collect(c, c.Seek(45), c.Next)
+			},
+			expected: nil,
+		},
+		{
+			name: "seek last absent element",
+			moves: func(c *tree.Cursor[int]) []int {
+				return collect(c, c.SeekLast(9), c.Prev)
+			},
+			expected: []int{8, 2, 1},
+		},
+		{
+			name: "seek last below min",
+			moves: func(c *tree.Cursor[int]) []int {
+				return collect(c, c.SeekLast(0), c.Prev)
+			},
+			expected: nil,
+		},
+		{
+			name: "change direction",
+			moves: func(c *tree.Cursor[int]) []int {
+				c.Seek(10)
+				c.Next()
+				c.Prev()
+				c.Prev()
+
+				return []int{c.Key()}
+			},
+			expected: []int{8},
+		},
+		{
+			name: "cursor past the end stays invalid",
+			moves: func(c *tree.Cursor[int]) []int {
+				c.Last()
+				c.Next()
+
+				if c.Prev() || c.Valid() {
+					return []int{c.Key()}
+				}
+
+				return nil
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.moves(sut.Cursor()); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCursorInvalid(t *testing.T) {
+	t.Parallel()
+
+	c := tree.New[int]().Cursor()
+
+	if c.Valid() || c.Next() || c.Prev() || c.First() || c.Last() || c.Seek(1) {
+		t.Fatal("cursor over empty tree must stay invalid")
+	}
+
+	if key := c.Key(); key != 0 {
+		t.Errorf("expected zero key of invalid cursor, got %d", key)
+	}
+}
+
+func TestCursorModification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     func(sut *tree.Tree[int], key int)
+		expected []int
+	}{
+		{
+			name: "delete current element",
+			body: func(sut *tree.Tree[int], key int) {
+				sut.Delete(key)
+			},
+			expected: between(0, 10),
+		},
+		{
+			name: "delete upcoming element",
+			body: func(sut *tree.Tree[int], key int) {
+				sut.Delete(key + 1)
+			},
+			expected: []int{0, 2, 4, 6, 8},
+		},
+		{
+			name: "add upcoming and past elements",
+			body: func(sut *tree.Tree[int], key int) {
+				sut.Add(key - 100)
+				if key < 10 {
+					sut.Add(key + 10)
+				}
+			},
+			expected: between(0, 20),
+		},
+		{
+			name: "delete all elements",
+			body: func(sut *tree.Tree[int], _ int) {
+				for _, v := range between(0, 10) {
+					sut.Delete(v)
+				}
+			},
+			expected: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sut := tree.New[int]()
+			for i := range 10 {
+				sut.Add(i)
+			}
+
+			var got []int
+
+			c := sut.Cursor()
+			for ok := c.First(); ok; ok = c.Next() {
+				got = append(got, c.Key())
+				tt.body(sut, c.Key())
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCursorRandomOperations(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(12))
+	sut := tree.New[int]()
+	c := sut.Cursor()
+
+	var model []int
+
+	for i := 0; i < 5000; i++ {
+		v := rnd.Intn(200)
+
+		switch rnd.Intn(5) {
+		case 0:
+			if idx, found := slices.BinarySearch(model, v); found {
+				model = slices.Delete(model, idx, idx+1)
+			}
+
+			sut.Delete(v)
+		case 1:
+			if idx, found := slices.BinarySearch(model, v); !found {
+				model = slices.Insert(model, idx, v)
+			}
+
+			sut.Add(v)
+		case 2:
+			c.Seek(v)
+		default:
+			if !c.Valid() {
+				c.First()
+				continue
+			}
+
+			key := c.Key()
+			desc := rnd.Intn(2) == 0
+
+			var (
+				idx      int
+				expected = -1
+			)
+
+			if desc {
+				idx, _ = slices.BinarySearch(model, key)
+				idx--
+				c.Prev()
+			} else {
+				idx, _ = slices.BinarySearch(model, key+1)
+				c.Next()
+			}
+
+			if idx >= 0 && idx < len(model) {
+				expected = model[idx]
+			}
+
+			got := -1
+			if c.Valid() {
+				got = c.Key()
+			}
+
+			if got != expected {
+				t.Fatalf("step %d: moved from %d (desc %v), expected %d, got %d", i, key, desc, expected, got)
+			}
+		}
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+	for i := range 95 {
+		sut.Add(i * 2)
+	}
+
+	const pageSize = 10
+
+	var (
+		pages [][]int
+		after = -1
+	)
+
+	for {
+		c := sut.Cursor()
+
+		var page []int
+		for ok := c.Seek(after + 1); ok && len(page) < pageSize; ok = c.Next() {
+			page = append(page, c.Key())
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		pages = append(pages, page)
+		after = page[len(page)-1]
+	}
+
+	if len(pages) != 10 || len(pages[9]) != 5 {
+		t.Fatalf("expected 9 full pages and a page of 5, got %v", pages)
+	}
+
+	if got := slices.Concat(pages...); !reflect.DeepEqual(got, slices.Collect(sut.All())) {
+		t.Errorf("pages do not cover the tree: %v", got)
+	}
+}
+
+func BenchmarkCursor_Next(b *testing.B) {
+	sut := tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		sut.Add(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c := sut.Cursor()
+		for ok := c.First(); ok; ok = c.Next() {
+			_ = c.Key()
+		}
+	}
+}
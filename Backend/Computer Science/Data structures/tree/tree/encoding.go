@@ -0,0 +1,478 @@
+package tree
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// binaryFormat is the version of the MarshalBinary layout:
+//
+//	format byte | uvarint count of distinct elements | (element | uvarint occurrences)...
+//
+// Elements are written in ascending order, so the tree is rebuilt without comparisons.
+const binaryFormat byte = 1
+
+var (
+	// ErrMalformed is wrapped by errors of decoding data not written by encoding a tree
+	// of the same element type, including elements out of order of the receiver.
+	ErrMalformed = errors.New("malformed tree encoding")
+	// ErrUnsupportedType is wrapped by errors of the binary encoding of elements that are
+	// neither numbers, strings, booleans nor implement encoding.BinaryMarshaler.
+	ErrUnsupportedType = errors.New("unsupported tree element type")
+	// ErrNoComparator is returned by decoding into a zero tree, which has no ordering.
+	ErrNoComparator = errors.New("tree has no comparator, create it with New or NewFunc before decoding")
+)
+
+// group is a distinct value with count of its occurrences.
+type group[T any] struct {
+	val   T
+	count uint
+}
+
+// build creates a perfectly balanced subtree from groups sorted in ascending order.
+// Asymptotic: O(n)
+func build[T any](groups []group[T], gen uint64, augment Augment[T]) *node[T] {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	mid := len(groups) / 2
+	n := &node[T]{val: groups[mid].val, count: groups[mid].count, gen: gen}
+	n.left = build(groups[:mid], gen, augment)
+	n.right = build(groups[mid+1:], gen, augment)
+	n.fix(augment)
+
+	return n
+}
+
+// groupElems collapses equal elements into groups sorted in ascending order.
+// Sorted elems are grouped in O(n), unsorted ones are sorted first in O(n log n).
+func groupElems[T any](elems []T, compare func(a, b T) int) []group[T] {
+	if !slices.IsSortedFunc(elems, compare) {
+		elems = slices.Clone(elems)
+		slices.SortStableFunc(elems, compare)
+	}
+
+	groups := make([]group[T], 0, len(elems))
+
+	for _, elem := range elems {
+		if last := len(groups) - 1; last >= 0 && compare(groups[last].val, elem) == 0 {
+			groups[last].count++
+			continue
+		}
+
+		groups = append(groups, group[T]{val: elem, count: 1})
+	}
+
+	return groups
+}
+
+// appendGroups appends distinct values of the subtree with their occurrences in ascending order.
+func (n *node[T]) appendGroups(dst []group[T]) []group[T] {
+	if n == nil {
+		return dst
+	}
+
+	dst = n.left.appendGroups(dst)
+	dst = append(dst, group[T]{val: n.val, count: n.count})
+
+	return n.right.appendGroups(dst)
+}
+
+// load replaces content of the tree with groups sorted in ascending order
+// under the write lock held by the caller. Occurrences are dropped unless the tree is a multiset.
+func (t *Tree[T]) load(groups []group[T]) {
+	total := t.normalize(groups)
+
+	var prev []group[T]
+	if len(t.watchers) > 0 {
+		prev = t.root.appendGroups(nil)
+	}
+
+	t.root = build(groups, t.gen, t.augment)
+	t.nodesCol = total
+	t.commit()
+
+	if len(t.watchers) > 0 {
+		t.reloaded(prev, groups)
+	}
+}
+
+// encodeGroups writes groups sorted in ascending order in the MarshalBinary layout.
+func encodeGroups[T any](groups []group[T]) ([]byte, error) {
+	data := binary.AppendUvarint([]byte{binaryFormat}, uint64(len(groups)))
+
+	var err error
+
+	for _, g := range groups {
+		if data, err = appendElem(data, reflect.ValueOf(&g.val).Elem()); err != nil {
+			return nil, err
+		}
+
+		data = binary.AppendUvarint(data, uint64(g.count))
+	}
+
+	return data, nil
+}
+
+// decodeGroups reads groups written by encodeGroups, verifying their order by compare.
+func decodeGroups[T any](data []byte, compare func(a, b T) int) ([]group[T], error) {
+	if compare == nil {
+		return nil, ErrNoComparator
+	}
+
+	if len(data) == 0 || data[0] != binaryFormat {
+		return nil, fmt.Errorf("%w: unknown format", ErrMalformed)
+	}
+
+	length, read := binary.Uvarint(data[1:])
+	if read <= 0 || length > uint64(len(data)) {
+		return nil, fmt.Errorf("%w: bad length", ErrMalformed)
+	}
+
+	data = data[1+read:]
+	groups := make([]group[T], length)
+
+	var err error
+
+	for i := range groups {
+		if data, err = readElem(data, reflect.ValueOf(&groups[i].val).Elem()); err != nil {
+			return nil, err
+		}
+
+		count, read := binary.Uvarint(data)
+		if read <= 0 || count == 0 {
+			return nil, fmt.Errorf("%w: bad occurrences of element %d", ErrMalformed, i)
+		}
+
+		data = data[read:]
+		groups[i].count = uint(count)
+
+		if i > 0 && compare(groups[i-1].val, groups[i].val) >= 0 {
+			return nil, fmt.Errorf("%w: element %d is out of order", ErrMalformed, i)
+		}
+	}
+
+	if len(data) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrMalformed, len(data))
+	}
+
+	return groups, nil
+}
+
+// encodeJSON writes sorted elements as a JSON array.
+func encodeJSON[T any](sorted []T) ([]byte, error) {
+	if sorted == nil {
+		sorted = []T{}
+	}
+
+	return json.Marshal(sorted)
+}
+
+// decodeJSON reads a JSON array of elements into groups sorted by compare.
+func decodeJSON[T any](data []byte, compare func(a, b T) int) ([]group[T], error) {
+	if compare == nil {
+		return nil, ErrNoComparator
+	}
+
+	var elems []T
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return nil, err
+	}
+
+	return groupElems(elems, compare), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// Elements of integer, float, string and bool kinds are supported natively,
+// any other element type has to implement encoding.BinaryMarshaler itself.
+// Asymptotic: O(n)
+func (t *Tree[T]) MarshalBinary() ([]byte, error) {
+	return encodeGroups(t.groups())
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It replaces content of the tree, which is rebuilt perfectly balanced.
+// Asymptotic: O(n)
+func (t *Tree[T]) UnmarshalBinary(data []byte) error {
+	groups, err := decodeGroups(data, t.compare)
+	if err != nil {
+		return err
+	}
+
+	t.lock()
+	defer t.unlock()
+
+	t.load(groups)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the MarshalBinary layout.
+func (t *Tree[T]) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the UnmarshalBinary layout.
+func (t *Tree[T]) GobDecode(data []byte) error {
+	return t.UnmarshalBinary(data)
+}
+
+// MarshalJSON implements json.Marshaler: the tree is encoded
+// as an array of its elements in ascending order.
+// Asymptotic: O(n)
+func (t *Tree[T]) MarshalJSON() ([]byte, error) {
+	return encodeJSON(t.SortedAsc())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces content of the tree
+// with elements of a JSON array. A sorted array is loaded in O(n),
+// an unsorted one is sorted first in O(n log n).
+func (t *Tree[T]) UnmarshalJSON(data []byte) error {
+	groups, err := decodeJSON(data, t.compare)
+	if err != nil {
+		return err
+	}
+
+	t.lock()
+	defer t.unlock()
+
+	t.load(groups)
+
+	return nil
+}
+
+// build fills slots with a perfectly balanced subtree of groups sorted in ascending order
+// and returns its root slot.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) build(groups []group[T]) int32 {
+	if len(groups) == 0 {
+		return null
+	}
+
+	mid := len(groups) / 2
+	i := t.alloc(groups[mid].val)
+	t.slots[i].count = uint32(groups[mid].count)
+	left := t.build(groups[:mid])
+	right := t.build(groups[mid+1:])
+	t.slots[i].left, t.slots[i].right = left, right
+	t.fix(i)
+
+	return i
+}
+
+// appendGroups appends distinct values of the tree with their occurrences in ascending order.
+func (t *ArenaTree[T]) appendGroups(dst []group[T]) []group[T] {
+	w := arenaWalker[T]{tree: t, stack: make([]int32, 0, t.slots[t.root].height)}
+	w.pushFrom(t.root)
+
+	for i := w.pop(); i != null; i = w.pop() {
+		dst = append(dst, group[T]{val: t.slots[i].val, count: uint(t.slots[i].count)})
+	}
+
+	return dst
+}
+
+// load replaces content of the tree with groups sorted in ascending order, see Tree.load.
+// The arena is refilled from scratch, keeping its capacity.
+func (t *ArenaTree[T]) load(groups []group[T]) {
+	total := t.normalize(groups)
+
+	var prev []group[T]
+	if len(t.watchers) > 0 {
+		prev = t.appendGroups(nil)
+	}
+
+	clear(t.slots[1:])
+	t.slots, t.free = slices.Grow(t.slots[:1], len(groups)), null
+	t.root = t.build(groups)
+	t.total = total
+	t.version++
+
+	if len(t.watchers) > 0 {
+		t.reloaded(prev, groups)
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the layout of Tree.MarshalBinary,
+// so either tree decodes what the other one encodes.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) MarshalBinary() ([]byte, error) {
+	return encodeGroups(t.groups())
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, see Tree.UnmarshalBinary.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) UnmarshalBinary(data []byte) error {
+	groups, err := decodeGroups(data, t.compare)
+	if err != nil {
+		return err
+	}
+
+	for i, g := range groups {
+		if g.count > math.MaxUint32 {
+			return fmt.Errorf("%w: %d occurrences of element %d exceed the arena limit", ErrMalformed, g.count, i)
+		}
+	}
+
+	t.lock()
+	defer t.unlock()
+
+	t.load(groups)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the MarshalBinary layout.
+func (t *ArenaTree[T]) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with the UnmarshalBinary layout.
+func (t *ArenaTree[T]) GobDecode(data []byte) error {
+	return t.UnmarshalBinary(data)
+}
+
+// MarshalJSON implements json.Marshaler, see Tree.MarshalJSON.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) MarshalJSON() ([]byte, error) {
+	return encodeJSON(t.SortedAsc())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, see Tree.UnmarshalJSON.
+func (t *ArenaTree[T]) UnmarshalJSON(data []byte) error {
+	groups, err := decodeJSON(data, t.compare)
+	if err != nil {
+		return err
+	}
+
+	t.lock()
+	defer t.unlock()
+
+	t.load(groups)
+
+	return nil
+}
+
+// appendElem appends binary representation of addressable v to data.
+func appendElem(data []byte, v reflect.Value) ([]byte, error) {
+	if m, ok := v.Addr().Interface().(encoding.BinaryMarshaler); ok {
+		raw, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		data = binary.AppendUvarint(data, uint64(len(raw)))
+
+		return append(data, raw...), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(data, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(data, v.Uint()), nil
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(data, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		data = binary.AppendUvarint(data, uint64(v.Len()))
+
+		return append(data, v.String()...), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return append(data, 1), nil
+		}
+
+		return append(data, 0), nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+	}
+}
+
+// readElem decodes the leading element of data into addressable v and returns the rest of data.
+func readElem(data []byte, v reflect.Value) ([]byte, error) {
+	if u, ok := v.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
+		raw, rest, err := readBytes(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return rest, u.UnmarshalBinary(raw)
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, read := binary.Varint(data)
+		if read <= 0 || v.OverflowInt(x) {
+			return nil, fmt.Errorf("%w: bad %v", ErrMalformed, v.Type())
+		}
+
+		v.SetInt(x)
+
+		return data[read:], nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, read := binary.Uvarint(data)
+		if read <= 0 || v.OverflowUint(x) {
+			return nil, fmt.Errorf("%w: bad %v", ErrMalformed, v.Type())
+		}
+
+		v.SetUint(x)
+
+		return data[read:], nil
+	case reflect.Float32:
+		if len(data) < 4 {
+			return nil, fmt.Errorf("%w: bad %v", ErrMalformed, v.Type())
+		}
+
+		v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(data))))
+
+		return data[4:], nil
+	case reflect.Float64:
+		if len(data) < 8 {
+			return nil, fmt.Errorf("%w: bad %v", ErrMalformed, v.Type())
+		}
+
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(data)))
+
+		return data[8:], nil
+	case reflect.String:
+		raw, rest, err := readBytes(data)
+		if err != nil {
+			return nil, err
+		}
+
+		v.SetString(string(raw))
+
+		return rest, nil
+	case reflect.Bool:
+		if len(data) < 1 || data[0] > 1 {
+			return nil, fmt.Errorf("%w: bad %v", ErrMalformed, v.Type())
+		}
+
+		v.SetBool(data[0] == 1)
+
+		return data[1:], nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+	}
+}
+
+// readBytes decodes a length-prefixed byte string.
+func readBytes(data []byte) ([]byte, []byte, error) {
+	length, read := binary.Uvarint(data)
+	if read <= 0 || length > uint64(len(data)-read) {
+		return nil, nil, fmt.Errorf("%w: bad length", ErrMalformed)
+	}
+
+	data = data[read:]
+
+	return data[:length], data[length:], nil
+}
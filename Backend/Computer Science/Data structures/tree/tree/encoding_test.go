@@ -0,0 +1,276 @@
+package tree_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeEncodingRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	codecs := []struct {
+		name   string
+		encode func(*tree.Tree[int]) ([]byte, error)
+		decode func([]byte, *tree.Tree[int]) error
+	}{
+		{
+			name:   "binary",
+			encode: (*tree.Tree[int]).MarshalBinary,
+			decode: func(data []byte, sut *tree.Tree[int]) error {
+				return sut.UnmarshalBinary(data)
+			},
+		},
+		{
+			name: "json",
+			encode: func(sut *tree.Tree[int]) ([]byte, error) {
+				return json.Marshal(sut)
+			},
+			decode: func(data []byte, sut *tree.Tree[int]) error {
+				return json.Unmarshal(data, sut)
+			},
+		},
+		{
+			name: "gob",
+			encode: func(sut *tree.Tree[int]) ([]byte, error) {
+				var buf bytes.Buffer
+				err := gob.NewEncoder(&buf).Encode(sut)
+				return buf.Bytes(), err
+			},
+			decode: func(data []byte, sut *tree.Tree[int]) error {
+				return gob.NewDecoder(bytes.NewReader(data)).Decode(sut)
+			},
+		},
+	}
+
+	inputs := []struct {
+		name  string
+		opts  []tree.Option
+		input []int
+	}{
+		{
+			name:  "empty tree",
+			input: nil,
+		},
+		{
+			name:  "set",
+			input: []int{44, 18, 1, -2, 10, 8},
+		},
+		{
+			name:  "multiset",
+			opts:  []tree.Option{tree.Multiset()},
+			input: []int{5, 2, 9, 2, 1, 5, 2},
+		},
+		{
+			name:  "large tree",
+			input: between(-5000, 5000),
+		},
+	}
+
+	for _, codec := range codecs {
+		for _, in := range inputs {
+			t.Run(codec.name+" "+in.name, func(t *testing.T) {
+				t.Parallel()
+
+				src := tree.New[int](in.opts...)
+				for _, v := range in.input {
+					src.Add(v)
+				}
+
+				data, err := codec.encode(src)
+				if err != nil {
+					t.Fatalf("encode: %v", err)
+				}
+
+				dst := tree.New[int](in.opts...)
+				dst.Add(100_000) // content is replaced on decoding.
+
+				if err := codec.decode(data, dst); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+
+				if err := dst.CheckInvariants(); err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(dst.SortedAsc(), src.SortedAsc()) {
+					t.Errorf("expected %v, got %v", src.SortedAsc(), dst.SortedAsc())
+				}
+			})
+		}
+	}
+}
+
+func TestTreeEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scenario func(*testing.T)
+	}{
+		{
+			name: "json is a sorted array",
+			scenario: func(t *testing.T) {
+				sut := tree.New[string]()
+				sut.Add("b")
+				sut.Add("a")
+
+				data, err := json.Marshal(sut)
+				if err != nil || string(data) != `["a","b"]` {
+					t.Errorf(`expected ["a","b"], got %s (%v)`, data, err)
+				}
+
+				empty, _ := json.Marshal(tree.New[string]())
+				if string(empty) != `[]` {
+					t.Errorf("expected [], got %s", empty)
+				}
+			},
+		},
+		{
+			name: "unsorted json with duplicates",
+			scenario: func(t *testing.T) {
+				set := tree.New[int]()
+				multiset := tree.New[int](tree.Multiset())
+
+				for _, sut := range []*tree.Tree[int]{set, multiset} {
+					if err := json.Unmarshal([]byte(`[3, 1, 2, 3, 1]`), sut); err != nil {
+						t.Fatal(err)
+					}
+
+					if err := sut.CheckInvariants(); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if got := set.SortedAsc(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+					t.Errorf("expected [1 2 3], got %v", got)
+				}
+
+				if got := multiset.SortedAsc(); !reflect.DeepEqual(got, []int{1, 1, 2, 3, 3}) {
+					t.Errorf("expected [1 1 2 3 3], got %v", got)
+				}
+			},
+		},
+		{
+			name: "elements implementing binary marshaler",
+			scenario: func(t *testing.T) {
+				base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				src := tree.NewFunc(time.Time.Compare)
+				src.Add(base.Add(time.Hour))
+				src.Add(base)
+
+				data, err := src.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				dst := tree.NewFunc(time.Time.Compare)
+				if err := dst.UnmarshalBinary(data); err != nil {
+					t.Fatal(err)
+				}
+
+				if got, _ := dst.Max(); !got.Equal(base.Add(time.Hour)) {
+					t.Errorf("expected %v, got %v", base.Add(time.Hour), got)
+				}
+			},
+		},
+		{
+			name: "unsupported element type",
+			scenario: func(t *testing.T) {
+				type point struct{ x, y int }
+
+				sut := tree.NewFunc(func(a, b point) int { return a.x - b.x })
+				sut.Add(point{1, 2})
+
+				if _, err := sut.MarshalBinary(); !errors.Is(err, tree.ErrUnsupportedType) {
+					t.Errorf("expected ErrUnsupportedType, got %v", err)
+				}
+			},
+		},
+		{
+			name: "malformed binary",
+			scenario: func(t *testing.T) {
+				src := tree.New[string]()
+				src.Add("alpha")
+				src.Add("bravo")
+
+				data, _ := src.MarshalBinary()
+
+				for _, bad := range [][]byte{nil, {0}, data[:len(data)-1], append(data, 0)} {
+					if err := tree.New[string]().UnmarshalBinary(bad); !errors.Is(err, tree.ErrMalformed) {
+						t.Errorf("expected ErrMalformed for %v, got %v", bad, err)
+					}
+				}
+			},
+		},
+		{
+			name: "out of order binary",
+			scenario: func(t *testing.T) {
+				// format 1, 2 elements: 2 (zigzag 4) once, 1 (zigzag 2) once.
+				data := []byte{1, 2, 4, 1, 2, 1}
+
+				if err := tree.New[int]().UnmarshalBinary(data); !errors.Is(err, tree.ErrMalformed) {
+					t.Errorf("expected ErrMalformed, got %v", err)
+				}
+			},
+		},
+		{
+			name: "zero tree has no comparator",
+			scenario: func(t *testing.T) {
+				var sut tree.Tree[int]
+
+				if err := json.Unmarshal([]byte(`[1]`), &sut); !errors.Is(err, tree.ErrNoComparator) {
+					t.Errorf("expected ErrNoComparator, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.scenario(t)
+		})
+	}
+}
+
+func BenchmarkTree_UnmarshalBinary(b *testing.B) {
+	src := tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		src.Add(i)
+	}
+
+	data, _ := src.MarshalBinary()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := tree.New[int]().UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTree_ReloadWithAdd(b *testing.B) {
+	src := tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		src.Add(i)
+	}
+
+	sorted := src.SortedAsc()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		dst := tree.New[int]()
+		for _, v := range sorted {
+			dst.Add(v)
+		}
+	}
+}
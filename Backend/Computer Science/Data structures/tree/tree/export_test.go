@@ -0,0 +1,9 @@
+package tree
+
+// PendingDeadlines returns count of scheduled expiries, stale ones included.
+func (t *Tree[T]) PendingDeadlines() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.ttl.deadlines)
+}
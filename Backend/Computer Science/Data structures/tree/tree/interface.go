@@ -0,0 +1,33 @@
+package tree
+
+import (
+	"iter"
+)
+
+// OrderedSet is the ordered set API shared by Tree, ArenaTree and other
+// ordered collections built alongside them, so they are interchangeable
+// and verified by the same conformance suite (see package settest).
+type OrderedSet[T any] interface {
+	Add(elem T)
+	Delete(elem T) bool
+	Contains(elem T) bool
+	Size() uint
+	Min() (T, bool)
+	Max() (T, bool)
+	Floor(elem T) (T, bool)
+	Ceiling(elem T) (T, bool)
+	Predecessor(elem T) (T, bool)
+	Successor(elem T) (T, bool)
+	All() iter.Seq[T]
+	Backward() iter.Seq[T]
+	Ascend(from T) iter.Seq[T]
+	Descend(from T) iter.Seq[T]
+	Range(lo, hi T) iter.Seq[T]
+	SortedAsc() []T
+	SortedDesc() []T
+}
+
+var (
+	_ OrderedSet[int] = (*Tree[int])(nil)
+	_ OrderedSet[int] = (*ArenaTree[int])(nil)
+)
@@ -0,0 +1,190 @@
+package tree
+
+import (
+	"iter"
+)
+
+// walker performs lazy in-order traversal with an explicit stack of pending nodes,
+// so that only O(log n) memory is used regardless of the tree size.
+type walker[T any] struct {
+	compare func(a, b T) int
+	stack   []*node[T]
+	desc    bool
+	enter   func(T) bool // skips subtrees whose root elements it rejects, nil to walk all.
+}
+
+// enters reports whether the walk goes into the subtree of n.
+func (w *walker[T]) enters(n *node[T]) bool {
+	return w.enter == nil || w.enter(n.val)
+}
+
+// first returns child of n that precedes it in the walking direction.
+func first[T any](n *node[T], desc bool) *node[T] {
+	if desc {
+		return n.right
+	}
+
+	return n.left
+}
+
+// last returns child of n that follows it in the walking direction.
+func last[T any](n *node[T], desc bool) *node[T] {
+	if desc {
+		return n.left
+	}
+
+	return n.right
+}
+
+// pushFrom pushes n and the chain of its first-visited descendants.
+func (w *walker[T]) pushFrom(n *node[T]) {
+	for ; n != nil && w.enters(n); n = first(n, w.desc) {
+		w.stack = append(w.stack, n)
+	}
+}
+
+// seek positions the walker on the first value not before from in the walking direction.
+// from itself is skipped unless inclusive is set.
+func (w *walker[T]) seek(root *node[T], from T, inclusive bool) {
+	w.stack = w.stack[:0]
+
+	for n := root; n != nil && w.enters(n); {
+		c := w.compare(from, n.val)
+		if w.desc {
+			c = -c
+		}
+
+		if c < 0 || inclusive && c == 0 {
+			w.stack = append(w.stack, n)
+			n = first(n, w.desc)
+		} else {
+			n = last(n, w.desc)
+		}
+	}
+}
+
+// peek returns the current node without advancing the walker.
+func (w *walker[T]) peek() *node[T] {
+	if len(w.stack) == 0 {
+		return nil
+	}
+
+	return w.stack[len(w.stack)-1]
+}
+
+// pop returns the current node and advances the walker, nil when the walk is over.
+func (w *walker[T]) pop() *node[T] {
+	if len(w.stack) == 0 {
+		return nil
+	}
+
+	n := w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+	w.pushFrom(last(n, w.desc))
+
+	return n
+}
+
+// walk returns an iterator over the tree elements in the given direction,
+// starting from the from value (or from the very edge of the tree when it is nil),
+// skipping subtrees rejected by enter unless it is nil and lasting while within reports true.
+//
+// The read lock is held only while the walker advances, never during yield,
+// so the loop body is free to modify the tree. When the tree has been modified
+// since the previous step, the walker re-seeks from the last yielded value:
+// iteration never panics, never repeats an element and observes every element
+// that is present for the whole iteration; elements added or deleted meanwhile
+// may or may not be observed. Occurrences of a multiset value are tracked by position:
+// after k of them have been yielded, iteration continues with the (k+1)-th one if it is still present.
+func (t *Tree[T]) walk(desc bool, from *T, enter, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.mu.RLock()
+
+		w := walker[T]{compare: t.compare, stack: make([]*node[T], 0, t.root.getHeight()), desc: desc, enter: enter}
+		if from == nil {
+			w.pushFrom(t.root)
+		} else {
+			w.seek(t.root, *from, true)
+		}
+
+		version := t.version
+
+		var (
+			current *node[T] // node whose occurrences are being yielded.
+			yielded uint     // occurrences of current already yielded.
+		)
+
+		for {
+			if current == nil || yielded >= current.count {
+				if current, yielded = w.pop(), 0; current == nil {
+					t.mu.RUnlock()
+					return
+				}
+			}
+
+			val := current.val
+			yielded++
+			t.mu.RUnlock()
+
+			if within != nil && !within(val) || !yield(val) {
+				return
+			}
+
+			t.mu.RLock()
+
+			if t.version != version {
+				// Resume from the same value: its remaining occurrences
+				// (if any are still present) are yielded first.
+				w.seek(t.root, val, true)
+				version = t.version
+
+				if current = w.peek(); current != nil && w.compare(current.val, val) == 0 {
+					w.pop()
+				} else {
+					current = nil
+				}
+			}
+		}
+	}
+}
+
+// All returns an iterator over all elements in ascending order.
+// Asymptotic: O(n) for the full iteration, O(log n) memory.
+func (t *Tree[T]) All() iter.Seq[T] {
+	return t.walk(false, nil, nil, nil)
+}
+
+// Backward returns an iterator over all elements in descending order.
+// Asymptotic: O(n) for the full iteration, O(log n) memory.
+func (t *Tree[T]) Backward() iter.Seq[T] {
+	return t.walk(true, nil, nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *Tree[T]) Ascend(from T) iter.Seq[T] {
+	return t.walk(false, &from, nil, nil)
+}
+
+// Descend returns an iterator over elements less than or equal to from in descending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *Tree[T]) Descend(from T) iter.Seq[T] {
+	return t.walk(true, &from, nil, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+// Asymptotic: O(log n + k) for k yielded elements.
+func (t *Tree[T]) Range(lo, hi T) iter.Seq[T] {
+	return t.walk(false, &lo, nil, func(val T) bool { return t.compare(val, hi) < 0 })
+}
+
+// Search returns an iterator over elements in ascending order, skipping every subtree
+// whose root element is rejected by enter and stopping at the first element rejected by within.
+// It suits trees created WithAugment: enter sees the summary kept in the root element
+// and tells whether the subtree may hold wanted elements, e.g. intervals reaching a point.
+// Elements of entered subtrees are yielded even if they are not wanted themselves.
+// Iteration goes on after modifications of the tree as All does.
+// Asymptotic: O(log n) per yielded element at most.
+func (t *Tree[T]) Search(enter, within func(elem T) bool) iter.Seq[T] {
+	return t.walk(false, nil, enter, within)
+}
@@ -0,0 +1,324 @@
+package tree_test
+
+import (
+	"cmp"
+	"iter"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeIterators(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+	for _, v := range []int{44, 18, 1, 2, 10, 8} {
+		sut.Add(v)
+	}
+
+	empty := tree.New[int]()
+
+	tests := []struct {
+		name     string
+		seq      iter.Seq[int]
+		expected []int
+	}{
+		{
+			name:     "all",
+			seq:      sut.All(),
+			expected: []int{1, 2, 8, 10, 18, 44},
+		},
+		{
+			name:     "backward",
+			seq:      sut.Backward(),
+			expected: []int{44, 18, 10, 8, 2, 1},
+		},
+		{
+			name:     "ascend from present element",
+			seq:      sut.Ascend(8),
+			expected: []int{8, 10, 18, 44},
+		},
+		{
+			name:     "ascend from absent element",
+			seq:      sut.Ascend(9),
+			expected: []int{10, 18, 44},
+		},
+		{
+			name:     "ascend above max",
+			seq:      sut.Ascend(45),
+			expected: nil,
+		},
+		{
+			name:     "descend from present element",
+			seq:      sut.Descend(8),
+			expected: []int{8, 2, 1},
+		},
+		{
+			name:     "descend from absent element",
+			seq:      sut.Descend(17),
+			expected: []int{10, 8, 2, 1},
+		},
+		{
+			name:     "range is half-open",
+			seq:      sut.Range(2, 18),
+			expected: []int{2, 8, 10},
+		},
+		{
+			name:     "empty range",
+			seq:      sut.Range(11, 17),
+			expected: nil,
+		},
+		{
+			name:     "all of empty tree",
+			seq:      empty.All(),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := slices.Collect(tt.seq); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTreeIteratorsModification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     func(sut *tree.Tree[int], v int) bool
+		expected []int
+		after    []int
+	}{
+		{
+			name: "break stops iteration",
+			body: func(_ *tree.Tree[int], v int) bool {
+				return v < 3
+			},
+			expected: []int{0, 1, 2, 3},
+			after:    between(0, 10),
+		},
+		{
+			name: "delete every yielded element",
+			body: func(sut *tree.Tree[int], v int) bool {
+				sut.Delete(v)
+				return true
+			},
+			expected: between(0, 10),
+			after:    nil,
+		},
+		{
+			name: "delete upcoming elements",
+			body: func(sut *tree.Tree[int], v int) bool {
+				sut.Delete(v + 1)
+				return true
+			},
+			expected: []int{0, 2, 4, 6, 8},
+			after:    []int{0, 2, 4, 6, 8},
+		},
+		{
+			name: "add upcoming and past elements",
+			body: func(sut *tree.Tree[int], v int) bool {
+				sut.Add(v - 100)
+				if v < 10 {
+					sut.Add(v + 10)
+				}
+				return true
+			},
+			expected: between(0, 20),
+			after:    append(between(-100, -80), between(0, 20)...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sut := tree.New[int]()
+			for i := range 10 {
+				sut.Add(i)
+			}
+
+			var got []int
+
+			for v := range sut.All() {
+				got = append(got, v)
+				if !tt.body(sut, v) {
+					break
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+
+			if after := sut.SortedAsc(); !reflect.DeepEqual(after, tt.after) {
+				t.Errorf("expected tree %v after iteration, got %v", tt.after, after)
+			}
+		})
+	}
+}
+
+// between returns integers of the half-open interval [lo, hi).
+// job is an element of an augmented tree keeping the greatest priority of its subtree.
+type job struct {
+	id, priority int
+	top          int // the greatest priority in the subtree.
+}
+
+func augmentJob(j, left, right *job) bool {
+	top := j.priority
+
+	for _, child := range []*job{left, right} {
+		if child != nil {
+			top = max(top, child.top)
+		}
+	}
+
+	changed := j.top != top
+	j.top = top
+
+	return changed
+}
+
+func TestTreeAugmentedSearch(t *testing.T) {
+	t.Parallel()
+
+	byID := func(a, b job) int { return cmp.Compare(a.id, b.id) }
+
+	for _, opts := range [][]tree.Option{nil, {tree.Persistent()}} {
+		sut := tree.NewFunc(byID, append(opts, tree.WithAugment(augmentJob))...)
+		model := map[int]int{}
+		rnd := rand.New(rand.NewSource(18))
+
+		for i := 0; i < 3000; i++ {
+			id := rnd.Intn(300)
+
+			switch rnd.Intn(4) {
+			case 0:
+				sut.Delete(job{id: id})
+				delete(model, id)
+			case 1:
+				// Add keeps the priority of a present job, Put replaces it.
+				if _, ok := model[id]; !ok {
+					model[id] = rnd.Intn(1000)
+				}
+
+				sut.Add(job{id: id, priority: model[id]})
+			default:
+				model[id] = rnd.Intn(1000)
+				sut.Put(job{id: id, priority: model[id]})
+			}
+
+			if i%100 == 0 {
+				// A snapshot makes the next writes copy nodes with their summaries.
+				sut.Snapshot()
+			}
+
+			if err := sut.CheckInvariants(); err != nil {
+				t.Fatalf("step %d: %v", i, err)
+			}
+
+			threshold, limit := rnd.Intn(1000), rnd.Intn(300)
+
+			var expected []int
+
+			for id, priority := range model {
+				if priority >= threshold && id < limit {
+					expected = append(expected, id)
+				}
+			}
+
+			slices.Sort(expected)
+
+			var got []int
+
+			for j := range sut.Search(
+				func(j job) bool { return j.top >= threshold },
+				func(j job) bool { return j.id < limit },
+			) {
+				if j.priority >= threshold {
+					got = append(got, j.id)
+				}
+			}
+
+			if !slices.Equal(got, expected) {
+				t.Fatalf("step %d: jobs %d+ before %d: expected %v, got %v", i, threshold, limit, expected, got)
+			}
+		}
+	}
+}
+
+func TestTreeAugmentMismatch(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for augment of another element type")
+		}
+	}()
+
+	tree.New[int](tree.WithAugment(augmentJob))
+}
+
+func between(lo, hi int) []int {
+	result := make([]int, 0, hi-lo)
+	for i := lo; i < hi; i++ {
+		result = append(result, i)
+	}
+
+	return result
+}
+
+func TestTreeIteratorsAllocations(t *testing.T) {
+	sut := tree.New[int]()
+	for i := range 10_000 {
+		sut.Add(i)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		for v := range sut.All() {
+			_ = v
+		}
+	})
+
+	if allocs > 5 {
+		t.Errorf("expected constant count of allocations, got %v", allocs)
+	}
+}
+
+func BenchmarkTree_All(b *testing.B) {
+	sut := tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		sut.Add(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for v := range sut.All() {
+			_ = v
+		}
+	}
+}
+
+func BenchmarkTree_SortedAsc(b *testing.B) {
+	sut := tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		sut.Add(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = sut.SortedAsc()
+	}
+}
@@ -0,0 +1,153 @@
+package tree
+
+import (
+	"iter"
+)
+
+type (
+	// entry is a key-value pair ordered by key only.
+	entry[K, V any] struct {
+		key K
+		val V
+	}
+
+	// TreeMap is an ordered map on top of the self-balancing Tree,
+	// so it shares its balancing, locking and iteration semantics.
+	TreeMap[K, V any] struct {
+		tree *Tree[entry[K, V]]
+	}
+)
+
+// NewMap creates an empty map with keys ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewMap[K, V any](compare func(a, b K) int) *TreeMap[K, V] {
+	return &TreeMap[K, V]{
+		tree: NewFunc(func(a, b entry[K, V]) int {
+			return compare(a.key, b.key)
+		}),
+	}
+}
+
+// Put associates val with key, replacing the previous value if any.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Put(key K, val V) {
+	m.tree.lock()
+	defer m.tree.unlock()
+
+	m.tree.add(entry[K, V]{key: key, val: val}, replaceDuplicates)
+}
+
+// Get returns value associated with key if presented.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Get(key K) (V, bool) {
+	m.tree.mu.RLock()
+	defer m.tree.mu.RUnlock()
+
+	e, ok := m.tree.root.find(m.tree.compare, entry[K, V]{key: key}).value()
+
+	return e.val, ok
+}
+
+// Contains reports whether key is present in the map.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Contains(key K) bool {
+	return m.tree.Contains(entry[K, V]{key: key})
+}
+
+// Delete removes key from the map and reports whether it was present.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Delete(key K) bool {
+	return m.tree.Delete(entry[K, V]{key: key})
+}
+
+// Size returns count of keys in the map.
+// Asymptotic: O(1)
+func (m *TreeMap[K, V]) Size() uint {
+	return m.tree.Size()
+}
+
+// Min returns the least key with its value if presented.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Min() (K, V, bool) {
+	return unwrap(m.tree.Min())
+}
+
+// Max returns the greatest key with its value if presented.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Max() (K, V, bool) {
+	return unwrap(m.tree.Max())
+}
+
+// Floor returns the greatest key less than or equal to key with its value.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Floor(key K) (K, V, bool) {
+	return unwrap(m.tree.Floor(entry[K, V]{key: key}))
+}
+
+// Ceiling returns the least key greater than or equal to key with its value.
+// Asymptotic: O(log n)
+func (m *TreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return unwrap(m.tree.Ceiling(entry[K, V]{key: key}))
+}
+
+// All returns an iterator over all key-value pairs in ascending order of keys.
+// It follows the concurrent modification semantics of Tree.All.
+func (m *TreeMap[K, V]) All() iter.Seq2[K, V] {
+	return pairs(m.tree.All())
+}
+
+// Backward returns an iterator over all key-value pairs in descending order of keys.
+func (m *TreeMap[K, V]) Backward() iter.Seq2[K, V] {
+	return pairs(m.tree.Backward())
+}
+
+// Ascend returns an iterator over pairs with keys greater than or equal to from in ascending order.
+func (m *TreeMap[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return pairs(m.tree.Ascend(entry[K, V]{key: from}))
+}
+
+// Descend returns an iterator over pairs with keys less than or equal to from in descending order.
+func (m *TreeMap[K, V]) Descend(from K) iter.Seq2[K, V] {
+	return pairs(m.tree.Descend(entry[K, V]{key: from}))
+}
+
+// Range returns an iterator over pairs with keys of the half-open interval [lo, hi) in ascending order.
+func (m *TreeMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return pairs(m.tree.Range(entry[K, V]{key: lo}, entry[K, V]{key: hi}))
+}
+
+// Keys returns an iterator over all keys in ascending order.
+func (m *TreeMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for e := range m.tree.All() {
+			if !yield(e.key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over all values in ascending order of their keys.
+func (m *TreeMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for e := range m.tree.All() {
+			if !yield(e.val) {
+				return
+			}
+		}
+	}
+}
+
+func unwrap[K, V any](e entry[K, V], ok bool) (K, V, bool) {
+	return e.key, e.val, ok
+}
+
+func pairs[K, V any](seq iter.Seq[entry[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := range seq {
+			if !yield(e.key, e.val) {
+				return
+			}
+		}
+	}
+}
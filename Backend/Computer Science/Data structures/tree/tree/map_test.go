@@ -0,0 +1,168 @@
+package tree_test
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeMap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scenario func(*testing.T)
+	}{
+		{
+			name: "put, get and overwrite",
+			scenario: func(t *testing.T) {
+				m := tree.NewMap[int64, string](cmp.Compare[int64])
+				m.Put(2, "two")
+				m.Put(1, "one")
+				m.Put(2, "deux")
+
+				if v, ok := m.Get(2); !ok || v != "deux" {
+					t.Errorf("expected deux, got %v", v)
+				}
+
+				if _, ok := m.Get(3); ok {
+					t.Error("expected absent key to be missing")
+				}
+
+				if m.Size() != 2 {
+					t.Errorf("expected size 2, got %d", m.Size())
+				}
+			},
+		},
+		{
+			name: "delete",
+			scenario: func(t *testing.T) {
+				m := tree.NewMap[int64, string](cmp.Compare[int64])
+				m.Put(1, "one")
+
+				if !m.Delete(1) || m.Delete(1) {
+					t.Error("expected key to be deleted exactly once")
+				}
+
+				if m.Contains(1) || m.Size() != 0 {
+					t.Error("expected map to be empty")
+				}
+			},
+		},
+		{
+			name: "time keys are ordered chronologically",
+			scenario: func(t *testing.T) {
+				m := tree.NewMap[time.Time, string](time.Time.Compare)
+				base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				m.Put(base.Add(time.Hour), "b")
+				m.Put(base.Add(time.Minute), "a")
+				m.Put(base.Add(24*time.Hour), "c")
+
+				if got := slices.Collect(m.Values()); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+					t.Errorf("expected [a b c], got %v", got)
+				}
+
+				if k, v, ok := m.Floor(base.Add(2 * time.Hour)); !ok || v != "b" || !k.Equal(base.Add(time.Hour)) {
+					t.Errorf("expected b at %v, got %v at %v", base.Add(time.Hour), v, k)
+				}
+			},
+		},
+		{
+			name: "struct keys with custom comparator",
+			scenario: func(t *testing.T) {
+				type user struct {
+					last, first string
+				}
+
+				m := tree.NewMap[user, int](func(a, b user) int {
+					return cmp.Or(strings.Compare(a.last, b.last), strings.Compare(a.first, b.first))
+				})
+				m.Put(user{"smith", "john"}, 3)
+				m.Put(user{"doe", "jane"}, 1)
+				m.Put(user{"smith", "adam"}, 2)
+
+				var got []int
+				for _, v := range m.All() {
+					got = append(got, v)
+				}
+
+				if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+					t.Errorf("expected [1 2 3], got %v", got)
+				}
+
+				if k, _, _ := m.Max(); k != (user{"smith", "john"}) {
+					t.Errorf("expected smith john, got %v", k)
+				}
+			},
+		},
+		{
+			name: "ordered iteration",
+			scenario: func(t *testing.T) {
+				m := tree.NewMap[int, string](cmp.Compare[int])
+				for _, k := range []int{5, 3, 8, 1, 4} {
+					m.Put(k, strings.Repeat("x", k))
+				}
+
+				if got := slices.Collect(m.Keys()); !reflect.DeepEqual(got, []int{1, 3, 4, 5, 8}) {
+					t.Errorf("expected [1 3 4 5 8], got %v", got)
+				}
+
+				var backward []int
+				for k := range m.Backward() {
+					backward = append(backward, k)
+				}
+
+				if !reflect.DeepEqual(backward, []int{8, 5, 4, 3, 1}) {
+					t.Errorf("expected [8 5 4 3 1], got %v", backward)
+				}
+
+				var ranged []int
+				for k, v := range m.Range(3, 8) {
+					if len(v) != k {
+						t.Errorf("expected value of length %d, got %q", k, v)
+					}
+					ranged = append(ranged, k)
+				}
+
+				if !reflect.DeepEqual(ranged, []int{3, 4, 5}) {
+					t.Errorf("expected [3 4 5], got %v", ranged)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.scenario(t)
+		})
+	}
+}
+
+func BenchmarkTreeMap_Put(b *testing.B) {
+	m := tree.NewMap[int, int](cmp.Compare[int])
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m.Put(i, i)
+	}
+}
+
+func BenchmarkTreeMap_Get(b *testing.B) {
+	m := tree.NewMap[int, int](cmp.Compare[int])
+	for i := 0; i < 100_000; i++ {
+		m.Put(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m.Get(i % 100_000)
+	}
+}
@@ -0,0 +1,204 @@
+package tree_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestMultiset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scenario func(*tree.Tree[int]) any
+		expected any
+	}{
+		{
+			name: "duplicates are kept in sorted output",
+			scenario: func(sut *tree.Tree[int]) any {
+				return sut.SortedAsc()
+			},
+			expected: []int{1, 2, 2, 2, 5, 5, 9},
+		},
+		{
+			name: "duplicates are kept in descending output",
+			scenario: func(sut *tree.Tree[int]) any {
+				return sut.SortedDesc()
+			},
+			expected: []int{9, 5, 5, 2, 2, 2, 1},
+		},
+		{
+			name: "duplicates are yielded by iterators",
+			scenario: func(sut *tree.Tree[int]) any {
+				return slices.Collect(sut.Range(2, 9))
+			},
+			expected: []int{2, 2, 2, 5, 5},
+		},
+		{
+			name: "size counts duplicates",
+			scenario: func(sut *tree.Tree[int]) any {
+				return sut.Size()
+			},
+			expected: uint(7),
+		},
+		{
+			name: "count of occurrences",
+			scenario: func(sut *tree.Tree[int]) any {
+				return []uint{sut.Count(1), sut.Count(2), sut.Count(5), sut.Count(7)}
+			},
+			expected: []uint{1, 3, 2, 0},
+		},
+		{
+			name: "remove decrements count",
+			scenario: func(sut *tree.Tree[int]) any {
+				sut.Remove(2)
+				sut.Remove(9)
+				return sut.SortedAsc()
+			},
+			expected: []int{1, 2, 2, 5, 5},
+		},
+		{
+			name: "delete drops all occurrences",
+			scenario: func(sut *tree.Tree[int]) any {
+				sut.Delete(2)
+				return []any{sut.SortedAsc(), sut.Size()}
+			},
+			expected: []any{[]int{1, 5, 5, 9}, uint(4)},
+		},
+		{
+			name: "order statistics account duplicates",
+			scenario: func(sut *tree.Tree[int]) any {
+				third, _ := sut.Select(3)
+				fourth, _ := sut.Select(4)
+				return []any{third, fourth, sut.Rank(5), sut.CountRange(2, 6)}
+			},
+			expected: []any{2, 5, uint(4), uint(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sut := tree.New[int](tree.Multiset())
+			for _, v := range []int{5, 2, 9, 2, 1, 5, 2} {
+				sut.Add(v)
+			}
+
+			got := tt.scenario(sut)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+
+			if err := sut.CheckInvariants(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMultisetKthLargestStream(t *testing.T) {
+	t.Parallel()
+
+	const k = 3
+
+	sut := tree.New[int](tree.Multiset())
+	for _, v := range []int{4, 5, 8, 2} {
+		sut.Add(v)
+	}
+
+	var got []int
+
+	for _, v := range []int{3, 5, 10, 9, 4} {
+		sut.Add(v)
+
+		kth, _ := sut.Select(sut.Size() - k)
+		got = append(got, kth)
+	}
+
+	if expected := []int{4, 5, 5, 8, 8}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMultisetRandomOperations(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(11))
+	sut := tree.New[int](tree.Multiset())
+
+	var model []int
+
+	for i := 0; i < 3000; i++ {
+		v := rnd.Intn(50)
+
+		switch rnd.Intn(4) {
+		case 0:
+			if idx := slices.Index(model, v); idx >= 0 {
+				model = slices.Delete(model, idx, idx+1)
+			}
+
+			sut.Remove(v)
+		case 1:
+			model = slices.DeleteFunc(model, func(e int) bool { return e == v })
+			sut.Delete(v)
+		default:
+			model = append(model, v)
+			sut.Add(v)
+		}
+
+		if err := sut.CheckInvariants(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	sort.Ints(model)
+
+	if got := slices.Collect(sut.All()); !reflect.DeepEqual(got, model) {
+		t.Errorf("expected %v, got %v", model, got)
+	}
+}
+
+func TestMultisetIterationWithModification(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int](tree.Multiset())
+	for _, v := range []int{1, 1, 2, 3, 3} {
+		sut.Add(v)
+	}
+
+	var got []int
+
+	for v := range sut.All() {
+		got = append(got, v)
+
+		switch v {
+		case 1:
+			// occurrences added to the current value are yielded as well.
+			if len(got) == 1 {
+				sut.Add(1)
+			}
+		case 2:
+			sut.Remove(3)
+		}
+	}
+
+	if expected := []int{1, 1, 1, 2, 3}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func BenchmarkMultiset_Add(b *testing.B) {
+	sut := tree.New[int](tree.Multiset())
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Add(i % 1000)
+	}
+}
@@ -0,0 +1,59 @@
+package tree
+
+type (
+	options struct {
+		multiset   bool
+		persistent bool
+		lazyExpiry bool
+		clock      Clock
+		augment    any // Augment of the element type.
+	}
+
+	// Option configures a tree created by New or NewFunc.
+	Option func(*options)
+
+	// Augment recalculates the summary of a subtree, which an augmented tree keeps
+	// in the element at its root, from the element itself and the elements at the roots
+	// of its child subtrees, nil for empty ones. For example, an element of an interval tree
+	// keeps the greatest upper bound among intervals of its subtree. The summary must not
+	// affect the order of elements. Augment reports whether it has changed the summary.
+	Augment[T any] func(elem, left, right *T) bool
+
+	// duplicates defines how add treats a value equal to an already present one.
+	duplicates uint8
+)
+
+const (
+	ignoreDuplicates duplicates = iota
+	replaceDuplicates
+	countDuplicates
+)
+
+// Multiset makes the tree keep every occurrence of equal elements
+// instead of dropping duplicates: each node counts its occurrences,
+// so Size, SortedAsc, SortedDesc, iterators and order statistics account all of them.
+func Multiset() Option {
+	return func(o *options) {
+		o.multiset = true
+	}
+}
+
+// Persistent makes every modification of the tree copy the path from the root
+// to the changed nodes instead of updating them in place, and publish
+// the new version atomically, so Snapshot is a single atomic load
+// that never contends with writers. Writes allocate O(log n) nodes each.
+func Persistent() Option {
+	return func(o *options) {
+		o.persistent = true
+	}
+}
+
+// WithAugment makes the tree keep summaries of subtrees in their root elements:
+// augment is called for every node whose subtree has changed, after its children,
+// including nodes moved by rebalancing. Search uses summaries to skip subtrees.
+// The element type of the tree must be T, otherwise New and NewFunc panic.
+func WithAugment[T any](augment Augment[T]) Option {
+	return func(o *options) {
+		o.augment = augment
+	}
+}
@@ -0,0 +1,155 @@
+package tree
+
+// min returns the leftmost node of the subtree.
+func (n *node[T]) min() *node[T] {
+	if n == nil {
+		return nil
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+// max returns the rightmost node of the subtree.
+func (n *node[T]) max() *node[T] {
+	if n == nil {
+		return nil
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+// find returns the node holding elem or nil.
+func (n *node[T]) find(compare func(a, b T) int, elem T) *node[T] {
+	for n != nil {
+		switch c := compare(elem, n.val); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+// floor returns the node with the greatest value less than elem
+// (or equal to it when inclusive is set).
+func (n *node[T]) floor(compare func(a, b T) int, elem T, inclusive bool) *node[T] {
+	var candidate *node[T]
+
+	for n != nil {
+		switch c := compare(n.val, elem); {
+		case c < 0 || inclusive && c == 0:
+			candidate = n
+			n = n.right
+		default:
+			n = n.left
+		}
+	}
+
+	return candidate
+}
+
+// ceiling returns the node with the least value greater than elem
+// (or equal to it when inclusive is set).
+func (n *node[T]) ceiling(compare func(a, b T) int, elem T, inclusive bool) *node[T] {
+	var candidate *node[T]
+
+	for n != nil {
+		switch c := compare(n.val, elem); {
+		case c > 0 || inclusive && c == 0:
+			candidate = n
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+
+	return candidate
+}
+
+// value unwraps node value in the (value, ok) form used by the public API.
+func (n *node[T]) value() (T, bool) {
+	if n == nil {
+		var zero T
+
+		return zero, false
+	}
+
+	return n.val, true
+}
+
+// Contains reports whether elem is present in the tree.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Contains(elem T) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.find(t.compare, elem) != nil
+}
+
+// Min returns the least element of the tree if presented.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Min() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.min().value()
+}
+
+// Max returns the greatest element of the tree if presented.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Max() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.max().value()
+}
+
+// Floor returns the greatest element less than or equal to elem.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Floor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.floor(t.compare, elem, true).value()
+}
+
+// Ceiling returns the least element greater than or equal to elem.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Ceiling(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.ceiling(t.compare, elem, true).value()
+}
+
+// Predecessor returns the greatest element strictly less than elem.
+// elem itself does not have to be present in the tree.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Predecessor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.floor(t.compare, elem, false).value()
+}
+
+// Successor returns the least element strictly greater than elem.
+// elem itself does not have to be present in the tree.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Successor(elem T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.ceiling(t.compare, elem, false).value()
+}
@@ -0,0 +1,151 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeQueries(t *testing.T) {
+	t.Parallel()
+
+	type result struct {
+		val int
+		ok  bool
+	}
+
+	sut := tree.New[int]()
+	for _, v := range []int{44, 18, 1, 2, 10, 8} {
+		sut.Add(v)
+	}
+
+	empty := tree.New[int]()
+
+	tests := []struct {
+		name     string
+		query    func() (int, bool)
+		expected result
+	}{
+		{
+			name:     "min",
+			query:    sut.Min,
+			expected: result{1, true},
+		},
+		{
+			name:     "max",
+			query:    sut.Max,
+			expected: result{44, true},
+		},
+		{
+			name:     "min of empty tree",
+			query:    empty.Min,
+			expected: result{0, false},
+		},
+		{
+			name:     "max of empty tree",
+			query:    empty.Max,
+			expected: result{0, false},
+		},
+		{
+			name:     "floor of present element",
+			query:    func() (int, bool) { return sut.Floor(10) },
+			expected: result{10, true},
+		},
+		{
+			name:     "floor of absent element",
+			query:    func() (int, bool) { return sut.Floor(17) },
+			expected: result{10, true},
+		},
+		{
+			name:     "floor below min",
+			query:    func() (int, bool) { return sut.Floor(0) },
+			expected: result{0, false},
+		},
+		{
+			name:     "ceiling of present element",
+			query:    func() (int, bool) { return sut.Ceiling(18) },
+			expected: result{18, true},
+		},
+		{
+			name:     "ceiling of absent element",
+			query:    func() (int, bool) { return sut.Ceiling(11) },
+			expected: result{18, true},
+		},
+		{
+			name:     "ceiling above max",
+			query:    func() (int, bool) { return sut.Ceiling(45) },
+			expected: result{0, false},
+		},
+		{
+			name:     "predecessor of present element",
+			query:    func() (int, bool) { return sut.Predecessor(10) },
+			expected: result{8, true},
+		},
+		{
+			name:     "predecessor of min",
+			query:    func() (int, bool) { return sut.Predecessor(1) },
+			expected: result{0, false},
+		},
+		{
+			name:     "successor of present element",
+			query:    func() (int, bool) { return sut.Successor(18) },
+			expected: result{44, true},
+		},
+		{
+			name:     "successor of absent element",
+			query:    func() (int, bool) { return sut.Successor(3) },
+			expected: result{8, true},
+		},
+		{
+			name:     "successor of max",
+			query:    func() (int, bool) { return sut.Successor(44) },
+			expected: result{0, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			val, ok := tt.query()
+			if got := (result{val, ok}); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTreeContains(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[string]()
+	sut.Add("alpha")
+	sut.Add("bravo")
+
+	if !sut.Contains("alpha") || !sut.Contains("bravo") {
+		t.Error("expected added elements to be present")
+	}
+
+	sut.Delete("alpha")
+
+	if sut.Contains("alpha") {
+		t.Error("expected deleted element to be absent")
+	}
+
+	if sut.Contains("charlie") {
+		t.Error("expected never added element to be absent")
+	}
+}
+
+func BenchmarkTree_Contains(b *testing.B) {
+	sut := tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		sut.Add(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Contains(i % 100_000)
+	}
+}
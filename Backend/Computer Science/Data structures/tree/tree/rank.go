@@ -0,0 +1,71 @@
+package tree
+
+// selectAt returns the node holding k-th (0-based) smallest value of the subtree or nil.
+func (n *node[T]) selectAt(k uint) *node[T] {
+	for n != nil {
+		leftSize := n.left.getSize()
+
+		switch {
+		case k < leftSize:
+			n = n.left
+		case k >= leftSize+n.count:
+			k -= leftSize + n.count
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+// rank returns count of values of the subtree strictly less than elem.
+func (n *node[T]) rank(compare func(a, b T) int, elem T) uint {
+	var rank uint
+
+	for n != nil {
+		if compare(elem, n.val) <= 0 {
+			n = n.left
+			continue
+		}
+
+		rank += n.left.getSize() + n.count
+		n = n.right
+	}
+
+	return rank
+}
+
+// Select returns k-th (0-based) smallest element of the tree,
+// so Select(0) is the minimum and Select(Size()-1) is the maximum.
+// k-th largest element is Select(Size()-k).
+// Asymptotic: O(log n)
+func (t *Tree[T]) Select(k uint) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.selectAt(k).value()
+}
+
+// Rank returns count of elements strictly less than elem.
+// For a present elem it is the index of its first occurrence in SortedAsc.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Rank(elem T) uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.rank(t.compare, elem)
+}
+
+// CountRange returns count of elements of the half-open interval [lo, hi).
+// Asymptotic: O(log n)
+func (t *Tree[T]) CountRange(lo, hi T) uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.compare(hi, lo) <= 0 {
+		return 0
+	}
+
+	return t.root.rank(t.compare, hi) - t.root.rank(t.compare, lo)
+}
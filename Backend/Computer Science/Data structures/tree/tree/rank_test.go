@@ -0,0 +1,132 @@
+package tree_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeOrderStatistics(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+	for _, v := range []int{44, 18, 1, 2, 10, 8} {
+		sut.Add(v)
+	}
+
+	tests := []struct {
+		name     string
+		query    func() any
+		expected any
+	}{
+		{
+			name:     "select minimum",
+			query:    func() any { v, _ := sut.Select(0); return v },
+			expected: 1,
+		},
+		{
+			name:     "select maximum",
+			query:    func() any { v, _ := sut.Select(sut.Size() - 1); return v },
+			expected: 44,
+		},
+		{
+			name:     "select 3rd largest",
+			query:    func() any { v, _ := sut.Select(sut.Size() - 3); return v },
+			expected: 10,
+		},
+		{
+			name:     "select out of range",
+			query:    func() any { _, ok := sut.Select(6); return ok },
+			expected: false,
+		},
+		{
+			name:     "rank of present element",
+			query:    func() any { return sut.Rank(10) },
+			expected: uint(3),
+		},
+		{
+			name:     "rank of absent element",
+			query:    func() any { return sut.Rank(11) },
+			expected: uint(4),
+		},
+		{
+			name:     "rank below min",
+			query:    func() any { return sut.Rank(-5) },
+			expected: uint(0),
+		},
+		{
+			name:     "rank above max",
+			query:    func() any { return sut.Rank(100) },
+			expected: uint(6),
+		},
+		{
+			name:     "count range",
+			query:    func() any { return sut.CountRange(2, 18) },
+			expected: uint(3),
+		},
+		{
+			name:     "count inverted range",
+			query:    func() any { return sut.CountRange(18, 2) },
+			expected: uint(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.query(); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTreeOrderStatisticsRandom(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(3))
+	sut := tree.New[int]()
+
+	for i := 0; i < 2000; i++ {
+		if v := rnd.Intn(500); rnd.Intn(4) == 0 {
+			sut.Delete(v)
+		} else {
+			sut.Add(v)
+		}
+
+		if err := sut.CheckInvariants(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	sorted := sut.SortedAsc()
+
+	for k, expected := range sorted {
+		if got, ok := sut.Select(uint(k)); !ok || got != expected {
+			t.Fatalf("select %d: expected %d, got %d", k, expected, got)
+		}
+	}
+
+	for v := -1; v <= 501; v++ {
+		expected, _ := slices.BinarySearch(sorted, v)
+		if got := sut.Rank(v); got != uint(expected) {
+			t.Fatalf("rank %d: expected %d, got %d", v, expected, got)
+		}
+	}
+}
+
+func BenchmarkTree_Select(b *testing.B) {
+	sut := tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		sut.Add(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Select(uint(i % 100_000))
+	}
+}
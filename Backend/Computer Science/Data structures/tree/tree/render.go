@@ -0,0 +1,158 @@
+package tree
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// errWriter remembers the first write error, so rendering code can stay linear.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...any) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
+
+// label describes a node: its value, occurrences (for duplicates), height and subtree size.
+func (n *node[T]) label() string {
+	if n.count > 1 {
+		return fmt.Sprintf("%v ×%d h=%d size=%d", n.val, n.count, n.height, n.size)
+	}
+
+	return fmt.Sprintf("%v h=%d size=%d", n.val, n.height, n.size)
+}
+
+// WriteDOT writes the actual node structure of the tree in Graphviz DOT format:
+//
+//	go run . render -dot < numbers.txt | dot -Tsvg > tree.svg
+//
+// A missing child of a node with the other child present is drawn
+// as an invisible node, so left and right children keep their sides.
+// Asymptotic: O(n)
+func (t *Tree[T]) WriteDOT(w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return writeDOT(w, t.root)
+}
+
+// writeDOT writes the subtree of root in Graphviz DOT format, see Tree.WriteDOT.
+func writeDOT[T any](w io.Writer, root *node[T]) error {
+	ew := &errWriter{w: w}
+	ew.printf("digraph tree {\n\tnode [shape=box, fontname=monospace];\n")
+
+	var id int
+
+	var walk func(n *node[T]) int
+	walk = func(n *node[T]) int {
+		self := id
+		id++
+
+		ew.printf("\tn%d [label=%s];\n", self, strconv.Quote(n.label()))
+
+		if n.left == nil && n.right == nil {
+			return self
+		}
+
+		for side, child := range []*node[T]{n.left, n.right} {
+			name := [...]string{"L", "R"}[side]
+
+			if child == nil {
+				ew.printf("\tn%d [style=invis];\n\tn%d -> n%d [style=invis];\n", id, self, id)
+				id++
+
+				continue
+			}
+
+			ew.printf("\tn%d -> n%d [label=%s];\n", self, walk(child), name)
+		}
+
+		return self
+	}
+
+	if root != nil {
+		walk(root)
+	}
+
+	ew.printf("}\n")
+
+	return ew.err
+}
+
+// WriteASCII writes the actual node structure of the tree as an indented drawing
+// where every node lists its left (L) and right (R) children:
+//
+//	10 h=3 size=6
+//	├── L: 2 h=2 size=3
+//	│   ├── L: 1 h=1 size=1
+//	│   └── R: 8 h=1 size=1
+//	└── R: 18 h=2 size=2
+//	    └── R: 44 h=1 size=1
+//
+// Asymptotic: O(n)
+func (t *Tree[T]) WriteASCII(w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return writeASCII(w, t.root)
+}
+
+// writeASCII writes the subtree of root as an indented drawing, see Tree.WriteASCII.
+func writeASCII[T any](w io.Writer, root *node[T]) error {
+	ew := &errWriter{w: w}
+
+	if root == nil {
+		ew.printf("(empty)\n")
+		return ew.err
+	}
+
+	var walk func(n *node[T], prefix string)
+	walk = func(n *node[T], prefix string) {
+		type child struct {
+			side string
+			node *node[T]
+		}
+
+		var children []child
+
+		if n.left != nil {
+			children = append(children, child{"L", n.left})
+		}
+
+		if n.right != nil {
+			children = append(children, child{"R", n.right})
+		}
+
+		for i, c := range children {
+			branch, indent := "├── ", "│   "
+			if i == len(children)-1 {
+				branch, indent = "└── ", "    "
+			}
+
+			ew.printf("%s%s%s: %s\n", prefix, branch, c.side, c.node.label())
+			walk(c.node, prefix+indent)
+		}
+	}
+
+	ew.printf("%s\n", root.label())
+	walk(root, "")
+
+	return ew.err
+}
+
+// WriteDOT writes the actual slot structure of the tree in Graphviz DOT format, see Tree.WriteDOT.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) WriteDOT(w io.Writer) error {
+	return writeDOT(w, t.Snapshot().root)
+}
+
+// WriteASCII writes the actual slot structure of the tree as an indented drawing, see Tree.WriteASCII.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) WriteASCII(w io.Writer) error {
+	return writeASCII(w, t.Snapshot().root)
+}
@@ -0,0 +1,119 @@
+package tree_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+type failingWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
+
+func TestTreeRender(t *testing.T) {
+	t.Parallel()
+
+	build := func(opts []tree.Option, values ...int) *tree.Tree[int] {
+		sut := tree.New[int](opts...)
+		for _, v := range values {
+			sut.Add(v)
+		}
+
+		return sut
+	}
+
+	tests := []struct {
+		name     string
+		render   func(*strings.Builder) error
+		expected string
+	}{
+		{
+			name: "ascii",
+			render: func(sb *strings.Builder) error {
+				return build(nil, 44, 18, 1, 2, 10, 8).WriteASCII(sb)
+			},
+			expected: "10 h=3 size=6\n" +
+				"├── L: 2 h=2 size=3\n" +
+				"│   ├── L: 1 h=1 size=1\n" +
+				"│   └── R: 8 h=1 size=1\n" +
+				"└── R: 18 h=2 size=2\n" +
+				"    └── R: 44 h=1 size=1\n",
+		},
+		{
+			name: "ascii with duplicates",
+			render: func(sb *strings.Builder) error {
+				return build([]tree.Option{tree.Multiset()}, 2, 1, 2).WriteASCII(sb)
+			},
+			expected: "2 ×2 h=2 size=3\n" +
+				"└── L: 1 h=1 size=1\n",
+		},
+		{
+			name: "ascii of empty tree",
+			render: func(sb *strings.Builder) error {
+				return build(nil).WriteASCII(sb)
+			},
+			expected: "(empty)\n",
+		},
+		{
+			name: "dot",
+			render: func(sb *strings.Builder) error {
+				return build(nil, 2, 1, 3, 4).WriteDOT(sb)
+			},
+			expected: "digraph tree {\n" +
+				"\tnode [shape=box, fontname=monospace];\n" +
+				"\tn0 [label=\"2 h=3 size=4\"];\n" +
+				"\tn1 [label=\"1 h=1 size=1\"];\n" +
+				"\tn0 -> n1 [label=L];\n" +
+				"\tn2 [label=\"3 h=2 size=2\"];\n" +
+				"\tn3 [style=invis];\n" +
+				"\tn2 -> n3 [style=invis];\n" +
+				"\tn4 [label=\"4 h=1 size=1\"];\n" +
+				"\tn2 -> n4 [label=R];\n" +
+				"\tn0 -> n2 [label=R];\n" +
+				"}\n",
+		},
+		{
+			name: "dot of empty tree",
+			render: func(sb *strings.Builder) error {
+				return build(nil).WriteDOT(sb)
+			},
+			expected: "digraph tree {\n\tnode [shape=box, fontname=monospace];\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sb strings.Builder
+			if err := tt.render(&sb); err != nil {
+				t.Fatal(err)
+			}
+
+			if sb.String() != tt.expected {
+				t.Errorf("expected\n%s\ngot\n%s", tt.expected, sb.String())
+			}
+		})
+	}
+}
+
+func TestTreeRenderWriteError(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+	sut.Add(1)
+
+	if err := sut.WriteASCII(failingWriter{}); !errors.Is(err, errWrite) {
+		t.Errorf("expected write error, got %v", err)
+	}
+
+	if err := sut.WriteDOT(failingWriter{}); !errors.Is(err, errWrite) {
+		t.Errorf("expected write error, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package tree
+
+import (
+	"cmp"
+)
+
+// BuildFromSorted creates a perfectly balanced tree of elements in O(n),
+// instead of O(n log n) for adding them one by one.
+// Unsorted elements are accepted as well but sorted first in O(n log n).
+func BuildFromSorted[T cmp.Ordered](sorted []T, opts ...Option) *Tree[T] {
+	return BuildFromSortedFunc(sorted, cmp.Compare[T], opts...)
+}
+
+// BuildFromSortedFunc is BuildFromSorted for elements ordered by compare.
+func BuildFromSortedFunc[T any](sorted []T, compare func(a, b T) int, opts ...Option) *Tree[T] {
+	t := NewFunc(compare, opts...)
+	t.load(groupElems(sorted, compare))
+
+	return t
+}
+
+// groups returns distinct elements of the tree with their occurrences in ascending order.
+func (t *Tree[T]) groups() []group[T] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.appendGroups(make([]group[T], 0, t.root.getSize()))
+}
+
+// derive creates a tree with the ordering and options of t holding groups.
+func (t *Tree[T]) derive(groups []group[T]) *Tree[T] {
+	result := &Tree[T]{}
+	result.derived(&t.core, result)
+	result.load(groups)
+
+	return result
+}
+
+// merge walks two sorted group lists simultaneously and collects count(a, b) occurrences
+// of every distinct element, where a and b are its occurrences in either list.
+// Asymptotic: O(n + m)
+func merge[T any](compare func(a, b T) int, as, bs []group[T], count func(a, b uint) uint) []group[T] {
+	result := make([]group[T], 0, max(len(as), len(bs)))
+
+	collect := func(val T, a, b uint) {
+		if c := count(a, b); c > 0 {
+			result = append(result, group[T]{val: val, count: c})
+		}
+	}
+
+	for len(as) > 0 && len(bs) > 0 {
+		switch c := compare(as[0].val, bs[0].val); {
+		case c < 0:
+			collect(as[0].val, as[0].count, 0)
+			as = as[1:]
+		case c > 0:
+			collect(bs[0].val, 0, bs[0].count)
+			bs = bs[1:]
+		default:
+			collect(as[0].val, as[0].count, bs[0].count)
+			as, bs = as[1:], bs[1:]
+		}
+	}
+
+	for _, g := range as {
+		collect(g.val, g.count, 0)
+	}
+
+	for _, g := range bs {
+		collect(g.val, 0, g.count)
+	}
+
+	return result
+}
+
+// union keeps max occurrences of an element, so it is present in either operand.
+func union(a, b uint) uint {
+	return max(a, b)
+}
+
+// intersection keeps min occurrences of an element, so it is present in both operands.
+func intersection(a, b uint) uint {
+	return min(a, b)
+}
+
+// difference subtracts occurrences of an element in the second operand from the first one.
+func difference(a, b uint) uint {
+	if a > b {
+		return a - b
+	}
+
+	return 0
+}
+
+// exceeding marks an element occurring in the first operand more times than in the second one.
+func exceeding(a, b uint) uint {
+	if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+// unequal marks an element occurring in the operands different number of times.
+func unequal(a, b uint) uint {
+	if a != b {
+		return 1
+	}
+
+	return 0
+}
+
+// Union returns a new tree of elements present in either t or other.
+// For multisets every element occurs max of its occurrences in t and other.
+// The result has ordering and options of t.
+// Asymptotic: O(n + m)
+func (t *Tree[T]) Union(other *Tree[T]) *Tree[T] {
+	return t.derive(merge(t.compare, t.groups(), other.groups(), union))
+}
+
+// Intersection returns a new tree of elements present in both t and other.
+// For multisets every element occurs min of its occurrences in t and other.
+// The result has ordering and options of t.
+// Asymptotic: O(n + m)
+func (t *Tree[T]) Intersection(other *Tree[T]) *Tree[T] {
+	return t.derive(merge(t.compare, t.groups(), other.groups(), intersection))
+}
+
+// Difference returns a new tree of elements of t absent in other.
+// For multisets occurrences in other are subtracted from occurrences in t.
+// The result has ordering and options of t.
+// Asymptotic: O(n + m)
+func (t *Tree[T]) Difference(other *Tree[T]) *Tree[T] {
+	return t.derive(merge(t.compare, t.groups(), other.groups(), difference))
+}
+
+// IsSubset reports whether every element of t is present in other
+// (at least as many times, for multisets).
+// Asymptotic: O(n + m)
+func (t *Tree[T]) IsSubset(other *Tree[T]) bool {
+	return len(merge(t.compare, t.groups(), other.groups(), exceeding)) == 0
+}
+
+// Equal reports whether t and other hold the same elements
+// (the same number of times, for multisets), regardless of their shape.
+// Asymptotic: O(n + m)
+func (t *Tree[T]) Equal(other *Tree[T]) bool {
+	return t.Size() == other.Size() && len(merge(t.compare, t.groups(), other.groups(), unequal)) == 0
+}
+
+// BuildArenaFromSorted is BuildFromSorted for an arena-backed tree,
+// which takes a single allocation of slots.
+func BuildArenaFromSorted[T cmp.Ordered](sorted []T, opts ...Option) *ArenaTree[T] {
+	return BuildArenaFromSortedFunc(sorted, cmp.Compare[T], opts...)
+}
+
+// BuildArenaFromSortedFunc is BuildArenaFromSorted for elements ordered by compare.
+func BuildArenaFromSortedFunc[T any](sorted []T, compare func(a, b T) int, opts ...Option) *ArenaTree[T] {
+	t := NewArenaFunc(compare, opts...)
+	t.load(groupElems(sorted, compare))
+
+	return t
+}
+
+// groups returns distinct elements of the tree with their occurrences in ascending order.
+func (t *ArenaTree[T]) groups() []group[T] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.appendGroups(make([]group[T], 0, t.total))
+}
+
+// derive creates a tree with the ordering and options of t holding groups.
+func (t *ArenaTree[T]) derive(groups []group[T]) *ArenaTree[T] {
+	result := &ArenaTree[T]{slots: make([]slot[T], 1, 1+len(groups))}
+	result.derived(&t.core, result)
+	result.load(groups)
+
+	return result
+}
+
+// Union returns a new tree of elements present in either t or other, see Tree.Union.
+// Asymptotic: O(n + m)
+func (t *ArenaTree[T]) Union(other *ArenaTree[T]) *ArenaTree[T] {
+	return t.derive(merge(t.compare, t.groups(), other.groups(), union))
+}
+
+// Intersection returns a new tree of elements present in both t and other, see Tree.Intersection.
+// Asymptotic: O(n + m)
+func (t *ArenaTree[T]) Intersection(other *ArenaTree[T]) *ArenaTree[T] {
+	return t.derive(merge(t.compare, t.groups(), other.groups(), intersection))
+}
+
+// Difference returns a new tree of elements of t absent in other, see Tree.Difference.
+// Asymptotic: O(n + m)
+func (t *ArenaTree[T]) Difference(other *ArenaTree[T]) *ArenaTree[T] {
+	return t.derive(merge(t.compare, t.groups(), other.groups(), difference))
+}
+
+// IsSubset reports whether every element of t is present in other, see Tree.IsSubset.
+// Asymptotic: O(n + m)
+func (t *ArenaTree[T]) IsSubset(other *ArenaTree[T]) bool {
+	return len(merge(t.compare, t.groups(), other.groups(), exceeding)) == 0
+}
+
+// Equal reports whether t and other hold the same elements, see Tree.Equal.
+// Asymptotic: O(n + m)
+func (t *ArenaTree[T]) Equal(other *ArenaTree[T]) bool {
+	return t.Size() == other.Size() && len(merge(t.compare, t.groups(), other.groups(), unequal)) == 0
+}
@@ -0,0 +1,216 @@
+package tree_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeSetAlgebra(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		opts         []tree.Option
+		a, b         []int
+		union        []int
+		intersection []int
+		difference   []int
+		subset       bool
+		equal        bool
+	}{
+		{
+			name:         "overlapping sets",
+			a:            []int{1, 3, 5, 7},
+			b:            []int{3, 4, 5, 6},
+			union:        []int{1, 3, 4, 5, 6, 7},
+			intersection: []int{3, 5},
+			difference:   []int{1, 7},
+		},
+		{
+			name:         "subset",
+			a:            []int{2, 4},
+			b:            []int{1, 2, 3, 4},
+			union:        []int{1, 2, 3, 4},
+			intersection: []int{2, 4},
+			difference:   nil,
+			subset:       true,
+		},
+		{
+			name:         "equal sets",
+			a:            []int{1, 2, 3},
+			b:            []int{3, 2, 1},
+			union:        []int{1, 2, 3},
+			intersection: []int{1, 2, 3},
+			difference:   nil,
+			subset:       true,
+			equal:        true,
+		},
+		{
+			name:         "disjoint sets",
+			a:            []int{1, 2},
+			b:            []int{3, 4},
+			union:        []int{1, 2, 3, 4},
+			intersection: nil,
+			difference:   []int{1, 2},
+		},
+		{
+			name:         "empty set",
+			a:            nil,
+			b:            []int{1},
+			union:        []int{1},
+			intersection: nil,
+			difference:   nil,
+			subset:       true,
+		},
+		{
+			name:         "multisets",
+			opts:         []tree.Option{tree.Multiset()},
+			a:            []int{1, 1, 1, 2, 3, 3},
+			b:            []int{1, 2, 2, 3, 3, 4},
+			union:        []int{1, 1, 1, 2, 2, 3, 3, 4},
+			intersection: []int{1, 2, 3, 3},
+			difference:   []int{1, 1},
+		},
+		{
+			name:         "multiset subset needs enough occurrences",
+			opts:         []tree.Option{tree.Multiset()},
+			a:            []int{1, 1},
+			b:            []int{1, 2},
+			union:        []int{1, 1, 2},
+			intersection: []int{1},
+			difference:   []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := tree.New[int](tt.opts...), tree.New[int](tt.opts...)
+			for _, v := range tt.a {
+				a.Add(v)
+			}
+
+			for _, v := range tt.b {
+				b.Add(v)
+			}
+
+			results := []struct {
+				op       string
+				got      *tree.Tree[int]
+				expected []int
+			}{
+				{"union", a.Union(b), tt.union},
+				{"intersection", a.Intersection(b), tt.intersection},
+				{"difference", a.Difference(b), tt.difference},
+			}
+
+			for _, r := range results {
+				if err := r.got.CheckInvariants(); err != nil {
+					t.Fatalf("%s: %v", r.op, err)
+				}
+
+				if got := r.got.SortedAsc(); !reflect.DeepEqual(got, r.expected) {
+					t.Errorf("%s: expected %v, got %v", r.op, r.expected, got)
+				}
+			}
+
+			if got := a.IsSubset(b); got != tt.subset {
+				t.Errorf("subset: expected %v, got %v", tt.subset, got)
+			}
+
+			if got := a.Equal(b); got != tt.equal {
+				t.Errorf("equal: expected %v, got %v", tt.equal, got)
+			}
+
+			if !a.Equal(a) || !a.IsSubset(a) {
+				t.Error("expected tree to be equal to and subset of itself")
+			}
+		})
+	}
+}
+
+func TestBuildFromSorted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []tree.Option
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "sorted input",
+			input:    []string{"a", "b", "c", "d", "e"},
+			expected: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:     "unsorted input is sorted",
+			input:    []string{"c", "a", "b"},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "duplicates are dropped",
+			input:    []string{"a", "a", "b"},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "duplicates are kept by multiset",
+			opts:     []tree.Option{tree.Multiset()},
+			input:    []string{"a", "a", "b"},
+			expected: []string{"a", "a", "b"},
+		},
+		{
+			name:     "empty input",
+			input:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := append([]string(nil), tt.input...)
+			sut := tree.BuildFromSorted(input, tt.opts...)
+
+			if err := sut.CheckInvariants(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := sut.SortedAsc(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+
+			if !reflect.DeepEqual(input, tt.input) {
+				t.Errorf("expected input to stay %v, got %v", tt.input, input)
+			}
+		})
+	}
+}
+
+func BenchmarkTree_Union(b *testing.B) {
+	x, y := tree.New[int](), tree.New[int]()
+	for i := 0; i < 100_000; i++ {
+		x.Add(2 * i)
+		y.Add(3 * i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = x.Union(y)
+	}
+}
+
+func BenchmarkBuildFromSorted(b *testing.B) {
+	sorted := between(0, 100_000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = tree.BuildFromSorted(sorted)
+	}
+}
@@ -0,0 +1,225 @@
+package tree
+
+import (
+	"iter"
+)
+
+// Snapshot is an immutable point-in-time view of a Tree or an ArenaTree.
+// It is safe for concurrent use and never takes a lock: a Tree copies
+// shared nodes on write instead of modifying them, an ArenaTree copies
+// its slots into the snapshot.
+type Snapshot[T any] struct {
+	compare func(a, b T) int
+	root    *node[T]
+	size    uint
+	version uint64
+}
+
+// commit finishes a modification made under the write lock.
+func (t *Tree[T]) commit() {
+	t.version++
+
+	if t.opts.persistent {
+		t.publish()
+	}
+}
+
+// publish freezes the current nodes of the tree and makes them the latest snapshot.
+func (t *Tree[T]) publish() *Snapshot[T] {
+	s := &Snapshot[T]{compare: t.compare, root: t.root, size: t.nodesCol, version: t.version}
+	t.published.Store(s)
+	// Nodes reachable from the snapshot now belong to an older generation,
+	// so the next modification copies them instead of writing in place.
+	t.gen++
+
+	return s
+}
+
+// Snapshot returns an immutable view of the current tree content.
+// The tree keeps being modifiable: nodes shared with the snapshot are copied
+// on the first write after it, so repeated snapshots between writes are free.
+// For a Persistent tree it is a single atomic load that never blocks,
+// otherwise it briefly takes the write lock.
+// Asymptotic: O(1)
+func (t *Tree[T]) Snapshot() *Snapshot[T] {
+	if t.opts.persistent {
+		return t.published.Load()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if s := t.published.Load(); s != nil && s.version == t.version {
+		return s
+	}
+
+	return t.publish()
+}
+
+// Snapshot returns an immutable view of the current tree content, see Tree.Snapshot.
+// Slots are modified in place, so the snapshot copies them into nodes allocated at once.
+// Repeated snapshots between writes share the copy.
+// Asymptotic: O(n), O(1) when the tree is unchanged since the previous snapshot.
+func (t *ArenaTree[T]) Snapshot() *Snapshot[T] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if s := t.published.Load(); s != nil && s.version == t.version {
+		return s
+	}
+
+	// Concurrent readers may copy the same version, either copy is as good as the other.
+	nodes := make([]node[T], 0, len(t.slots)-1)
+	s := &Snapshot[T]{compare: t.compare, root: t.freeze(t.root, &nodes), size: t.total, version: t.version}
+	t.published.Store(s)
+
+	return s
+}
+
+// freeze copies the subtree of slot i into nodes, which have room for all of them.
+func (t *ArenaTree[T]) freeze(i int32, nodes *[]node[T]) *node[T] {
+	if i == null {
+		return nil
+	}
+
+	s := &t.slots[i]
+	*nodes = append(*nodes, node[T]{
+		val:     s.val,
+		height:  int(s.height),
+		size:    s.size,
+		count:   uint(s.count),
+		expires: s.expires,
+	})
+	n := &(*nodes)[len(*nodes)-1]
+	n.left = t.freeze(s.left, nodes)
+	n.right = t.freeze(s.right, nodes)
+
+	return n
+}
+
+// Size returns count of elements in the snapshot.
+// Asymptotic: O(1)
+func (s *Snapshot[T]) Size() uint {
+	return s.size
+}
+
+// Contains reports whether elem is present in the snapshot.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Contains(elem T) bool {
+	return s.root.find(s.compare, elem) != nil
+}
+
+// Count returns count of occurrences of elem in the snapshot.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Count(elem T) uint {
+	if n := s.root.find(s.compare, elem); n != nil {
+		return n.count
+	}
+
+	return 0
+}
+
+// Min returns the least element of the snapshot if presented.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Min() (T, bool) {
+	return s.root.min().value()
+}
+
+// Max returns the greatest element of the snapshot if presented.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Max() (T, bool) {
+	return s.root.max().value()
+}
+
+// Floor returns the greatest element less than or equal to elem.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Floor(elem T) (T, bool) {
+	return s.root.floor(s.compare, elem, true).value()
+}
+
+// Ceiling returns the least element greater than or equal to elem.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Ceiling(elem T) (T, bool) {
+	return s.root.ceiling(s.compare, elem, true).value()
+}
+
+// Select returns k-th (0-based) smallest element of the snapshot.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Select(k uint) (T, bool) {
+	return s.root.selectAt(k).value()
+}
+
+// Rank returns count of elements strictly less than elem.
+// Asymptotic: O(log n)
+func (s *Snapshot[T]) Rank(elem T) uint {
+	return s.root.rank(s.compare, elem)
+}
+
+// SortedAsc returns all elements of the snapshot in ascending order.
+// Asymptotic: O(n)
+func (s *Snapshot[T]) SortedAsc() []T {
+	if s.root == nil {
+		return nil
+	}
+
+	return s.root.appendSorted(make([]T, 0, s.size), false)
+}
+
+// SortedDesc returns all elements of the snapshot in descending order.
+// Asymptotic: O(n)
+func (s *Snapshot[T]) SortedDesc() []T {
+	if s.root == nil {
+		return nil
+	}
+
+	return s.root.appendSorted(make([]T, 0, s.size), true)
+}
+
+// walk returns a lock-free iterator over the snapshot, see Tree.walk.
+func (s *Snapshot[T]) walk(desc bool, from *T, within func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		w := walker[T]{compare: s.compare, stack: make([]*node[T], 0, s.root.getHeight()), desc: desc}
+		if from == nil {
+			w.pushFrom(s.root)
+		} else {
+			w.seek(s.root, *from, true)
+		}
+
+		for n := w.pop(); n != nil; n = w.pop() {
+			if within != nil && !within(n.val) {
+				return
+			}
+
+			for range n.count {
+				if !yield(n.val) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All returns an iterator over all elements in ascending order.
+func (s *Snapshot[T]) All() iter.Seq[T] {
+	return s.walk(false, nil, nil)
+}
+
+// Backward returns an iterator over all elements in descending order.
+func (s *Snapshot[T]) Backward() iter.Seq[T] {
+	return s.walk(true, nil, nil)
+}
+
+// Ascend returns an iterator over elements greater than or equal to from in ascending order.
+func (s *Snapshot[T]) Ascend(from T) iter.Seq[T] {
+	return s.walk(false, &from, nil)
+}
+
+// Descend returns an iterator over elements less than or equal to from in descending order.
+func (s *Snapshot[T]) Descend(from T) iter.Seq[T] {
+	return s.walk(true, &from, nil)
+}
+
+// Range returns an iterator over elements of the half-open interval [lo, hi) in ascending order.
+func (s *Snapshot[T]) Range(lo, hi T) iter.Seq[T] {
+	return s.walk(false, &lo, func(val T) bool { return s.compare(val, hi) < 0 })
+}
@@ -0,0 +1,302 @@
+package tree_test
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []tree.Option
+	}{
+		{
+			name: "copy on write after snapshot",
+		},
+		{
+			name: "persistent tree",
+			opts: []tree.Option{tree.Persistent()},
+		},
+		{
+			name: "persistent multiset",
+			opts: []tree.Option{tree.Persistent(), tree.Multiset()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			type frozen struct {
+				snapshot *tree.Snapshot[int]
+				expected []int
+			}
+
+			rnd := rand.New(rand.NewSource(5))
+			sut := tree.New[int](tt.opts...)
+
+			var snapshots []frozen
+
+			for i := 0; i < 2000; i++ {
+				if v := rnd.Intn(200); rnd.Intn(3) == 0 {
+					sut.Delete(v)
+				} else {
+					sut.Add(v)
+				}
+
+				if err := sut.CheckInvariants(); err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+
+				if i%50 == 0 {
+					snapshots = append(snapshots, frozen{sut.Snapshot(), sut.SortedAsc()})
+				}
+			}
+
+			for i, f := range snapshots {
+				if got := slices.Collect(f.snapshot.All()); !reflect.DeepEqual(got, f.expected) {
+					t.Fatalf("snapshot %d: expected %v, got %v", i, f.expected, got)
+				}
+
+				if f.snapshot.Size() != uint(len(f.expected)) {
+					t.Fatalf("snapshot %d: expected size %d, got %d", i, len(f.expected), f.snapshot.Size())
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotQueries(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+	for _, v := range []int{44, 18, 1, 2, 10, 8} {
+		sut.Add(v)
+	}
+
+	snapshot := sut.Snapshot()
+
+	if again := sut.Snapshot(); again != snapshot {
+		t.Error("expected the same snapshot while the tree is unchanged")
+	}
+
+	sut.Delete(10)
+	sut.Add(11)
+
+	tests := []struct {
+		name     string
+		query    func() any
+		expected any
+	}{
+		{
+			name:     "contains deleted element",
+			query:    func() any { return snapshot.Contains(10) },
+			expected: true,
+		},
+		{
+			name:     "does not contain added element",
+			query:    func() any { return snapshot.Contains(11) },
+			expected: false,
+		},
+		{
+			name:     "min",
+			query:    func() any { v, _ := snapshot.Min(); return v },
+			expected: 1,
+		},
+		{
+			name:     "max",
+			query:    func() any { v, _ := snapshot.Max(); return v },
+			expected: 44,
+		},
+		{
+			name:     "floor",
+			query:    func() any { v, _ := snapshot.Floor(11); return v },
+			expected: 10,
+		},
+		{
+			name:     "ceiling",
+			query:    func() any { v, _ := snapshot.Ceiling(11); return v },
+			expected: 18,
+		},
+		{
+			name:     "select",
+			query:    func() any { v, _ := snapshot.Select(3); return v },
+			expected: 10,
+		},
+		{
+			name:     "rank",
+			query:    func() any { return snapshot.Rank(18) },
+			expected: uint(4),
+		},
+		{
+			name:     "sorted desc",
+			query:    func() any { return snapshot.SortedDesc() },
+			expected: []int{44, 18, 10, 8, 2, 1},
+		},
+		{
+			name:     "range",
+			query:    func() any { return slices.Collect(snapshot.Range(2, 18)) },
+			expected: []int{2, 8, 10},
+		},
+		{
+			name:     "descend",
+			query:    func() any { return slices.Collect(snapshot.Descend(9)) },
+			expected: []int{8, 2, 1},
+		},
+		{
+			name:     "tree has moved on",
+			query:    func() any { return sut.SortedAsc() },
+			expected: []int{1, 2, 8, 11, 18, 44},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.query(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSnapshotConcurrentReaders(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int](tree.Persistent())
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := range 5000 {
+			sut.Add(i)
+			if i%3 == 0 {
+				sut.Delete(i / 2)
+			}
+		}
+	}()
+
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 200 {
+				snapshot := sut.Snapshot()
+
+				var count uint
+
+				prev := -1
+				for v := range snapshot.All() {
+					if v <= prev {
+						t.Errorf("snapshot is out of order: %d after %d", v, prev)
+						return
+					}
+
+					prev = v
+					count++
+				}
+
+				if count != snapshot.Size() {
+					t.Errorf("expected %d elements in snapshot, got %d", snapshot.Size(), count)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// benchmarkReadsUnderWrites measures parallel read throughput
+// while a background goroutine keeps modifying the tree.
+func benchmarkReadsUnderWrites(b *testing.B, sut *tree.Tree[int], read func(int)) {
+	const size = 100_000
+
+	for i := 0; i < size; i++ {
+		sut.Add(i)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			sut.Delete(i % size)
+			sut.Add(i % size)
+		}
+	}()
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			read(i % size)
+		}
+	})
+
+	b.StopTimer()
+	close(done)
+	<-stopped
+}
+
+func BenchmarkReadsUnderWrites_TreeContains(b *testing.B) {
+	sut := tree.New[int]()
+	benchmarkReadsUnderWrites(b, sut, func(v int) {
+		sut.Contains(v)
+	})
+}
+
+func BenchmarkReadsUnderWrites_SnapshotContains(b *testing.B) {
+	sut := tree.New[int](tree.Persistent())
+	benchmarkReadsUnderWrites(b, sut, func(v int) {
+		sut.Snapshot().Contains(v)
+	})
+}
+
+func BenchmarkReadsUnderWrites_TreeScan(b *testing.B) {
+	sut := tree.New[int]()
+	benchmarkReadsUnderWrites(b, sut, func(v int) {
+		for range sut.Range(v, v+1000) {
+		}
+	})
+}
+
+func BenchmarkReadsUnderWrites_SnapshotScan(b *testing.B) {
+	sut := tree.New[int](tree.Persistent())
+	benchmarkReadsUnderWrites(b, sut, func(v int) {
+		for range sut.Snapshot().Range(v, v+1000) {
+		}
+	})
+}
+
+func BenchmarkPersistent_Add(b *testing.B) {
+	sut := tree.New[int](tree.Persistent())
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Add(i)
+	}
+}
@@ -0,0 +1,392 @@
+package tree
+
+import (
+	"iter"
+
+	"github.com/dzianismaroz/marathon/queue/queue"
+)
+
+// preOrder yields values of the subtree nodes in pre-order until yield returns false.
+func preOrder[T any](root *node[T], yield func(T) bool) {
+	if root == nil {
+		return
+	}
+
+	stack := make([]*node[T], 0, root.getHeight())
+	stack = append(stack, root)
+
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !yield(n.val) {
+			return
+		}
+
+		if n.right != nil {
+			stack = append(stack, n.right)
+		}
+
+		if n.left != nil {
+			stack = append(stack, n.left)
+		}
+	}
+}
+
+// postOrder yields values of the subtree nodes in post-order until yield returns false.
+func postOrder[T any](root *node[T], yield func(T) bool) {
+	var (
+		stack = make([]*node[T], 0, root.getHeight())
+		last  *node[T] // the most recently yielded node.
+	)
+
+	for n := root; n != nil || len(stack) > 0; {
+		if n != nil {
+			stack = append(stack, n)
+			n = n.left
+
+			continue
+		}
+
+		top := stack[len(stack)-1]
+		if top.right != nil && top.right != last {
+			n = top.right
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+		last = top
+
+		if !yield(top.val) {
+			return
+		}
+	}
+}
+
+// levelOrder yields values of the subtree nodes breadth-first with their levels until yield returns false.
+func levelOrder[T any](root *node[T], yield func(int, T) bool) {
+	type leveled struct {
+		node  *node[T]
+		level int
+	}
+
+	if root == nil {
+		return
+	}
+
+	q := queue.New[leveled]()
+	q.Push(leveled{root, 0})
+
+	for item, ok := q.Pop(); ok; item, ok = q.Pop() {
+		if !yield(item.level, item.node.val) {
+			return
+		}
+
+		for _, child := range []*node[T]{item.node.left, item.node.right} {
+			if child != nil {
+				q.Push(leveled{child, item.level + 1})
+			}
+		}
+	}
+}
+
+// PreOrder returns an iterator over values of the snapshot nodes in pre-order:
+// node first, then its left and right subtrees. Every node is yielded once
+// regardless of its occurrences.
+// Asymptotic: O(n) for the full iteration, O(log n) memory.
+func (s *Snapshot[T]) PreOrder() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		preOrder(s.root, yield)
+	}
+}
+
+// PostOrder returns an iterator over values of the snapshot nodes in post-order:
+// left and right subtrees first, then the node itself. Every node is yielded once
+// regardless of its occurrences.
+// Asymptotic: O(n) for the full iteration, O(log n) memory.
+func (s *Snapshot[T]) PostOrder() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		postOrder(s.root, yield)
+	}
+}
+
+// LevelOrder returns an iterator over values of the snapshot nodes breadth-first,
+// level by level from the root, paired with the level (0 for the root).
+// Every node is yielded once regardless of its occurrences.
+// Asymptotic: O(n) for the full iteration, O(n) memory.
+func (s *Snapshot[T]) LevelOrder() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		levelOrder(s.root, yield)
+	}
+}
+
+// PreOrder returns an iterator over node values in pre-order, see Snapshot.PreOrder.
+// Structural traversals walk a snapshot taken when the iteration starts,
+// so the tree may be modified meanwhile without affecting it: the whole shape
+// of the tree at that moment is yielded, never a part of it.
+func (t *Tree[T]) PreOrder() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.Snapshot().PreOrder()(yield)
+	}
+}
+
+// PostOrder returns an iterator over node values in post-order, see Snapshot.PostOrder.
+// It walks a snapshot taken when the iteration starts.
+func (t *Tree[T]) PostOrder() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.Snapshot().PostOrder()(yield)
+	}
+}
+
+// LevelOrder returns an iterator over node values breadth-first with their levels,
+// see Snapshot.LevelOrder. It walks a snapshot taken when the iteration starts.
+func (t *Tree[T]) LevelOrder() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		t.Snapshot().LevelOrder()(yield)
+	}
+}
+
+// Height returns count of nodes on the longest path from the root to a leaf,
+// 0 for an empty tree.
+// Asymptotic: O(1)
+func (t *Tree[T]) Height() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.getHeight()
+}
+
+// balancedHeight measures actual height of the subtree, ignoring the cached one.
+// The flag reports whether heights of children differ by at most one for every node.
+func (n *node[T]) balancedHeight() (int, bool) {
+	if n == nil {
+		return 0, true
+	}
+
+	left, ok := n.left.balancedHeight()
+	if !ok {
+		return 0, false
+	}
+
+	right, ok := n.right.balancedHeight()
+	if !ok {
+		return 0, false
+	}
+
+	return 1 + max(left, right), left-right <= 1 && right-left <= 1
+}
+
+// IsBalanced reports whether heights of the two subtrees of every node differ by at most one.
+// Unlike CheckInvariants it measures heights instead of relying on the cached ones.
+// Asymptotic: O(n)
+func (t *Tree[T]) IsBalanced() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	_, ok := t.root.balancedHeight()
+
+	return ok
+}
+
+// diameter returns count of edges on the longest path between two nodes of the subtree.
+func (n *node[T]) diameter() int {
+	if n == nil {
+		return 0
+	}
+
+	return max(n.left.getHeight()+n.right.getHeight(), n.left.diameter(), n.right.diameter())
+}
+
+// Diameter returns count of edges on the longest path between any two nodes,
+// 0 for a tree of at most one node.
+// Asymptotic: O(n)
+func (t *Tree[T]) Diameter() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.root.diameter()
+}
+
+// LowestCommonAncestor returns the deepest element that has both a and b in its subtree
+// (a node is a descendant of itself). It reports false unless both a and b are present.
+// Asymptotic: O(log n)
+func (t *Tree[T]) LowestCommonAncestor(a, b T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root.find(t.compare, a) == nil || t.root.find(t.compare, b) == nil {
+		var zero T
+
+		return zero, false
+	}
+
+	n := t.root
+
+	for {
+		ca, cb := t.compare(a, n.val), t.compare(b, n.val)
+
+		switch {
+		case ca < 0 && cb < 0:
+			n = n.left
+		case ca > 0 && cb > 0:
+			n = n.right
+		default:
+			return n.val, true
+		}
+	}
+}
+
+// PathTo returns elements on the path from the root down to elem inclusive.
+// It reports false with a nil path when elem is absent.
+// Asymptotic: O(log n)
+func (t *Tree[T]) PathTo(elem T) ([]T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	path := make([]T, 0, t.root.getHeight())
+
+	for n := t.root; n != nil; {
+		path = append(path, n.val)
+
+		switch c := t.compare(elem, n.val); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return path, true
+		}
+	}
+
+	return nil, false
+}
+
+// PreOrder returns an iterator over slot values in pre-order, see Snapshot.PreOrder.
+// As Tree.PreOrder does, it walks a snapshot taken when the iteration starts,
+// which copies the slots, so the tree may be modified meanwhile without affecting it.
+// Asymptotic: O(n) for the full iteration, O(n) memory.
+func (t *ArenaTree[T]) PreOrder() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.Snapshot().PreOrder()(yield)
+	}
+}
+
+// PostOrder returns an iterator over slot values in post-order, see Snapshot.PostOrder.
+// It walks a snapshot taken when the iteration starts, as PreOrder does.
+func (t *ArenaTree[T]) PostOrder() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.Snapshot().PostOrder()(yield)
+	}
+}
+
+// LevelOrder returns an iterator over slot values breadth-first with their levels,
+// see Snapshot.LevelOrder. It walks a snapshot taken when the iteration starts, as PreOrder does.
+func (t *ArenaTree[T]) LevelOrder() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		t.Snapshot().LevelOrder()(yield)
+	}
+}
+
+// balancedHeight measures actual height of the subtree of slot i, see node.balancedHeight.
+func (t *ArenaTree[T]) balancedHeight(i int32) (int32, bool) {
+	if i == null {
+		return 0, true
+	}
+
+	left, ok := t.balancedHeight(t.slots[i].left)
+	if !ok {
+		return 0, false
+	}
+
+	right, ok := t.balancedHeight(t.slots[i].right)
+	if !ok {
+		return 0, false
+	}
+
+	return 1 + max(left, right), left-right <= 1 && right-left <= 1
+}
+
+// IsBalanced reports whether heights of the two subtrees of every node differ by at most one,
+// see Tree.IsBalanced.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) IsBalanced() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	_, ok := t.balancedHeight(t.root)
+
+	return ok
+}
+
+// diameter returns count of edges on the longest path between two nodes of the subtree of slot i.
+func (t *ArenaTree[T]) diameter(i int32) int {
+	if i == null {
+		return 0
+	}
+
+	s := &t.slots[i]
+
+	return max(int(t.slots[s.left].height+t.slots[s.right].height), t.diameter(s.left), t.diameter(s.right))
+}
+
+// Diameter returns count of edges on the longest path between any two nodes,
+// 0 for a tree of at most one node.
+// Asymptotic: O(n)
+func (t *ArenaTree[T]) Diameter() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.diameter(t.root)
+}
+
+// LowestCommonAncestor returns the deepest element that has both a and b in its subtree,
+// see Tree.LowestCommonAncestor.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) LowestCommonAncestor(a, b T) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.find(a) == null || t.find(b) == null {
+		return t.value(null)
+	}
+
+	i := t.root
+
+	for {
+		ca, cb := t.compare(a, t.slots[i].val), t.compare(b, t.slots[i].val)
+
+		switch {
+		case ca < 0 && cb < 0:
+			i = t.slots[i].left
+		case ca > 0 && cb > 0:
+			i = t.slots[i].right
+		default:
+			return t.value(i)
+		}
+	}
+}
+
+// PathTo returns elements on the path from the root down to elem inclusive.
+// It reports false with a nil path when elem is absent.
+// Asymptotic: O(log n)
+func (t *ArenaTree[T]) PathTo(elem T) ([]T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	path := make([]T, 0, t.slots[t.root].height)
+
+	for i := t.root; i != null; {
+		path = append(path, t.slots[i].val)
+
+		switch c := t.compare(elem, t.slots[i].val); {
+		case c < 0:
+			i = t.slots[i].left
+		case c > 0:
+			i = t.slots[i].right
+		default:
+			return path, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,230 @@
+package tree_test
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeTraversals(t *testing.T) {
+	t.Parallel()
+
+	// 10
+	// ├── L: 2
+	// │   ├── L: 1
+	// │   └── R: 8
+	// └── R: 18
+	//     └── R: 44
+	sut := tree.New[int]()
+	for _, v := range []int{44, 18, 1, 2, 10, 8} {
+		sut.Add(v)
+	}
+
+	levelOrder := func(seq func(func(int, int) bool)) [][2]int {
+		var result [][2]int
+		for level, v := range seq {
+			result = append(result, [2]int{level, v})
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		query    func() any
+		expected any
+	}{
+		{
+			name:     "pre-order",
+			query:    func() any { return slices.Collect(sut.PreOrder()) },
+			expected: []int{10, 2, 1, 8, 18, 44},
+		},
+		{
+			name:     "post-order",
+			query:    func() any { return slices.Collect(sut.PostOrder()) },
+			expected: []int{1, 8, 2, 44, 18, 10},
+		},
+		{
+			name:     "level-order",
+			query:    func() any { return levelOrder(sut.LevelOrder()) },
+			expected: [][2]int{{0, 10}, {1, 2}, {1, 18}, {2, 1}, {2, 8}, {2, 44}},
+		},
+		{
+			name:     "pre-order of empty tree",
+			query:    func() any { return slices.Collect(tree.New[int]().PreOrder()) },
+			expected: []int(nil),
+		},
+		{
+			name:     "post-order of empty tree",
+			query:    func() any { return slices.Collect(tree.New[int]().PostOrder()) },
+			expected: []int(nil),
+		},
+		{
+			name:     "level-order of empty tree",
+			query:    func() any { return levelOrder(tree.New[int]().LevelOrder()) },
+			expected: [][2]int(nil),
+		},
+		{
+			name:     "height",
+			query:    func() any { return sut.Height() },
+			expected: 3,
+		},
+		{
+			name:     "balanced",
+			query:    func() any { return sut.IsBalanced() },
+			expected: true,
+		},
+		{
+			name:     "diameter",
+			query:    func() any { return sut.Diameter() },
+			expected: 4,
+		},
+		{
+			name:     "diameter of single node",
+			query:    func() any { return tree.BuildFromSorted([]int{1}).Diameter() },
+			expected: 0,
+		},
+		{
+			name:     "lowest common ancestor of siblings",
+			query:    func() any { v, _ := sut.LowestCommonAncestor(1, 8); return v },
+			expected: 2,
+		},
+		{
+			name:     "lowest common ancestor across root",
+			query:    func() any { v, _ := sut.LowestCommonAncestor(44, 1); return v },
+			expected: 10,
+		},
+		{
+			name:     "lowest common ancestor of ancestor and descendant",
+			query:    func() any { v, _ := sut.LowestCommonAncestor(18, 44); return v },
+			expected: 18,
+		},
+		{
+			name:     "lowest common ancestor with absent element",
+			query:    func() any { _, ok := sut.LowestCommonAncestor(1, 99); return ok },
+			expected: false,
+		},
+		{
+			name:     "path to leaf",
+			query:    func() any { path, _ := sut.PathTo(8); return path },
+			expected: []int{10, 2, 8},
+		},
+		{
+			name:     "path to root",
+			query:    func() any { path, _ := sut.PathTo(10); return path },
+			expected: []int{10},
+		},
+		{
+			name:     "path to absent element",
+			query:    func() any { path, ok := sut.PathTo(9); return []any{path, ok} },
+			expected: []any{[]int(nil), false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.query(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTreeTraversalsModification(t *testing.T) {
+	t.Parallel()
+
+	// traversed is the part of the API shared by Tree and ArenaTree used here.
+	type traversed interface {
+		Add(elem int)
+		Delete(elem int) bool
+		SortedAsc() []int
+		CheckInvariants() error
+		PreOrder() iter.Seq[int]
+		PostOrder() iter.Seq[int]
+		LevelOrder() iter.Seq2[int, int]
+	}
+
+	trees := []struct {
+		name string
+		new  func() traversed
+	}{
+		{name: "Tree", new: func() traversed { return tree.New[int]() }},
+		{name: "persistent Tree", new: func() traversed { return tree.New[int](tree.Persistent()) }},
+		{name: "ArenaTree", new: func() traversed { return tree.NewArena[int]() }},
+	}
+
+	// 3
+	// ├── L: 1
+	// │   ├── L: 0
+	// │   └── R: 2
+	// └── R: 5
+	//     ├── L: 4
+	//     └── R: 6
+	orders := []struct {
+		name     string
+		seq      func(sut traversed) iter.Seq[int]
+		expected []int
+	}{
+		{name: "pre-order", seq: traversed.PreOrder, expected: []int{3, 1, 0, 2, 5, 4, 6}},
+		{name: "post-order", seq: traversed.PostOrder, expected: []int{0, 2, 1, 4, 6, 5, 3}},
+		{
+			name: "level order",
+			seq: func(sut traversed) iter.Seq[int] {
+				return func(yield func(int) bool) {
+					for _, v := range sut.LevelOrder() {
+						if !yield(v) {
+							return
+						}
+					}
+				}
+			},
+			expected: []int{3, 1, 5, 0, 2, 4, 6},
+		},
+	}
+
+	for _, tr := range trees {
+		for _, order := range orders {
+			sut := tr.new()
+			for i := range 7 {
+				sut.Add(i)
+			}
+
+			// Every write reshapes the tree, yet the caller sees the whole initial shape.
+			var got []int
+
+			for v := range order.seq(sut) {
+				got = append(got, v)
+				sut.Delete(v)
+				sut.Add(v + 100)
+			}
+
+			if !reflect.DeepEqual(got, order.expected) {
+				t.Errorf("%s, %s: expected the initial tree %v, got %v", tr.name, order.name, order.expected, got)
+			}
+
+			if got, expected := sut.SortedAsc(), between(100, 107); !reflect.DeepEqual(got, expected) {
+				t.Errorf("%s, %s: expected the tree to hold %v after writes, got %v", tr.name, order.name, expected, got)
+			}
+
+			if err := sut.CheckInvariants(); err != nil {
+				t.Errorf("%s, %s: %v", tr.name, order.name, err)
+			}
+		}
+	}
+}
+
+func BenchmarkTree_LevelOrder(b *testing.B) {
+	sut := tree.BuildFromSorted(between(0, 100_000))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for range sut.LevelOrder() {
+		}
+	}
+}
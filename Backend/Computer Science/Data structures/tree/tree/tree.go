@@ -1,67 +1,285 @@
 package tree
 
 import (
-	"sync"
+	"cmp"
+	"sync/atomic"
 )
 
 type (
-	ordered interface {
-		~int | ~float32 | ~float64 | ~uint | ~string
-	}
-	node[T ordered] struct {
-		val   T
-		left  *node[T] // less than val
-		right *node[T] // greater than val.
+	node[T any] struct {
+		val    T
+		left   *node[T] // less than val.
+		right  *node[T] // greater than val.
+		height int      // height of the subtree rooted at this node.
+		size   uint     // count of elements in the subtree rooted at this node.
+		count  uint     // occurrences of val, always 1 unless the tree is a multiset.
+		gen    uint64   // generation of the tree that has created the node.
+		// expires is the deadline of val in Unix nanoseconds, 0 when it does not expire.
+		expires int64
 	}
 
-	Tree[T ordered] struct {
-		mu       sync.RWMutex
+	// Tree is a self-balancing (AVL) binary search tree.
+	// Heights of the two child subtrees of any node differ by at most one,
+	// so Add is O(log n) regardless of the insertion order.
+	// Use New or NewFunc to create a tree.
+	Tree[T any] struct {
+		core[T]
 		root     *node[T] // root tree node.
-		nodesCol uint     // total count of nodes.
+		nodesCol uint     // total count of elements.
+
+		// gen is the generation of nodes owned by the tree: nodes of older
+		// generations are reachable from snapshots and copied on write.
+		gen       uint64
+		published atomic.Pointer[Snapshot[T]] // the latest snapshot taken.
 	}
 )
 
-func newNode[T ordered](elem T) *node[T] {
-	return &node[T]{val: elem}
+func newNode[T any](elem T, gen uint64) *node[T] {
+	return &node[T]{val: elem, height: 1, size: 1, count: 1, gen: gen}
+}
+
+// New creates an empty tree of naturally ordered elements.
+func New[T cmp.Ordered](opts ...Option) *Tree[T] {
+	return NewFunc(cmp.Compare[T], opts...)
+}
+
+// NewFunc creates an empty tree ordered by compare, which must return
+// a negative number when a < b, a positive number when a > b and zero otherwise.
+func NewFunc[T any](compare func(a, b T) int, opts ...Option) *Tree[T] {
+	t := &Tree[T]{}
+	t.setup(compare, t, opts)
+
+	if t.opts.persistent {
+		t.publish()
+	}
+
+	return t
+}
+
+// add inserts elem into the subtree and returns its new (rebalanced) root.
+// The flag reports whether a new occurrence of elem has been inserted.
+// Nodes of generations other than gen are copied rather than modified.
+func (n *node[T]) add(compare func(a, b T) int, gen uint64, augment Augment[T], elem T, dup duplicates) (*node[T], bool) {
+	if n == nil {
+		n = newNode(elem, gen)
+		n.fix(augment)
+
+		return n, true
+	}
+
+	switch c := compare(elem, n.val); {
+	case c < 0:
+		left, added := n.left.add(compare, gen, augment, elem, dup)
+		if left == n.left && !added && augment == nil {
+			return n, false
+		}
+
+		n = n.own(gen)
+		n.left = left
+
+		if !added {
+			// The shape is the same, but a replaced element may change summaries.
+			n.fix(augment)
+
+			return n, false
+		}
+	case c > 0:
+		right, added := n.right.add(compare, gen, augment, elem, dup)
+		if right == n.right && !added && augment == nil {
+			return n, false
+		}
+
+		n = n.own(gen)
+		n.right = right
+
+		if !added {
+			n.fix(augment)
+
+			return n, false
+		}
+	case dup == replaceDuplicates:
+		n = n.own(gen)
+		n.val = elem
+		n.fix(augment)
+
+		return n, false
+	case dup == countDuplicates:
+		n = n.own(gen)
+		n.count++
+	default:
+		return n, false
+	}
+
+	return n.rebalance(gen, augment), true
+}
+
+// Add inserts elem into the tree. Duplicates are ignored unless
+// the tree is a multiset, which counts every occurrence.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Add(elem T) {
+	t.lock()
+	defer t.unlock()
+
+	t.add(elem, t.duplicates())
 }
 
-func New[T ordered]() *Tree[T] {
-	return &Tree[T]{}
+// Put inserts elem into the tree, replacing an equal element if any,
+// which keeps its occurrences and deadline. Unlike Add it updates parts of elements
+// not affecting their order, e.g. values kept along with keys.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Put(elem T) {
+	t.lock()
+	defer t.unlock()
+
+	t.add(elem, replaceDuplicates)
 }
 
-func (n *node[T]) add(elem T) bool {
-	switch {
-	case n.val < elem:
+// add inserts elem under the write lock held by the caller.
+func (t *Tree[T]) add(elem T, dup duplicates) bool {
+	root, added := t.root.add(t.compare, t.gen, t.augment, elem, dup)
+	if root == t.root && !added {
+		return false
+	}
+
+	t.root = root
+	if added {
+		t.nodesCol++
+	}
+
+	t.commit()
+
+	if added {
+		t.changed(EventAdd, elem)
+	}
+
+	return added
+}
+
+// delete removes elem from the subtree and returns its new (rebalanced) root
+// with count of removed occurrences. Only one occurrence is removed unless all is set.
+// Nodes of generations other than gen are copied rather than modified.
+func (n *node[T]) delete(compare func(a, b T) int, gen uint64, augment Augment[T], elem T, all bool) (*node[T], uint) {
+	if n == nil {
+		return nil, 0
+	}
+
+	var deleted uint
+
+	switch c := compare(elem, n.val); {
+	case c < 0:
+		var left *node[T]
+		if left, deleted = n.left.delete(compare, gen, augment, elem, all); deleted == 0 {
+			return n, 0
+		}
+
+		n = n.own(gen)
+		n.left = left
+	case c > 0:
+		var right *node[T]
+		if right, deleted = n.right.delete(compare, gen, augment, elem, all); deleted == 0 {
+			return n, 0
+		}
+
+		n = n.own(gen)
+		n.right = right
+	case !all && n.count > 1:
+		n = n.own(gen)
+		n.count--
+		deleted = 1
+	default:
+		deleted = n.count
+
 		if n.left == nil {
-			n.left = newNode(elem)
-			return true
+			return n.right, deleted
 		}
 
-		return n.left.add(elem)
-	case n.val > elem:
 		if n.right == nil {
-			n.right = newNode(elem)
-			return true
+			return n.left, deleted
 		}
-		return n.right.add(elem)
+
+		// Both children are present: replace value with in-order successor
+		// and remove the successor from the right subtree instead.
+		successor := n.right.min()
+		n = n.own(gen)
+		n.val, n.count, n.expires = successor.val, successor.count, successor.expires
+		n.right, _ = n.right.delete(compare, gen, augment, n.val, true)
 	}
-	return false
+
+	return n.rebalance(gen, augment), deleted
 }
 
-func (t *Tree[T]) Add(elem T) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
+// Delete removes elem with all its occurrences from the tree and reports whether it was present.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Delete(elem T) bool {
+	t.lock()
+	defer t.unlock()
 
-	if t.root == nil {
-		t.root = newNode(elem)
+	return t.delete(elem, true) > 0
+}
+
+// Remove removes a single occurrence of elem and reports whether it was present.
+// For a multiset it decrements count of elem, otherwise it is the same as Delete.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Remove(elem T) bool {
+	t.lock()
+	defer t.unlock()
+
+	return t.delete(elem, false) > 0
+}
+
+// delete removes elem under the write lock held by the caller.
+func (t *Tree[T]) delete(elem T, all bool) uint {
+	var deleted uint
+	if t.root, deleted = t.root.delete(t.compare, t.gen, t.augment, elem, all); deleted > 0 {
+		t.nodesCol -= deleted
+		t.commit()
+		t.changed(EventDelete, elem)
+	}
+
+	return deleted
+}
+
+// lookup returns the stored element equal to elem with its occurrences and deadline.
+func (t *Tree[T]) lookup(elem T) (T, uint, int64, bool) {
+	if n := t.root.find(t.compare, elem); n != nil {
+		return n.val, n.count, n.expires, true
+	}
+
+	var zero T
+
+	return zero, 0, 0, false
+}
+
+// setDeadline sets the deadline of elem if it is present.
+func (t *Tree[T]) setDeadline(elem T, at int64) {
+	// Looking elem up first keeps nodes shared with snapshots when it is absent.
+	if t.root.find(t.compare, elem) == nil {
 		return
 	}
 
-	if t.root.add(elem) {
-		t.nodesCol++
+	t.root = t.root.setDeadline(t.compare, t.gen, elem, at)
+}
+
+// length returns count of elements under the lock held by the caller.
+func (t *Tree[T]) length() uint {
+	return t.nodesCol
+}
+
+// Count returns count of occurrences of elem in the tree.
+// Asymptotic: O(log n)
+func (t *Tree[T]) Count(elem T) uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if n := t.root.find(t.compare, elem); n != nil {
+		return n.count
 	}
+
+	return 0
 }
 
+// Size returns count of elements in the tree, including duplicates of a multiset.
+// Asymptotic: O(1)
 func (t *Tree[T]) Size() uint {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -69,35 +287,44 @@ func (t *Tree[T]) Size() uint {
 	return t.nodesCol
 }
 
-func (n *node[T]) sorted() []T {
-	var sorted []T
-	if n.left != nil {
-		sorted = append(sorted, n.left.sorted()...)
+// appendSorted appends values of the subtree to dst in ascending (or descending) order.
+func (n *node[T]) appendSorted(dst []T, desc bool) []T {
+	if n == nil {
+		return dst
 	}
-	sorted = append(sorted, n.val)
-	if n.right != nil {
-		sorted = append(sorted, n.right.sorted()...)
+
+	first, last := n.left, n.right
+	if desc {
+		first, last = last, first
 	}
-	return sorted
-}
 
-func (t *Tree[T]) SortedDesc() []T {
-	var result []T
-	if t.root == nil {
-		return result
+	dst = first.appendSorted(dst, desc)
+	for range n.count {
+		dst = append(dst, n.val)
 	}
 
-	result = append(result, t.root.sorted()...)
-	return result
+	return last.appendSorted(dst, desc)
 }
 
+// SortedAsc returns all elements of the tree in ascending order.
+// Asymptotic: O(n)
 func (t *Tree[T]) SortedAsc() []T {
-	desc := t.SortedDesc()
-	result := make([]T, len(desc))
-	length := len(desc) - 1
-	for i := 0; i <= length; i++ {
-		result[i] = desc[length-i]
+	return t.sorted(false)
+}
+
+// SortedDesc returns all elements of the tree in descending order.
+// Asymptotic: O(n)
+func (t *Tree[T]) SortedDesc() []T {
+	return t.sorted(true)
+}
+
+func (t *Tree[T]) sorted(desc bool) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.root == nil {
+		return nil
 	}
 
-	return result
+	return t.root.appendSorted(make([]T, 0, t.nodesCol), desc)
 }
@@ -0,0 +1,219 @@
+package tree_test
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+func TestTreeBalance(t *testing.T) {
+	t.Parallel()
+
+	ascending := make([]int, 1000)
+	descending := make([]int, 1000)
+	for i := range ascending {
+		ascending[i] = i
+		descending[i] = len(descending) - i
+	}
+
+	tests := []struct {
+		name  string
+		input []int
+	}{
+		{
+			name:  "almost sorted input",
+			input: []int{44, 18, 1, 2, 10, 8},
+		},
+		{
+			name:  "ascending input",
+			input: ascending,
+		},
+		{
+			name:  "descending input",
+			input: descending,
+		},
+		{
+			name:  "zig-zag input",
+			input: []int{50, 10, 40, 20, 30, 25, 35, 22, 28},
+		},
+		{
+			name:  "random input",
+			input: rand.New(rand.NewSource(42)).Perm(500),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sut := tree.New[int]()
+			for _, v := range tt.input {
+				sut.Add(v)
+				sut.Add(v)
+
+				if err := sut.CheckInvariants(); err != nil {
+					t.Fatalf("after adding %d: %v", v, err)
+				}
+			}
+
+			expected := append([]int(nil), tt.input...)
+			sort.Ints(expected)
+
+			if got := sut.SortedAsc(); !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestTreeDelete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    []int
+		delete   []int
+		expected []int
+	}{
+		{
+			name:     "delete leaf",
+			input:    []int{2, 1, 3},
+			delete:   []int{3},
+			expected: []int{1, 2},
+		},
+		{
+			name:     "delete node with two children",
+			input:    []int{44, 18, 1, 2, 10, 8},
+			delete:   []int{8},
+			expected: []int{1, 2, 10, 18, 44},
+		},
+		{
+			name:     "delete root until empty",
+			input:    []int{1, 2, 3},
+			delete:   []int{2, 1, 3},
+			expected: nil,
+		},
+		{
+			name:     "delete absent element",
+			input:    []int{1, 2, 3},
+			delete:   []int{4},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "delete from empty tree",
+			delete:   []int{1},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sut := tree.New[int]()
+			for _, v := range tt.input {
+				sut.Add(v)
+			}
+
+			for _, v := range tt.delete {
+				sut.Delete(v)
+
+				if err := sut.CheckInvariants(); err != nil {
+					t.Fatalf("after deleting %d: %v", v, err)
+				}
+			}
+
+			if got := sut.SortedAsc(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+
+			if sut.Size() != uint(len(tt.expected)) {
+				t.Errorf("expected size %d, got %d", len(tt.expected), sut.Size())
+			}
+		})
+	}
+}
+
+func TestTreeRandomOperations(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(7))
+	sut := tree.New[int]()
+	model := make(map[int]bool)
+
+	for i := 0; i < 5000; i++ {
+		v := rnd.Intn(300)
+
+		if rnd.Intn(3) == 0 {
+			if deleted := sut.Delete(v); deleted != model[v] {
+				t.Fatalf("delete %d: expected %v, got %v", v, model[v], deleted)
+			}
+
+			delete(model, v)
+		} else {
+			sut.Add(v)
+			model[v] = true
+		}
+
+		if err := sut.CheckInvariants(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+
+		if sut.Size() != uint(len(model)) {
+			t.Fatalf("step %d: expected size %d, got %d", i, len(model), sut.Size())
+		}
+	}
+}
+
+func TestTreeSorted(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[string]()
+	for _, v := range []string{"delta", "alpha", "charlie", "bravo"} {
+		sut.Add(v)
+	}
+
+	if got, expected := sut.SortedAsc(), []string{"alpha", "bravo", "charlie", "delta"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if got, expected := sut.SortedDesc(), []string{"delta", "charlie", "bravo", "alpha"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func BenchmarkTree_AddSorted(b *testing.B) {
+	sut := tree.New[int]()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Add(i)
+	}
+}
+
+func BenchmarkTree_Delete(b *testing.B) {
+	sut := tree.New[int]()
+	for i := 0; i < b.N; i++ {
+		sut.Add(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Delete(i)
+	}
+}
+
+func BenchmarkTree_AddRandom(b *testing.B) {
+	sut := tree.New[int]()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Add(rand.Int())
+	}
+}
@@ -0,0 +1,277 @@
+package tree
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"time"
+)
+
+type (
+	// Clock tells the time and schedules expiry of elements added with TTL.
+	// The system clock is used unless another one is injected with WithClock,
+	// for example a fake one advanced manually to make expiry deterministic in tests.
+	Clock interface {
+		Now() time.Time
+		// AfterFunc calls f in its own goroutine once d has elapsed.
+		AfterFunc(d time.Duration, f func()) Timer
+	}
+
+	// Timer is a scheduled call of Clock.AfterFunc.
+	Timer interface {
+		// Stop prevents the call and reports whether it has not happened yet.
+		Stop() bool
+	}
+
+	systemClock struct{}
+
+	// deadline is a scheduled expiry of elem at Unix nanoseconds.
+	deadline[T any] struct {
+		at   int64
+		elem T
+	}
+
+	// deadlines is a min-heap of scheduled expiries. An expiry is stale and skipped
+	// once the element has been deleted or got another deadline,
+	// and stale ones are compacted away before they outnumber the elements.
+	deadlines[T any] []deadline[T]
+
+	// expiry keeps the TTL state of a tree, guarded by the tree lock.
+	expiry[T any] struct {
+		deadlines deadlines[T]
+		timer     Timer  // scheduled purge, nil when none.
+		armed     int64  // time of the scheduled purge.
+		round     uint64 // incremented on every scheduling, so a replaced timer firing anyway is ignored.
+		onExpire  func(elem T)
+		expired   []T // elements awaiting onExpire calls after unlocking.
+	}
+)
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (d deadlines[T]) Len() int           { return len(d) }
+func (d deadlines[T]) Less(i, j int) bool { return d[i].at < d[j].at }
+func (d deadlines[T]) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d *deadlines[T]) Push(x any)        { *d = append(*d, x.(deadline[T])) }
+
+func (d *deadlines[T]) Pop() any {
+	old := *d
+	last := old[len(old)-1]
+	*d = old[:len(old)-1]
+
+	return last
+}
+
+// WithClock makes the tree use clock for expiry of elements added with TTL.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// LazyExpiry disables background expiry: elements whose TTL has elapsed are purged
+// only by the next modification of the tree or by Expire, so no timers are used,
+// but reads may observe such elements until then.
+func LazyExpiry() Option {
+	return func(o *options) {
+		o.lazyExpiry = true
+	}
+}
+
+// clock returns the injected clock or the system one.
+func (c *core[T]) clock() Clock {
+	if c.opts.clock == nil {
+		return systemClock{}
+	}
+
+	return c.opts.clock
+}
+
+// now returns the current time of the tree clock in Unix nanoseconds.
+func (c *core[T]) now() int64 {
+	return c.clock().Now().UnixNano()
+}
+
+// setDeadline sets deadline of elem in the subtree, copying nodes of other generations
+// on the path to it, so elem must be present.
+func (n *node[T]) setDeadline(compare func(a, b T) int, gen uint64, elem T, at int64) *node[T] {
+	if n == nil {
+		return nil
+	}
+
+	n = n.own(gen)
+
+	switch c := compare(elem, n.val); {
+	case c < 0:
+		n.left = n.left.setDeadline(compare, gen, elem, at)
+	case c > 0:
+		n.right = n.right.setDeadline(compare, gen, elem, at)
+	default:
+		n.expires = at
+	}
+
+	return n
+}
+
+// AddWithTTL inserts elem into the tree like Add and makes it expire once ttl has elapsed:
+// all its occurrences are deleted and the OnExpire callback is called.
+// Adding a present element with TTL again replaces its deadline,
+// while Add keeps the deadline of a present element. Deadlines are neither
+// encoded nor inherited by trees derived with set operations.
+// Asymptotic: O(log n)
+func (c *core[T]) AddWithTTL(elem T, ttl time.Duration) {
+	c.lock()
+	defer c.unlock()
+
+	c.store.add(elem, c.duplicates())
+
+	at := c.now() + int64(ttl)
+	c.store.setDeadline(elem, at)
+	heap.Push(&c.ttl.deadlines, deadline[T]{at: at, elem: elem})
+
+	// Every element has one current deadline at most, so the rest are stale.
+	if len(c.ttl.deadlines) > 2*int(c.store.length()) {
+		c.compactDeadlines()
+	}
+
+	c.arm()
+}
+
+// compactDeadlines drops stale expiries from the heap, so refreshing TTL of the same
+// elements again and again keeps it proportional to the tree size.
+// Asymptotic: O(k log n) for k expiries, amortized O(log n) per AddWithTTL.
+func (c *core[T]) compactDeadlines() {
+	current := c.ttl.deadlines[:0]
+
+	for _, d := range c.ttl.deadlines {
+		if _, _, expires, found := c.store.lookup(d.elem); found && expires == d.at {
+			current = append(current, d)
+		}
+	}
+
+	// An element may be scheduled at its current deadline twice after getting
+	// an old deadline back. Sorted expiries are a valid heap, with duplicates adjacent.
+	slices.SortFunc(current, func(a, b deadline[T]) int {
+		if a.at != b.at {
+			return cmp.Compare(a.at, b.at)
+		}
+
+		return c.compare(a.elem, b.elem)
+	})
+
+	current = slices.CompactFunc(current, func(a, b deadline[T]) bool {
+		return a.at == b.at && c.compare(a.elem, b.elem) == 0
+	})
+
+	clear(c.ttl.deadlines[len(current):])
+	c.ttl.deadlines = current
+}
+
+// Deadline returns the time when elem expires. It reports false
+// when elem is absent or has been added without TTL.
+// Asymptotic: O(log n)
+func (c *core[T]) Deadline(elem T) (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, _, expires, found := c.store.lookup(elem); found && expires != 0 {
+		return time.Unix(0, expires), true
+	}
+
+	return time.Time{}, false
+}
+
+// OnExpire sets fn to be called with every expired element. It is called
+// without the tree lock held, so fn may access the tree: from a background goroutine,
+// or by the modification or Expire call that has purged the element under LazyExpiry.
+func (c *core[T]) OnExpire(fn func(elem T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl.onExpire = fn
+}
+
+// Expire purges elements whose TTL has elapsed and returns their count.
+// Asymptotic: O(k log n) for k expired elements.
+func (c *core[T]) Expire() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.purge()
+}
+
+// expireDue is called by the clock when the earliest deadline comes.
+// round is the scheduling that has set the timer: a timer fires anyway
+// when Stop comes too late, then the scheduling that has replaced it is left as is.
+func (c *core[T]) expireDue(round uint64) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if round != c.ttl.round {
+		return
+	}
+
+	c.ttl.timer = nil
+	c.purge()
+	c.arm()
+}
+
+// purge deletes elements whose deadline has passed under the write lock.
+func (c *core[T]) purge() int {
+	now, purged := c.now(), 0
+
+	for len(c.ttl.deadlines) > 0 && c.ttl.deadlines[0].at <= now {
+		d := heap.Pop(&c.ttl.deadlines).(deadline[T])
+
+		elem, _, expires, found := c.store.lookup(d.elem)
+		if !found || expires != d.at {
+			continue
+		}
+
+		c.store.delete(elem, true)
+		purged++
+
+		if c.ttl.onExpire != nil {
+			c.ttl.expired = append(c.ttl.expired, elem)
+		}
+	}
+
+	return purged
+}
+
+// arm schedules purge for the earliest deadline unless it is already scheduled.
+func (c *core[T]) arm() {
+	if c.opts.lazyExpiry || len(c.ttl.deadlines) == 0 {
+		return
+	}
+
+	at := c.ttl.deadlines[0].at
+	if c.ttl.timer != nil {
+		if c.ttl.armed <= at {
+			return
+		}
+
+		c.ttl.timer.Stop()
+	}
+
+	c.ttl.round++
+	c.ttl.armed = at
+
+	round := c.ttl.round
+	c.ttl.timer = c.clock().AfterFunc(time.Duration(at-c.now()), func() { c.expireDue(round) })
+}
+
+// lock takes the write lock for a modification, purging expired elements first under LazyExpiry.
+func (c *core[T]) lock() {
+	c.mu.Lock()
+
+	if c.opts.lazyExpiry {
+		c.purge()
+	}
+}
@@ -0,0 +1,354 @@
+package tree_test
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+type (
+	// fakeClock stands still until advanced, then calls due functions synchronously.
+	fakeClock struct {
+		mu     sync.Mutex
+		now    time.Time
+		timers []*fakeTimer
+
+		// lateStop makes Stop come too late: the timer fires anyway, as a real one
+		// does when it is stopped while its function is already starting.
+		lateStop bool
+	}
+
+	fakeTimer struct {
+		clock *fakeClock
+		at    time.Time
+		f     func()
+	}
+)
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) tree.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+
+	return timer
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	if t.clock.lateStop {
+		return false
+	}
+
+	before := len(t.clock.timers)
+	t.clock.timers = slices.DeleteFunc(t.clock.timers, func(other *fakeTimer) bool { return other == t })
+
+	return len(t.clock.timers) < before
+}
+
+// Advance moves the clock forward and calls functions of timers that are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+
+	var due []*fakeTimer
+
+	c.timers = slices.DeleteFunc(c.timers, func(timer *fakeTimer) bool {
+		if timer.at.After(c.now) {
+			return false
+		}
+
+		due = append(due, timer)
+
+		return true
+	})
+	c.mu.Unlock()
+
+	for _, timer := range due {
+		timer.f()
+	}
+}
+
+// pending returns count of scheduled timers.
+func (c *fakeClock) pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+func TestTreeTTL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []tree.Option
+		steps    func(t *testing.T, sut *tree.Tree[int], clock *fakeClock)
+		expected []int
+		expired  []int
+	}{
+		{
+			name: "elements expire in order of deadlines",
+			steps: func(t *testing.T, sut *tree.Tree[int], clock *fakeClock) {
+				sut.AddWithTTL(1, 3*time.Second)
+				sut.AddWithTTL(2, time.Second)
+				sut.AddWithTTL(3, 2*time.Second)
+				sut.Add(4)
+
+				clock.Advance(time.Second)
+				clock.Advance(time.Second)
+			},
+			expected: []int{1, 4},
+			expired:  []int{2, 3},
+		},
+		{
+			name: "adding with TTL again extends deadline",
+			steps: func(t *testing.T, sut *tree.Tree[int], clock *fakeClock) {
+				sut.AddWithTTL(1, time.Second)
+				sut.AddWithTTL(2, time.Second)
+				clock.Advance(time.Second / 2)
+				sut.AddWithTTL(1, time.Second)
+				sut.Add(2)
+				clock.Advance(time.Second / 2)
+			},
+			expected: []int{1},
+			expired:  []int{2},
+		},
+		{
+			name: "deleted element does not expire",
+			steps: func(t *testing.T, sut *tree.Tree[int], clock *fakeClock) {
+				sut.AddWithTTL(1, time.Second)
+				sut.Delete(1)
+				sut.Add(1)
+				clock.Advance(time.Hour)
+			},
+			expected: []int{1},
+			expired:  nil,
+		},
+		{
+			name: "all occurrences of multiset element expire",
+			opts: []tree.Option{tree.Multiset()},
+			steps: func(t *testing.T, sut *tree.Tree[int], clock *fakeClock) {
+				sut.Add(1)
+				sut.AddWithTTL(1, time.Second)
+				sut.Add(1)
+				sut.Add(2)
+				clock.Advance(time.Second)
+			},
+			expected: []int{2},
+			expired:  []int{1},
+		},
+		{
+			name: "lazy expiry waits for modification",
+			opts: []tree.Option{tree.LazyExpiry()},
+			steps: func(t *testing.T, sut *tree.Tree[int], clock *fakeClock) {
+				sut.AddWithTTL(1, time.Second)
+				sut.AddWithTTL(2, time.Second)
+				clock.Advance(time.Second)
+
+				if !sut.Contains(1) || clock.pending() != 0 {
+					t.Error("lazy expiry has purged element without modification")
+				}
+
+				sut.Add(3)
+			},
+			expected: []int{3},
+			expired:  []int{1, 2},
+		},
+		{
+			name: "explicit expiry",
+			opts: []tree.Option{tree.LazyExpiry()},
+			steps: func(t *testing.T, sut *tree.Tree[int], clock *fakeClock) {
+				sut.AddWithTTL(1, time.Second)
+				sut.AddWithTTL(2, 2*time.Second)
+				clock.Advance(time.Second)
+
+				if purged := sut.Expire(); purged != 1 {
+					t.Errorf("expected a single purged element, got %d", purged)
+				}
+			},
+			expected: []int{2},
+			expired:  []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := newFakeClock()
+			sut := tree.New[int](append(tt.opts, tree.WithClock(clock))...)
+
+			var expired []int
+
+			sut.OnExpire(func(elem int) {
+				expired = append(expired, elem)
+			})
+
+			tt.steps(t, sut, clock)
+
+			if got := sut.SortedAsc(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected tree %v, got %v", tt.expected, got)
+			}
+
+			if !reflect.DeepEqual(expired, tt.expired) {
+				t.Errorf("expected expired %v, got %v", tt.expired, expired)
+			}
+
+			if err := sut.CheckInvariants(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestTreeDeadline(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	sut := tree.New[int](tree.WithClock(clock))
+
+	sut.Add(1)
+	sut.AddWithTTL(2, time.Minute)
+
+	if _, ok := sut.Deadline(1); ok {
+		t.Error("element added without TTL has a deadline")
+	}
+
+	if at, ok := sut.Deadline(2); !ok || !at.Equal(clock.Now().Add(time.Minute)) {
+		t.Errorf("expected deadline in a minute, got %v", at)
+	}
+
+	// A callback is free to access the tree.
+	sut.OnExpire(func(elem int) {
+		sut.Add(elem * 10)
+	})
+
+	clock.Advance(time.Minute)
+
+	if got := sut.SortedAsc(); !reflect.DeepEqual(got, []int{1, 20}) {
+		t.Errorf("expected [1 20], got %v", got)
+	}
+}
+
+func TestTreeTTLLateStop(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	clock.lateStop = true
+	sut := tree.New[int](tree.WithClock(clock))
+
+	sut.AddWithTTL(1, 2*time.Second)
+	sut.AddWithTTL(3, 5*time.Second)
+	// The earlier deadline replaces the timer of 1, which fires anyway.
+	sut.AddWithTTL(2, time.Second)
+
+	clock.Advance(time.Second)
+	clock.Advance(time.Second)
+
+	if pending := clock.pending(); pending != 1 {
+		t.Errorf("expected a single timer for 3, got %d", pending)
+	}
+
+	if got := sut.SortedAsc(); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("expected [3], got %v", got)
+	}
+
+	clock.Advance(3 * time.Second)
+
+	if sut.Size() != 0 {
+		t.Errorf("expected 3 to expire, got %v", sut.SortedAsc())
+	}
+}
+
+func TestTreeTTLRefresh(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	sut := tree.New[int](tree.WithClock(clock))
+
+	// Sessions are refreshed on every request, alternating between two deadlines.
+	var ttls [3]time.Duration
+
+	for i := range 10_000 {
+		ttls[i%3] = time.Duration(1+i%2) * time.Minute
+		sut.AddWithTTL(i%3, ttls[i%3])
+	}
+
+	if pending := sut.PendingDeadlines(); pending > 2*3+1 {
+		t.Errorf("expected expiries proportional to 3 elements, got %d", pending)
+	}
+
+	for v, ttl := range ttls {
+		if at, ok := sut.Deadline(v); !ok || !at.Equal(clock.Now().Add(ttl)) {
+			t.Errorf("%d: expected deadline in %v, got %v", v, ttl, at)
+		}
+	}
+
+	clock.Advance(time.Minute)
+
+	if got := sut.SortedAsc(); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("expected [0 1] after a minute, got %v", got)
+	}
+
+	clock.Advance(time.Minute)
+
+	if sut.Size() != 0 || clock.pending() != 0 {
+		t.Errorf("expected all to expire, got %v with %d timers", sut.SortedAsc(), clock.pending())
+	}
+}
+
+func TestTreeTTLSystemClock(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+	expired := make(chan int, 1)
+
+	sut.OnExpire(func(elem int) {
+		expired <- elem
+	})
+
+	sut.AddWithTTL(1, time.Millisecond)
+
+	select {
+	case elem := <-expired:
+		if elem != 1 || sut.Contains(1) {
+			t.Errorf("expected 1 to expire, got %d", elem)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("element has not expired in background")
+	}
+}
+
+func BenchmarkTree_AddWithTTL(b *testing.B) {
+	clock := newFakeClock()
+	sut := tree.New[int](tree.WithClock(clock))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.AddWithTTL(i, time.Duration(i%1000)*time.Millisecond)
+
+		if i%1000 == 0 {
+			clock.Advance(time.Millisecond)
+		}
+	}
+}
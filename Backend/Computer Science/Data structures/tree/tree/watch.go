@@ -0,0 +1,337 @@
+package tree
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+)
+
+// defaultWatchBuffer is count of undelivered events a watch holds by default.
+const defaultWatchBuffer = 64
+
+const (
+	// EventAdd means an occurrence of the element has been added.
+	EventAdd EventKind = iota + 1
+	// EventDelete means one or all occurrences of the element have been deleted.
+	EventDelete
+)
+
+const (
+	blockOverflow overflow = iota
+	dropOverflow
+	coalesceOverflow
+)
+
+type (
+	// EventKind tells how an element has changed.
+	EventKind uint8
+
+	// Event describes a change of an element within a watched range.
+	Event[T any] struct {
+		// Seq numbers events of a watch from 1 in the order of modifications.
+		// A gap means that events have been dropped or coalesced.
+		Seq   uint64
+		Kind  EventKind
+		Elem  T
+		Count uint // occurrences of Elem in the tree after the change.
+	}
+
+	// WatchOption configures a watch created by Watch of Tree or ArenaTree.
+	WatchOption func(*watchOptions)
+
+	watchOptions struct {
+		policy overflow
+		buffer int
+	}
+
+	// overflow defines what a watch does when its consumer falls behind.
+	overflow uint8
+
+	// watcher queues events of its range for a dispatcher goroutine,
+	// which hands them over to the consumer one by one.
+	watcher[T any] struct {
+		compare func(a, b T) int
+		lo, hi  T
+		opts    watchOptions
+		done    <-chan struct{}
+		wake    chan struct{} // signals the dispatcher about a new pending event.
+
+		mu      sync.Mutex
+		seq     uint64          // number of the latest event.
+		pending list.List       // undelivered events, the front one is being delivered.
+		index   []*list.Element // pending events sorted by element, only when coalescing.
+		space   chan struct{}   // closed and replaced when a pending event is delivered.
+	}
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventAdd:
+		return "add"
+	case EventDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// WatchBlock makes modifications of the tree wait until at most buffer events
+// are left undelivered to a slow consumer, so no event is lost. With zero buffer
+// every modification waits until its event is received. It is the default policy
+// with buffer of 64 events. The consumer must not modify the tree itself,
+// otherwise it would wait for itself once the buffer is full.
+func WatchBlock(buffer int) WatchOption {
+	return func(o *watchOptions) {
+		o.policy, o.buffer = blockOverflow, max(buffer, 0)
+	}
+}
+
+// WatchDrop makes a watch discard new events while buffer (at least one)
+// events are left undelivered to a slow consumer. Modifications never wait,
+// the consumer detects lost events by gaps in their sequence numbers.
+func WatchDrop(buffer int) WatchOption {
+	return func(o *watchOptions) {
+		o.policy, o.buffer = dropOverflow, max(buffer, 1)
+	}
+}
+
+// WatchCoalesce makes a new event of an element replace its undelivered one,
+// so a slow consumer skips intermediate changes and ends up with the latest state
+// of every changed element, received in the order of the latest changes.
+// Modifications never wait and the watch holds at most one undelivered event
+// per distinct element of its range.
+func WatchCoalesce() WatchOption {
+	return func(o *watchOptions) {
+		o.policy = coalesceOverflow
+	}
+}
+
+// Watch subscribes to changes of elements of the half-open interval [lo, hi)
+// and returns a channel delivering their events in the order of modifications.
+// Deleting an absent element or adding a duplicate of a set changes nothing,
+// so it produces no event. Decoding into the tree produces events
+// for every element whose occurrences differ from the previous content.
+//
+// The watch lasts until ctx is done: then the channel is closed, undelivered events
+// are discarded and modifications waiting for the consumer are released.
+// By default a slow consumer blocks modifications of the tree, see WatchBlock,
+// WatchDrop and WatchCoalesce for other policies.
+func (c *core[T]) Watch(ctx context.Context, lo, hi T, opts ...WatchOption) <-chan Event[T] {
+	w := &watcher[T]{
+		compare: c.compare,
+		lo:      lo,
+		hi:      hi,
+		opts:    watchOptions{policy: blockOverflow, buffer: defaultWatchBuffer},
+		done:    ctx.Done(),
+		wake:    make(chan struct{}, 1),
+		space:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+
+	c.mu.Lock()
+	c.watchers = append(c.watchers, w)
+	c.mu.Unlock()
+
+	events := make(chan Event[T])
+	go c.dispatch(w, events)
+
+	return events
+}
+
+// dispatch delivers pending events of w to the consumer until the watch is done,
+// then unsubscribes w and closes the channel.
+func (c *core[T]) dispatch(w *watcher[T], events chan<- Event[T]) {
+	defer func() {
+		c.mu.Lock()
+		c.watchers = slices.DeleteFunc(c.watchers, func(other *watcher[T]) bool {
+			return other == w
+		})
+		c.mu.Unlock()
+
+		close(events)
+	}()
+
+	for {
+		w.mu.Lock()
+		front := w.pending.Front()
+		w.mu.Unlock()
+
+		if front == nil {
+			select {
+			case <-w.wake:
+				continue
+			case <-w.done:
+				return
+			}
+		}
+
+		select {
+		case events <- front.Value.(Event[T]):
+			w.delivered(front)
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// push queues a new event according to the overflow policy.
+func (w *watcher[T]) push(kind EventKind, elem T, count uint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.seq++
+	ev := Event[T]{Seq: w.seq, Kind: kind, Elem: elem, Count: count}
+
+	switch w.opts.policy {
+	case coalesceOverflow:
+		e := w.pending.PushBack(ev)
+
+		if i, found := w.locate(elem); found {
+			w.pending.Remove(w.index[i])
+			w.index[i] = e
+		} else {
+			w.index = slices.Insert(w.index, i, e)
+		}
+	case dropOverflow:
+		if w.pending.Len() >= w.opts.buffer {
+			return
+		}
+
+		w.pending.PushBack(ev)
+	default:
+		w.pending.PushBack(ev)
+	}
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// delivered removes the event received by the consumer from the pending ones,
+// unless it has already been replaced by a newer event of its element.
+func (w *watcher[T]) delivered(e *list.Element) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending.Remove(e)
+
+	if w.opts.policy == coalesceOverflow {
+		if i, found := w.locate(e.Value.(Event[T]).Elem); found && w.index[i] == e {
+			w.index = slices.Delete(w.index, i, i+1)
+		}
+	}
+
+	if w.opts.policy == blockOverflow {
+		close(w.space)
+		w.space = make(chan struct{})
+	}
+}
+
+// locate finds position of the pending event of elem in the index.
+func (w *watcher[T]) locate(elem T) (int, bool) {
+	return slices.BinarySearchFunc(w.index, elem, func(e *list.Element, elem T) int {
+		return w.compare(e.Value.(Event[T]).Elem, elem)
+	})
+}
+
+// wait blocks until at most buffer events are left undelivered or the watch is done.
+func (w *watcher[T]) wait() {
+	for {
+		w.mu.Lock()
+		full, space := w.pending.Len() > w.opts.buffer, w.space
+		w.mu.Unlock()
+
+		if !full {
+			return
+		}
+
+		select {
+		case <-space:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// unlock releases the write lock taken for a modification, then calls
+// the OnExpire callback for purged elements and waits for consumers of blocking watches.
+func (c *core[T]) unlock() {
+	expired, onExpire := c.ttl.expired, c.ttl.onExpire
+	c.ttl.expired = nil
+
+	var blocking []*watcher[T]
+
+	for _, w := range c.watchers {
+		if w.opts.policy == blockOverflow {
+			blocking = append(blocking, w)
+		}
+	}
+
+	c.mu.Unlock()
+
+	for _, elem := range expired {
+		onExpire(elem)
+	}
+
+	for _, w := range blocking {
+		w.wait()
+	}
+}
+
+// notify queues an event for every watcher whose range holds elem, under the write lock.
+func (c *core[T]) notify(kind EventKind, elem T, count uint) {
+	for _, w := range c.watchers {
+		if c.compare(elem, w.lo) >= 0 && c.compare(elem, w.hi) < 0 {
+			w.push(kind, elem, count)
+		}
+	}
+}
+
+// changed notifies watchers about the modification of elem, under the write lock.
+func (c *core[T]) changed(kind EventKind, elem T) {
+	if len(c.watchers) == 0 {
+		return
+	}
+
+	_, count, _, _ := c.store.lookup(elem)
+	c.notify(kind, elem, count)
+}
+
+// reloaded notifies watchers about elements whose occurrences differ
+// between the previous and the current groups, both sorted in ascending order.
+func (c *core[T]) reloaded(prev, cur []group[T]) {
+	for len(prev) > 0 || len(cur) > 0 {
+		diff := 0
+
+		switch {
+		case len(prev) == 0:
+			diff = 1
+		case len(cur) == 0:
+			diff = -1
+		default:
+			diff = c.compare(prev[0].val, cur[0].val)
+		}
+
+		switch {
+		case diff < 0:
+			c.notify(EventDelete, prev[0].val, 0)
+			prev = prev[1:]
+		case diff > 0:
+			c.notify(EventAdd, cur[0].val, cur[0].count)
+			cur = cur[1:]
+		default:
+			if prev[0].count < cur[0].count {
+				c.notify(EventAdd, cur[0].val, cur[0].count)
+			} else if prev[0].count > cur[0].count {
+				c.notify(EventDelete, cur[0].val, cur[0].count)
+			}
+
+			prev, cur = prev[1:], cur[1:]
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package tree_test
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dzianismaroz/marathon/tree/tree"
+)
+
+// receive waits for the next event of a watch, failing the test on timeout or closed channel.
+func receive[T any](t *testing.T, events <-chan tree.Event[T]) tree.Event[T] {
+	t.Helper()
+
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("events channel is closed")
+		}
+
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no event within a second")
+	}
+
+	return tree.Event[T]{}
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []tree.Option
+		initial  []int
+		modify   func(sut *tree.Tree[int]) error
+		expected []tree.Event[int]
+	}{
+		{
+			name: "changes within range",
+			modify: func(sut *tree.Tree[int]) error {
+				for _, v := range []int{5, 10, 15, 15, 20} {
+					sut.Add(v)
+				}
+
+				sut.Delete(15)
+				sut.Delete(16)
+				sut.Add(19)
+
+				return nil
+			},
+			expected: []tree.Event[int]{
+				{Seq: 1, Kind: tree.EventAdd, Elem: 10, Count: 1},
+				{Seq: 2, Kind: tree.EventAdd, Elem: 15, Count: 1},
+				{Seq: 3, Kind: tree.EventDelete, Elem: 15, Count: 0},
+				{Seq: 4, Kind: tree.EventAdd, Elem: 19, Count: 1},
+			},
+		},
+		{
+			name: "multiset occurrences",
+			opts: []tree.Option{tree.Multiset()},
+			modify: func(sut *tree.Tree[int]) error {
+				sut.Add(12)
+				sut.Add(12)
+				sut.Add(12)
+				sut.Remove(12)
+				sut.Delete(12)
+
+				return nil
+			},
+			expected: []tree.Event[int]{
+				{Seq: 1, Kind: tree.EventAdd, Elem: 12, Count: 1},
+				{Seq: 2, Kind: tree.EventAdd, Elem: 12, Count: 2},
+				{Seq: 3, Kind: tree.EventAdd, Elem: 12, Count: 3},
+				{Seq: 4, Kind: tree.EventDelete, Elem: 12, Count: 2},
+				{Seq: 5, Kind: tree.EventDelete, Elem: 12, Count: 0},
+			},
+		},
+		{
+			name:    "decoding reports difference",
+			opts:    []tree.Option{tree.Multiset()},
+			initial: []int{1, 10, 11, 12, 12},
+			modify: func(sut *tree.Tree[int]) error {
+				return sut.UnmarshalJSON([]byte(`[2, 11, 12, 13, 13, 30]`))
+			},
+			expected: []tree.Event[int]{
+				{Seq: 1, Kind: tree.EventDelete, Elem: 10, Count: 0},
+				{Seq: 2, Kind: tree.EventDelete, Elem: 12, Count: 1},
+				{Seq: 3, Kind: tree.EventAdd, Elem: 13, Count: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sut := tree.New[int](tt.opts...)
+			for _, v := range tt.initial {
+				sut.Add(v)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := sut.Watch(ctx, 10, 20)
+
+			if err := tt.modify(sut); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []tree.Event[int]
+			for range tt.expected {
+				got = append(got, receive(t, events))
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWatchCancel(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := sut.Watch(ctx, 0, 10, tree.WatchBlock(0))
+
+	added := make(chan struct{})
+
+	go func() {
+		sut.Add(1)
+		close(added)
+	}()
+
+	select {
+	case <-added:
+		t.Fatal("modification does not wait for the consumer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Fatal("modification is not released on cancellation")
+	}
+
+	for range events {
+		t.Error("undelivered event is not discarded")
+	}
+
+	sut.Add(2) // no watchers are left to wait for.
+}
+
+func TestWatchDrop(t *testing.T) {
+	t.Parallel()
+
+	sut := tree.New[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := sut.Watch(ctx, 0, 100, tree.WatchDrop(2))
+
+	for i := range 10 {
+		sut.Add(i)
+	}
+
+	for _, seq := range []uint64{1, 2} {
+		if ev := receive(t, events); ev.Seq != seq {
+			t.Fatalf("expected event %d, got %v", seq, ev)
+		}
+	}
+
+	sut.Add(50)
+
+	if ev := receive(t, events); ev.Seq != 11 || ev.Elem != 50 {
+		t.Errorf("expected event 11 of 50 after a gap, got %v", ev)
+	}
+}
+
+func TestWatchCoalesce(t *testing.T) {
+	t.Parallel()
+
+	const sentinel = 99
+
+	rnd := rand.New(rand.NewSource(13))
+	sut := tree.New[int](tree.Multiset())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := sut.Watch(ctx, 0, 100, tree.WatchCoalesce())
+
+	for range 5000 {
+		if v := rnd.Intn(20); rnd.Intn(3) == 0 {
+			sut.Remove(v)
+		} else {
+			sut.Add(v)
+		}
+	}
+
+	sut.Add(sentinel)
+
+	var (
+		state    = map[int]uint{}
+		received int
+		last     uint64
+	)
+
+	for ev := receive(t, events); ; ev = receive(t, events) {
+		if ev.Seq <= last {
+			t.Fatalf("event %v is out of order after %d", ev, last)
+		}
+
+		last = ev.Seq
+		received++
+		state[ev.Elem] = ev.Count
+
+		if ev.Elem == sentinel {
+			break
+		}
+	}
+
+	// The first event may be delivered before coalescing, then one per element.
+	if received > 22 {
+		t.Errorf("expected coalesced events, got %d", received)
+	}
+
+	for v, count := range state {
+		if actual := sut.Count(v); actual != count {
+			t.Errorf("expected %d occurrences of %d by events, got %d in the tree", count, v, actual)
+		}
+	}
+}
+
+func TestWatchConcurrentWriters(t *testing.T) {
+	t.Parallel()
+
+	const (
+		writers = 4
+		adds    = 500
+	)
+
+	sut := tree.New[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := sut.Watch(ctx, 0, writers*adds, tree.WatchBlock(8))
+
+	var wg sync.WaitGroup
+
+	for w := range writers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range adds {
+				sut.Add(w*adds + i)
+			}
+		}()
+	}
+
+	seen := make(map[int]bool, writers*adds)
+
+	for seq := uint64(1); seq <= writers*adds; seq++ {
+		ev := receive(t, events)
+		if ev.Seq != seq || ev.Kind != tree.EventAdd || seen[ev.Elem] {
+			t.Fatalf("expected event %d of a new element, got %v", seq, ev)
+		}
+
+		seen[ev.Elem] = true
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkTree_AddWatched(b *testing.B) {
+	sut := tree.New[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := sut.Watch(ctx, 0, b.N)
+
+	go func() {
+		for range events {
+		}
+	}()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sut.Add(i)
+	}
+}
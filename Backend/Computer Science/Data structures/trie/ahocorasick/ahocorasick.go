@@ -0,0 +1,211 @@
+// Package ahocorasick implements the Aho-Corasick automaton: a trie of patterns
+// where every node also links to the node of its longest proper suffix present in the trie,
+// so all occurrences of all patterns are found in a single pass over the text
+// in O(len(text) + count of matches) regardless of count of patterns.
+package ahocorasick
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrEmptyPattern is wrapped by errors of New given an empty pattern, which would match
+// at every position.
+var ErrEmptyPattern = errors.New("empty pattern")
+
+type (
+	options struct {
+		caseFold   bool
+		wholeWords bool
+	}
+
+	// Option configures a matcher created by New.
+	Option func(*options)
+
+	// Matcher finds occurrences of a fixed dictionary of patterns.
+	// It is immutable, so it is safe for concurrent use.
+	// Use New to create a matcher.
+	Matcher struct {
+		options
+
+		classes    [256]uint16 // bytes of patterns are numbered from 1, the others are 0.
+		numClasses int
+		// delta is the complete transition table: the state after state s and byte b
+		// is delta[s*numClasses+classes[b]], fail links are resolved in advance.
+		delta []int32
+		own   [][]int // patterns ending at a state.
+		dict  []int32 // the nearest state with own patterns along fail links, -1 if none.
+		lens  []int   // lengths of patterns, case folded if enabled.
+		max   int
+	}
+)
+
+// CaseFold makes the matcher compare texts with Unicode simple case folding:
+// "error" matches "ERROR" and "Error", "k" matches the Kelvin sign U+212A,
+// but "ß" does not match "ss", as it takes full case folding.
+func CaseFold() Option {
+	return func(o *options) {
+		o.caseFold = true
+	}
+}
+
+// WholeWords makes the matcher report only occurrences which are neither preceded
+// nor followed by a word character: a letter, a digit or an underscore.
+func WholeWords() Option {
+	return func(o *options) {
+		o.wholeWords = true
+	}
+}
+
+// foldRune returns the representative of the case folding orbit of r: the least rune of it.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+
+		return r
+	}
+
+	least := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		least = min(least, f)
+	}
+
+	return least
+}
+
+// isWord reports whether r is a word character.
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// foldString folds every rune of s, keeping bytes of invalid UTF-8 as they are.
+func foldString(s string) string {
+	folded := make([]byte, 0, len(s))
+
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && size == 1 {
+			folded = append(folded, s[0])
+		} else {
+			folded = utf8.AppendRune(folded, foldRune(r))
+		}
+
+		s = s[size:]
+	}
+
+	return string(folded)
+}
+
+// New builds the matcher of patterns, which are identified by their positions in the slice.
+// Equal patterns are reported each. It returns ErrEmptyPattern if any pattern is empty.
+// Asymptotic: O(total length of patterns * σ) for σ distinct bytes of patterns.
+func New(patterns []string, opts ...Option) (*Matcher, error) {
+	m := &Matcher{lens: make([]int, len(patterns))}
+	for _, opt := range opts {
+		opt(&m.options)
+	}
+
+	keys := make([]string, len(patterns))
+
+	for i, p := range patterns {
+		if p == "" {
+			return nil, fmt.Errorf("%w at %d", ErrEmptyPattern, i)
+		}
+
+		if keys[i] = p; m.caseFold {
+			keys[i] = foldString(p)
+		}
+
+		for j := 0; j < len(keys[i]); j++ {
+			if c := keys[i][j]; m.classes[c] == 0 {
+				m.numClasses++
+				m.classes[c] = uint16(m.numClasses)
+			}
+		}
+
+		m.lens[i] = len(keys[i])
+		m.max = max(m.max, len(keys[i]))
+	}
+
+	m.numClasses++ // for bytes out of patterns.
+
+	m.buildTrie(keys)
+	m.buildLinks()
+
+	return m, nil
+}
+
+// addState appends a state without transitions and returns it.
+func (m *Matcher) addState() int32 {
+	for range m.numClasses {
+		m.delta = append(m.delta, -1)
+	}
+
+	m.own = append(m.own, nil)
+	m.dict = append(m.dict, -1)
+
+	return int32(len(m.own) - 1)
+}
+
+// buildTrie fills the transitions along keys, leaving the others -1.
+func (m *Matcher) buildTrie(keys []string) {
+	m.addState()
+
+	for i, key := range keys {
+		var s int32
+
+		for j := 0; j < len(key); j++ {
+			at := int(s)*m.numClasses + int(m.classes[key[j]])
+			if m.delta[at] < 0 {
+				next := m.addState()
+				m.delta[at] = next
+			}
+
+			s = m.delta[at]
+		}
+
+		m.own[s] = append(m.own[s], i)
+	}
+}
+
+// buildLinks computes fail links in breadth-first order, so the fail state of a state
+// is complete before it, and replaces missing transitions by the transitions of fail states.
+func (m *Matcher) buildLinks() {
+	fail := make([]int32, len(m.own))
+	queue := make([]int32, 0, len(m.own))
+
+	for c := range m.numClasses {
+		if next := m.delta[c]; next < 0 {
+			m.delta[c] = 0
+		} else {
+			queue = append(queue, next)
+		}
+	}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		f := fail[s]
+		if len(m.own[f]) > 0 {
+			m.dict[s] = f
+		} else {
+			m.dict[s] = m.dict[f]
+		}
+
+		for c := range m.numClasses {
+			at, fat := int(s)*m.numClasses+c, int(f)*m.numClasses+c
+
+			if next := m.delta[at]; next < 0 {
+				m.delta[at] = m.delta[fat]
+			} else {
+				fail[next] = m.delta[fat]
+				queue = append(queue, next)
+			}
+		}
+	}
+}
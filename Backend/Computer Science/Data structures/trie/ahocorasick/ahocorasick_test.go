@@ -0,0 +1,278 @@
+package ahocorasick_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/dzianismaroz/marathon/trie/ahocorasick"
+)
+
+func mustNew(t testing.TB, patterns []string, opts ...ahocorasick.Option) *ahocorasick.Matcher {
+	t.Helper()
+
+	m, err := ahocorasick.New(patterns, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+// naive finds occurrences by comparing every pattern at every offset, ordered as Matches does.
+func naive(text string, patterns []string) []ahocorasick.Match {
+	var result []ahocorasick.Match
+
+	for end := 1; end <= len(text); end++ {
+		var ending []ahocorasick.Match
+
+		for id, p := range patterns {
+			if strings.HasSuffix(text[:end], p) {
+				ending = append(ending, ahocorasick.Match{Pattern: id, Start: int64(end - len(p)), End: int64(end)})
+			}
+		}
+
+		// The longer first, then in order of patterns as they share the state.
+		slices.SortStableFunc(ending, func(a, b ahocorasick.Match) int {
+			return int(a.Start - b.Start)
+		})
+
+		result = append(result, ending...)
+	}
+
+	return result
+}
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+
+	patterns := []string{"he", "she", "his", "hers", "she"}
+	sut := mustNew(t, patterns)
+
+	expected := []ahocorasick.Match{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 4, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 3, Start: 2, End: 6},
+	}
+
+	if got := sut.FindAll("ushers"); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if got := sut.FindAll("nothing to see"); got != nil {
+		t.Errorf("expected no matches, got %v", got)
+	}
+}
+
+func TestEmptyPattern(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ahocorasick.New([]string{"a", ""}); !errors.Is(err, ahocorasick.ErrEmptyPattern) {
+		t.Errorf("expected %v, got %v", ahocorasick.ErrEmptyPattern, err)
+	}
+}
+
+func TestEmptyDictionary(t *testing.T) {
+	t.Parallel()
+
+	for _, patterns := range [][]string{nil, {}} {
+		for _, opts := range [][]ahocorasick.Option{nil, {ahocorasick.CaseFold(), ahocorasick.WholeWords()}} {
+			if got := mustNew(t, patterns, opts...).FindAll("hello, world"); got != nil {
+				t.Errorf("expected no matches for %#v, got %v", patterns, got)
+			}
+		}
+	}
+}
+
+func TestCaseFold(t *testing.T) {
+	t.Parallel()
+
+	sut := mustNew(t, []string{"error", "Привет", "kb", "straße"}, ahocorasick.CaseFold())
+
+	// The Kelvin sign is 3 bytes long, so offsets are of the input rather than of the folded text.
+	text := "ERROR: ПРИВЕТ from 4\u212aB, STRAẞE, strasse, Error"
+
+	expected := []ahocorasick.Match{
+		{Pattern: 0, Start: 0, End: 5},
+		{Pattern: 1, Start: 7, End: 19},
+		{Pattern: 2, Start: 26, End: 30},
+		{Pattern: 3, Start: 32, End: 40},
+		{Pattern: 0, Start: 51, End: 56},
+	}
+
+	if got := sut.FindAll(text); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestWholeWords(t *testing.T) {
+	t.Parallel()
+
+	sut := mustNew(t, []string{"cat", "cat food", "дом"}, ahocorasick.WholeWords())
+
+	text := "cat concat cat_ (cat) cats cat food домой дом"
+
+	var got []string
+
+	for _, m := range sut.FindAll(text) {
+		got = append(got, fmt.Sprintf("%d:%s", m.Start, text[m.Start:m.End]))
+	}
+
+	expected := []string{"0:cat", "17:cat", "27:cat", "27:cat food", "47:дом"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	t.Parallel()
+
+	patterns := []string{"timeout", "refused", "panic:"}
+	text := strings.Repeat("GET /health 200\nconnection refused\n", 3) + "panic: timeout"
+
+	for _, opts := range [][]ahocorasick.Option{nil, {ahocorasick.CaseFold(), ahocorasick.WholeWords()}} {
+		sut := mustNew(t, patterns, opts...)
+
+		var got []ahocorasick.Match
+
+		// Runes and matches span reads of a single byte.
+		for m, err := range sut.Matches(iotest.OneByteReader(strings.NewReader(text))) {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got = append(got, m)
+		}
+
+		if expected := sut.FindAll(text); !slices.Equal(got, expected) || len(got) != 5 {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestReadError(t *testing.T) {
+	t.Parallel()
+
+	failure := errors.New("connection reset")
+	sut := mustNew(t, []string{"ab"})
+
+	var (
+		got     []ahocorasick.Match
+		lastErr error
+	)
+
+	for m, err := range sut.Matches(io.MultiReader(strings.NewReader("abab"), iotest.ErrReader(failure))) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+
+		got = append(got, m)
+	}
+
+	if len(got) != 2 || !errors.Is(lastErr, failure) {
+		t.Errorf("expected 2 matches and %v, got %v and %v", failure, got, lastErr)
+	}
+
+	// Breaking early stops reading.
+	for range sut.Matches(io.MultiReader(strings.NewReader("ab"), iotest.ErrReader(failure))) {
+		break
+	}
+}
+
+func TestRandomAgainstNaive(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(23))
+	random := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "aab"[rnd.Intn(3)]
+		}
+
+		return string(b)
+	}
+
+	for range 300 {
+		patterns := make([]string, 1+rnd.Intn(8))
+		for i := range patterns {
+			patterns[i] = random(1 + rnd.Intn(4))
+		}
+
+		text := random(rnd.Intn(60))
+
+		if got, expected := mustNew(t, patterns).FindAll(text), naive(text, patterns); !slices.Equal(got, expected) {
+			t.Fatalf("%q in %q: expected %v, got %v", patterns, text, expected, got)
+		}
+
+		// Folding changes nothing for texts of lower case letters.
+		upper := strings.ToUpper(text)
+		folded := mustNew(t, patterns, ahocorasick.CaseFold()).FindAll(upper)
+
+		if expected := naive(text, patterns); !slices.Equal(folded, expected) {
+			t.Fatalf("%q in %q: expected %v, got %v", patterns, upper, expected, folded)
+		}
+	}
+}
+
+func BenchmarkMatches(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	word := func() string {
+		w := make([]byte, 4+rnd.Intn(8))
+		for i := range w {
+			w[i] = byte('a' + rnd.Intn(26))
+		}
+
+		return string(w)
+	}
+
+	keywords := make([]string, 5000)
+	for i := range keywords {
+		keywords[i] = word()
+	}
+
+	var log strings.Builder
+	for log.Len() < 1<<20 {
+		fmt.Fprintf(&log, "2024-05-01T12:00:00Z level=info msg=%q user=%s\n", word()+" "+word(), word())
+	}
+
+	text := log.String()
+
+	for _, tt := range []struct {
+		name string
+		opts []ahocorasick.Option
+	}{
+		{name: "exact"},
+		{name: "case fold whole words", opts: []ahocorasick.Option{ahocorasick.CaseFold(), ahocorasick.WholeWords()}},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			sut := mustNew(b, keywords, tt.opts...)
+
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				for range sut.Matches(strings.NewReader(text)) {
+				}
+			}
+		})
+	}
+
+	// One pass of strings.Count per keyword for comparison.
+	b.Run("strings.Count", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+
+		for i := 0; i < b.N; i++ {
+			for _, k := range keywords {
+				strings.Count(text, k)
+			}
+		}
+	})
+}
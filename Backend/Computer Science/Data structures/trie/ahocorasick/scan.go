@@ -0,0 +1,158 @@
+package ahocorasick
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// Match is an occurrence of a pattern.
+	Match struct {
+		Pattern    int   // position of the pattern in the slice passed to New.
+		Start, End int64 // byte offsets of the occurrence in the input, End is exclusive.
+	}
+
+	// position describes a byte fed to the automaton.
+	position struct {
+		offset    int64 // in the input, of the whole rune when case folding.
+		runeStart bool  // whether the byte starts a rune.
+		afterWord bool  // whether the rune before is a word character.
+	}
+
+	// scanner is the state of a single pass over an input.
+	scanner struct {
+		m       *Matcher
+		state   int32
+		fed     uint64     // count of bytes fed to the automaton.
+		ring    []position // of the last fed bytes, enough to find where any pattern starts.
+		pending []Match    // whole word candidates waiting for the next rune.
+	}
+)
+
+// feed passes b to the automaton and appends occurrences ending with it to found.
+// end is the offset in the input after b, or after its rune when case folding.
+func (s *scanner) feed(b byte, at position, end int64, lastOfRune bool, found []Match) []Match {
+	m := s.m
+
+	s.ring[s.fed%uint64(len(s.ring))] = at
+	s.fed++
+	s.state = m.delta[int(s.state)*m.numClasses+int(m.classes[b])]
+
+	for state := s.state; state >= 0; state = m.dict[state] {
+		for _, id := range m.own[state] {
+			start := s.ring[(s.fed-uint64(m.lens[id]))%uint64(len(s.ring))]
+
+			// An occurrence splitting a rune is never a whole word.
+			if m.wholeWords && (!start.runeStart || start.afterWord || !lastOfRune) {
+				continue
+			}
+
+			found = append(found, Match{Pattern: id, Start: start.offset, End: end})
+		}
+	}
+
+	return found
+}
+
+// Matches returns an iterator over occurrences of patterns in the text read from r
+// in the order of their ends, the longer first for equal ends. Overlapping occurrences
+// are reported all. The text is read in a single pass with buffering, so it may be a stream
+// of any length. A read error is yielded with a zero Match as the last element.
+//
+// With WholeWords an occurrence is yielded after the rune following it is read.
+// Asymptotic: O(len(text) + count of occurrences)
+func (m *Matcher) Matches(r io.Reader) iter.Seq2[Match, error] {
+	return func(yield func(Match, error) bool) {
+		br := bufio.NewReader(r)
+		// An empty dictionary matches nothing, but the ring still takes a fed byte.
+		s := scanner{m: m, ring: make([]position, max(m.max, 1))}
+
+		var (
+			offset   int64
+			prevWord bool
+			found    []Match
+			buf      [utf8.UTFMax]byte
+		)
+
+		for {
+			c, size, err := br.ReadRune()
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+
+				for _, match := range s.pending {
+					if !yield(match, nil) {
+						return
+					}
+				}
+
+				if err != nil {
+					yield(Match{}, err)
+				}
+
+				return
+			}
+
+			// Bytes of invalid UTF-8 go as they are and are not word characters.
+			encoded, word := buf[:0], isWord(c)
+
+			switch {
+			case c == utf8.RuneError && size == 1:
+				_ = br.UnreadRune()
+				b, _ := br.ReadByte()
+				encoded, word = append(encoded, b), false
+			case m.caseFold:
+				encoded = utf8.AppendRune(encoded, foldRune(c))
+			default:
+				encoded = utf8.AppendRune(encoded, c)
+			}
+
+			if m.wholeWords {
+				if !word {
+					found = append(found, s.pending...)
+				}
+
+				s.pending = s.pending[:0]
+			}
+
+			for i, b := range encoded {
+				at, end := position{offset + int64(i), i == 0, prevWord}, offset+int64(i)+1
+				if m.caseFold {
+					at.offset, end = offset, offset+int64(size)
+				}
+
+				if m.wholeWords {
+					s.pending = s.feed(b, at, end, i == len(encoded)-1, s.pending)
+				} else {
+					found = s.feed(b, at, end, i == len(encoded)-1, found)
+				}
+			}
+
+			for _, match := range found {
+				if !yield(match, nil) {
+					return
+				}
+			}
+
+			found = found[:0]
+			offset += int64(size)
+			prevWord = word
+		}
+	}
+}
+
+// FindAll returns all occurrences of patterns in s in the order of Matches.
+func (m *Matcher) FindAll(s string) []Match {
+	var result []Match
+
+	// Reading a string never fails.
+	for match := range m.Matches(strings.NewReader(s)) {
+		result = append(result, match)
+	}
+
+	return result
+}
@@ -0,0 +1,3 @@
+module github.com/dzianismaroz/marathon/trie
+
+go 1.23.2
@@ -0,0 +1,150 @@
+package radix
+
+import (
+	"iter"
+	"strings"
+)
+
+type (
+	// frame is a node being walked with position of its next child, -1 before the node itself is visited.
+	frame[V any] struct {
+		node *node[V]
+		next int
+	}
+
+	// walker performs lazy pre-order traversal of the subtree of a prefix with an explicit
+	// stack of nodes, one per edge of the current key beyond the subtree root.
+	walker[V any] struct {
+		tree   *Tree[V]
+		prefix string
+		key    []byte // the path of the node on top of the stack.
+		stack  []frame[V]
+	}
+)
+
+// seek positions the walker on the first key with the prefix greater than after,
+// or on the very first one when after is the prefix itself and inclusive is set.
+func (w *walker[V]) seek(after string, inclusive bool) {
+	w.stack = w.stack[:0]
+	w.key = w.key[:0]
+
+	n, path := w.tree.find(w.prefix)
+	if n == nil {
+		return
+	}
+
+	w.stack = append(w.stack, frame[V]{n, -1})
+	w.key = append(w.key, path...)
+
+	if !strings.HasPrefix(after, path) {
+		// The prefix ends in the middle of the edge to the subtree root, so after either
+		// precedes every key of the subtree or follows them all.
+		if after > path {
+			w.stack = w.stack[:0]
+		}
+
+		return
+	}
+
+	for i := len(path); i < len(after); {
+		top := &w.stack[len(w.stack)-1]
+		at, ok := n.child(after[i])
+
+		if !ok {
+			// Keys under the following children are greater than after.
+			top.next = at
+			return
+		}
+
+		c, rest := n.children[at], after[i:]
+		if !strings.HasPrefix(rest, c.label) {
+			// after leaves the edge: keys under c are all greater or all less than after.
+			if c.label > rest {
+				top.next = at
+			} else {
+				top.next = at + 1
+			}
+
+			return
+		}
+
+		top.next = at + 1
+		n, i = c, i+len(c.label)
+		w.stack = append(w.stack, frame[V]{n, -1})
+		w.key = append(w.key, n.label...)
+	}
+
+	if !inclusive {
+		// after itself is visited already, its children are not.
+		w.stack[len(w.stack)-1].next = 0
+	}
+}
+
+// next returns the next key of the walk with its value and reports whether there is one.
+func (w *walker[V]) next() (string, V, bool) {
+	for len(w.stack) > 0 {
+		top := &w.stack[len(w.stack)-1]
+
+		switch {
+		case top.next < 0:
+			top.next = 0
+
+			if top.node.terminal {
+				return string(w.key), top.node.val, true
+			}
+		case top.next < len(top.node.children):
+			child := top.node.children[top.next]
+			top.next++
+			w.stack = append(w.stack, frame[V]{child, -1})
+			w.key = append(w.key, child.label...)
+		default:
+			w.stack = w.stack[:len(w.stack)-1]
+			if len(w.stack) > 0 {
+				w.key = w.key[:len(w.key)-len(top.node.label)]
+			}
+		}
+	}
+
+	var zero V
+
+	return "", zero, false
+}
+
+// KeysWithPrefix returns an iterator over keys starting with prefix and their values
+// in ascending order, so autocomplete over a large subtree may stop after the first few.
+//
+// As for trie.Trie, the read lock is not held during yield, so the loop body
+// is free to modify the tree: then the walk re-seeks after the last yielded key.
+// Asymptotic: O(len(prefix) + log σ) to start, then O(len(key)) per yielded key at most.
+func (t *Tree[V]) KeysWithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.mu.RLock()
+
+		w := walker[V]{tree: t, prefix: prefix}
+		w.seek(prefix, true)
+
+		version := t.version
+
+		for {
+			key, val, ok := w.next()
+			t.mu.RUnlock()
+
+			if !ok || !yield(key, val) {
+				return
+			}
+
+			t.mu.RLock()
+
+			if t.version != version {
+				w.seek(key, false)
+				version = t.version
+			}
+		}
+	}
+}
+
+// All returns an iterator over all keys and their values in ascending order.
+// Asymptotic: O(total length of keys) for the full iteration.
+func (t *Tree[V]) All() iter.Seq2[string, V] {
+	return t.KeysWithPrefix("")
+}
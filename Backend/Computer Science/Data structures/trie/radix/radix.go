@@ -0,0 +1,332 @@
+// Package radix implements a compressed radix tree: a trie where every chain of nodes
+// with a single child and no key is merged into one edge labelled with a whole substring,
+// so the tree has at most 2n nodes for n keys however long they are.
+package radix
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrInvariant is wrapped by CheckInvariants errors naming a node that should have been
+// compressed into its child, an empty label, children out of order or a wrong size.
+var ErrInvariant = errors.New("radix tree invariant violated")
+
+type (
+	node[V any] struct {
+		label    string     // the edge from the parent, empty for the root only.
+		children []*node[V] // sorted by the first byte of label, which differs between siblings.
+		val      V
+		terminal bool // whether the path to the node is a key.
+	}
+
+	// Tree maps string keys to values and answers prefix queries.
+	// Keys are compared bytewise, so they are ordered as strings are.
+	// Use New to create a tree.
+	Tree[V any] struct {
+		mu      sync.RWMutex
+		root    node[V]
+		size    uint
+		version uint64 // incremented on every modification.
+	}
+)
+
+// New creates an empty tree.
+func New[V any]() *Tree[V] {
+	return &Tree[V]{}
+}
+
+// child returns position of the child with label starting with c, or where it would be inserted,
+// and whether it is there.
+func (n *node[V]) child(c byte) (int, bool) {
+	return slices.BinarySearchFunc(n.children, c, func(child *node[V], c byte) int {
+		return int(child.label[0]) - int(c)
+	})
+}
+
+// commonPrefixLen returns length of the longest common prefix of a and b.
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+// Insert associates val with key, replacing the previous value if any.
+// Asymptotic: O(len(key) + log σ) for σ distinct bytes following a prefix.
+func (t *Tree[V]) Insert(key string, val V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := &t.root
+
+	for key != "" {
+		at, ok := n.child(key[0])
+		if !ok {
+			n.children = slices.Insert(n.children, at, &node[V]{label: key})
+			n, key = n.children[at], ""
+
+			break
+		}
+
+		c := n.children[at]
+		common := commonPrefixLen(c.label, key)
+
+		if common < len(c.label) {
+			// key leaves the edge in the middle: split it at that point.
+			mid := &node[V]{label: c.label[:common], children: []*node[V]{c}}
+			c.label = c.label[common:]
+			n.children[at] = mid
+			c = mid
+		}
+
+		n, key = c, key[common:]
+	}
+
+	if !n.terminal {
+		n.terminal = true
+		t.size++
+	}
+
+	n.val = val
+	t.version++
+}
+
+// delete removes key from the subtree of n and reports whether it was present,
+// merging the child it goes through with its own single child if the child is not a key any more.
+func (n *node[V]) delete(key string) bool {
+	at, ok := n.child(key[0])
+	if !ok || !strings.HasPrefix(key, n.children[at].label) {
+		return false
+	}
+
+	c := n.children[at]
+
+	if rest := key[len(c.label):]; rest != "" {
+		if !c.delete(rest) {
+			return false
+		}
+	} else {
+		if !c.terminal {
+			return false
+		}
+
+		var zero V
+
+		c.terminal, c.val = false, zero
+	}
+
+	switch {
+	case c.terminal:
+	case len(c.children) == 0:
+		n.children = slices.Delete(n.children, at, at+1)
+	case len(c.children) == 1:
+		grandchild := c.children[0]
+		grandchild.label = c.label + grandchild.label
+		n.children[at] = grandchild
+	}
+
+	return true
+}
+
+// Delete removes key from the tree and reports whether it was present.
+// Asymptotic: O(len(key) + log σ)
+func (t *Tree[V]) Delete(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var deleted bool
+
+	if key == "" {
+		var zero V
+
+		deleted = t.root.terminal
+		t.root.terminal, t.root.val = false, zero
+	} else {
+		deleted = t.root.delete(key)
+	}
+
+	if deleted {
+		t.size--
+		t.version++
+	}
+
+	return deleted
+}
+
+// Search returns the value associated with key if presented.
+// Asymptotic: O(len(key) + log σ)
+func (t *Tree[V]) Search(key string) (V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	n := &t.root
+
+	for key != "" {
+		at, ok := n.child(key[0])
+		if !ok || !strings.HasPrefix(key, n.children[at].label) {
+			n = nil
+			break
+		}
+
+		n = n.children[at]
+		key = key[len(n.label):]
+	}
+
+	if n == nil || !n.terminal {
+		var zero V
+
+		return zero, false
+	}
+
+	return n.val, true
+}
+
+// Size returns count of keys in the tree.
+// Asymptotic: O(1)
+func (t *Tree[V]) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.size
+}
+
+// PrefixesOf returns an iterator over keys which s starts with and their values,
+// from the shortest to the longest. They are collected under the read lock,
+// so the loop body is free to modify the tree.
+// Asymptotic: O(len(s) + log σ)
+func (t *Tree[V]) PrefixesOf(s string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		type match struct {
+			end int
+			val V
+		}
+
+		var matches []match
+
+		t.mu.RLock()
+
+		n, end := &t.root, 0
+
+		for {
+			if n.terminal {
+				matches = append(matches, match{end, n.val})
+			}
+
+			if end == len(s) {
+				break
+			}
+
+			at, ok := n.child(s[end])
+			if !ok || !strings.HasPrefix(s[end:], n.children[at].label) {
+				break
+			}
+
+			n = n.children[at]
+			end += len(n.label)
+		}
+
+		t.mu.RUnlock()
+
+		for _, m := range matches {
+			if !yield(s[:m.end], m.val) {
+				return
+			}
+		}
+	}
+}
+
+// LongestPrefixOf returns the longest key that s starts with together with its value
+// and reports whether there is one.
+// Asymptotic: O(len(s) + log σ)
+func (t *Tree[V]) LongestPrefixOf(s string) (string, V, bool) {
+	var (
+		key   string
+		val   V
+		found bool
+	)
+
+	for key, val = range t.PrefixesOf(s) {
+		found = true
+	}
+
+	return key, val, found
+}
+
+// find returns the topmost node whose path starts with prefix together with the path, or nil.
+// The prefix may end in the middle of the edge to the node.
+func (t *Tree[V]) find(prefix string) (*node[V], string) {
+	n, path, rest := &t.root, "", prefix
+
+	for rest != "" {
+		at, ok := n.child(rest[0])
+		if !ok {
+			return nil, ""
+		}
+
+		c := n.children[at]
+
+		common := commonPrefixLen(c.label, rest)
+		if common < len(c.label) && common < len(rest) {
+			return nil, ""
+		}
+
+		n, path, rest = c, path+c.label, rest[common:]
+	}
+
+	return n, path
+}
+
+// CheckInvariants verifies that siblings are sorted by distinct first bytes of non-empty labels,
+// that every node but the root is a key or has two children at least, and that the size is right.
+func (t *Tree[V]) CheckInvariants() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var (
+		count uint
+		check func(n *node[V], key string) error
+	)
+
+	check = func(n *node[V], key string) error {
+		if n.terminal {
+			count++
+		}
+
+		if n != &t.root && !n.terminal && len(n.children) < 2 {
+			return fmt.Errorf("%w: node %q has %d children and no key", ErrInvariant, key, len(n.children))
+		}
+
+		for i, c := range n.children {
+			if c.label == "" {
+				return fmt.Errorf("%w: child of %q has an empty label", ErrInvariant, key)
+			}
+
+			if i > 0 && n.children[i-1].label[0] >= c.label[0] {
+				return fmt.Errorf("%w: children of %q are out of order", ErrInvariant, key)
+			}
+
+			if err := check(c, key+c.label); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := check(&t.root, ""); err != nil {
+		return err
+	}
+
+	if count != t.size {
+		return fmt.Errorf("%w: size is %d, counted %d keys", ErrInvariant, t.size, count)
+	}
+
+	return nil
+}
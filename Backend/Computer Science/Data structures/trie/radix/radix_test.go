@@ -0,0 +1,240 @@
+package radix_test
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/trie/radix"
+)
+
+func build(keys ...string) *radix.Tree[int] {
+	t := radix.New[int]()
+	for i, key := range keys {
+		t.Insert(key, i)
+	}
+
+	return t
+}
+
+func collect(seq func(func(string, int) bool)) []string {
+	var result []string
+	for key := range seq {
+		result = append(result, key)
+	}
+
+	return result
+}
+
+func TestInsertSplitsEdges(t *testing.T) {
+	t.Parallel()
+
+	sut := build("romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom")
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		expected int
+		found    bool
+	}{
+		{key: "romane", expected: 0, found: true},
+		{key: "rubicundus", expected: 6, found: true},
+		{key: "rom", expected: 7, found: true},
+		{key: "roman", found: false},
+		{key: "r", found: false},
+		{key: "", found: false},
+		{key: "rubiconx", found: false},
+	}
+
+	for _, tt := range tests {
+		if got, found := sut.Search(tt.key); got != tt.expected || found != tt.found {
+			t.Errorf("Search(%q): expected %d, %v, got %d, %v", tt.key, tt.expected, tt.found, got, found)
+		}
+	}
+
+	if sut.Size() != 8 {
+		t.Errorf("expected size 8, got %d", sut.Size())
+	}
+}
+
+func TestDeleteMergesEdges(t *testing.T) {
+	t.Parallel()
+
+	sut := build("test", "team", "toast", "te", "")
+
+	for _, key := range []string{"tea", "teamx", "t"} {
+		if sut.Delete(key) {
+			t.Errorf("deleted %q which is not a key", key)
+		}
+	}
+
+	for _, key := range []string{"team", "te", "", "toast"} {
+		if !sut.Delete(key) {
+			t.Errorf("expected %q to be deleted", key)
+		}
+
+		if err := sut.CheckInvariants(); err != nil {
+			t.Fatalf("after deleting %q: %v", key, err)
+		}
+	}
+
+	if got := collect(sut.All()); !slices.Equal(got, []string{"test"}) {
+		t.Errorf("expected [test] left, got %v", got)
+	}
+}
+
+func TestKeysWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	sut := build("/users", "/users/new", "/users/list", "/posts", "/")
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{prefix: "", expected: []string{"/", "/posts", "/users", "/users/list", "/users/new"}},
+		{prefix: "/us", expected: []string{"/users", "/users/list", "/users/new"}},
+		{prefix: "/users/n", expected: []string{"/users/new"}},
+		{prefix: "/users/x", expected: nil},
+		{prefix: "/users/news", expected: nil},
+	}
+
+	for _, tt := range tests {
+		if got := collect(sut.KeysWithPrefix(tt.prefix)); !slices.Equal(got, tt.expected) {
+			t.Errorf("KeysWithPrefix(%q): expected %v, got %v", tt.prefix, tt.expected, got)
+		}
+	}
+}
+
+func TestKeysWithPrefixStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	sut := build("/users", "/users/new", "/users/list", "/posts")
+
+	// Autocomplete takes the first suggestion only.
+	for key := range sut.KeysWithPrefix("/u") {
+		if key != "/users" {
+			t.Errorf("expected /users, got %s", key)
+		}
+
+		break
+	}
+}
+
+func TestKeysWithPrefixWhileModifying(t *testing.T) {
+	t.Parallel()
+
+	sut := build("/a", "/ab", "/abc", "/abd", "/b", "/ba", "/bcd")
+
+	// Deleting the yielded key with its subtree, adding keys before and after the current one,
+	// splitting and merging edges on the way.
+	var got []string
+
+	for key := range sut.KeysWithPrefix("/") {
+		got = append(got, key)
+
+		switch key {
+		case "/ab":
+			sut.Delete("/ab")
+			sut.Delete("/abc")
+			sut.Insert("/aa", 0)
+			sut.Insert("/abcd", 0)
+		case "/b":
+			sut.Delete("/ba")
+			sut.Insert("/bb", 0)
+			sut.Insert("/bc", 0)
+		}
+	}
+
+	if expected := []string{"/a", "/ab", "/abcd", "/abd", "/b", "/bb", "/bc", "/bcd"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if err := sut.CheckInvariants(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPrefixesOf(t *testing.T) {
+	t.Parallel()
+
+	sut := build("/", "/api", "/api/v1", "/api/v2")
+
+	if got := collect(sut.PrefixesOf("/api/v1/users")); !slices.Equal(got, []string{"/", "/api", "/api/v1"}) {
+		t.Errorf("unexpected prefixes %v", got)
+	}
+
+	if key, val, found := sut.LongestPrefixOf("/api/v3"); key != "/api" || val != 1 || !found {
+		t.Errorf("LongestPrefixOf: expected /api, 1, true, got %q, %d, %v", key, val, found)
+	}
+
+	if _, _, found := sut.LongestPrefixOf("api"); found {
+		t.Error("found a prefix of api")
+	}
+}
+
+func TestRandomAgainstMap(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(22))
+	randomKey := func() string {
+		var b strings.Builder
+		for range rnd.Intn(8) {
+			b.WriteByte("ab/"[rnd.Intn(3)])
+		}
+
+		return b.String()
+	}
+
+	sut := radix.New[int]()
+	model := map[string]int{}
+
+	for i := 0; i < 20_000; i++ {
+		key := randomKey()
+
+		if rnd.Intn(3) == 0 {
+			_, found := model[key]
+			if deleted := sut.Delete(key); deleted != found {
+				t.Fatalf("step %d: Delete(%q) = %v, expected %v", i, key, deleted, found)
+			}
+
+			delete(model, key)
+		} else {
+			sut.Insert(key, i)
+			model[key] = i
+		}
+
+		if i%500 != 0 {
+			continue
+		}
+
+		if err := sut.CheckInvariants(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+
+		prefix := randomKey()
+
+		var expected []string
+
+		for _, k := range slices.Sorted(maps.Keys(model)) {
+			if strings.HasPrefix(k, prefix) {
+				expected = append(expected, k)
+			}
+		}
+
+		if got := collect(sut.KeysWithPrefix(prefix)); !slices.Equal(got, expected) {
+			t.Fatalf("step %d: KeysWithPrefix(%q): expected %v, got %v", i, prefix, expected, got)
+		}
+
+		for k, v := range model {
+			if got, found := sut.Search(k); !found || got != v {
+				t.Fatalf("step %d: Search(%q) = %d, %v, expected %d", i, k, got, found, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,312 @@
+// Package router implements an http.Handler dispatching requests by method and path pattern.
+// Static parts of patterns are kept in compressed radix trees, so matching a path
+// takes time proportional to its length rather than to count of routes.
+//
+// A pattern starts with '/' and consists of segments separated by '/'. A segment ":name"
+// matches any non-empty segment of a path, a segment "*name" must be the last one
+// and matches the rest of a path, including further slashes. Matched values are available
+// to handlers with http.Request.PathValue. A static segment wins over a parameter,
+// and a parameter wins over a wildcard: "/users/new" is served before "/users/:id".
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dzianismaroz/marathon/trie/radix"
+)
+
+var (
+	// ErrInvalidPattern is wrapped by errors of Handle for a pattern not starting with /,
+	// with a misplaced or badly named parameter, or with a wildcard before its end.
+	ErrInvalidPattern = errors.New("invalid pattern")
+	// ErrConflict is wrapped by errors of Handle for a route registered already
+	// or naming its parameters differently than a registered one.
+	ErrConflict = errors.New("conflicting route")
+	// ErrNilHandler is wrapped by errors of Handle for a nil handler.
+	ErrNilHandler = errors.New("nil handler")
+)
+
+type (
+	// node is the point of a pattern after a static part, a parameter or a wildcard.
+	node struct {
+		static       *radix.Tree[*node] // nodes after static parts following this one.
+		param        *node
+		paramName    string
+		wildcard     *node
+		wildcardName string
+		handlers     map[string]http.Handler // by method, the pattern ends here if not empty.
+		names        []string                // names of parameters of the pattern ending here.
+	}
+
+	// Router matches requests against registered patterns and calls the handler of the matching one.
+	// It responds with 404 Not Found if no pattern matches the path and with 405 Method Not Allowed
+	// if patterns match it for other methods only.
+	// Use New to create a router.
+	Router struct {
+		mu   sync.RWMutex
+		root *node
+	}
+
+	// token is a part of a pattern: a static string, a parameter or a wildcard.
+	token struct {
+		kind byte // 0 for static, ':' or '*'.
+		text string
+	}
+)
+
+func newNode() *node {
+	return &node{static: radix.New[*node]()}
+}
+
+// New creates a router without routes.
+func New() *Router {
+	return &Router{root: newNode()}
+}
+
+// parse splits pattern into tokens.
+func parse(pattern string) ([]token, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("%w: %q does not start with /", ErrInvalidPattern, pattern)
+	}
+
+	var (
+		tokens []token
+		static strings.Builder
+		seen   = map[string]bool{}
+	)
+
+	// Every segment follows a slash, which belongs to the static part before it.
+	for _, segment := range strings.Split(pattern[1:], "/") {
+		static.WriteByte('/')
+
+		if segment == "" || segment[0] != ':' && segment[0] != '*' {
+			if strings.ContainsAny(segment, ":*") {
+				return nil, fmt.Errorf("%w: %q has : or * inside segment %q", ErrInvalidPattern, pattern, segment)
+			}
+
+			static.WriteString(segment)
+
+			continue
+		}
+
+		kind, name := segment[0], segment[1:]
+
+		switch {
+		case name == "" || strings.ContainsAny(name, ":*"):
+			return nil, fmt.Errorf("%w: %q has invalid name %q", ErrInvalidPattern, pattern, segment)
+		case seen[name]:
+			return nil, fmt.Errorf("%w: %q has name %q twice", ErrInvalidPattern, pattern, name)
+		case kind == '*' && !strings.HasSuffix(pattern, "/"+segment):
+			return nil, fmt.Errorf("%w: %q has wildcard %q before its end", ErrInvalidPattern, pattern, segment)
+		}
+
+		seen[name] = true
+		tokens = append(tokens, token{text: static.String()}, token{kind: kind, text: name})
+		static.Reset()
+	}
+
+	if static.Len() > 0 {
+		tokens = append(tokens, token{text: static.String()})
+	}
+
+	return tokens, nil
+}
+
+// Handle registers handler for requests with method and path matching pattern.
+// It returns ErrInvalidPattern for a malformed pattern and ErrConflict if the method is registered
+// for the same pattern already, or if the pattern names a parameter or a wildcard differently
+// than a registered pattern at the same position, or has a parameter where another has a wildcard.
+// It returns ErrNilHandler for a nil handler rather than failing on the first matching request.
+func (r *Router) Handle(method, pattern string, handler http.Handler) error {
+	if handler == nil {
+		return fmt.Errorf("%w: %s %s", ErrNilHandler, method, pattern)
+	}
+
+	tokens, err := parse(pattern)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Nodes are created only after all the checks pass, so a rejected pattern leaves no traces.
+	n := r.root
+
+	var (
+		names   []string
+		pending []func()
+	)
+
+	for _, tok := range tokens {
+		switch tok.kind {
+		case ':':
+			if n.wildcardName != "" {
+				return fmt.Errorf("%w: %s has parameter :%s where wildcard *%s is registered",
+					ErrConflict, pattern, tok.text, n.wildcardName)
+			}
+
+			if n.param != nil && n.paramName != tok.text {
+				return fmt.Errorf("%w: %s has parameter :%s where :%s is registered",
+					ErrConflict, pattern, tok.text, n.paramName)
+			}
+
+			if n.param == nil {
+				parent, child := n, newNode()
+				pending = append(pending, func() { parent.param, parent.paramName = child, tok.text })
+				n = child
+			} else {
+				n = n.param
+			}
+
+			names = append(names, tok.text)
+		case '*':
+			if n.paramName != "" {
+				return fmt.Errorf("%w: %s has wildcard *%s where parameter :%s is registered",
+					ErrConflict, pattern, tok.text, n.paramName)
+			}
+
+			if n.wildcard != nil && n.wildcardName != tok.text {
+				return fmt.Errorf("%w: %s has wildcard *%s where *%s is registered",
+					ErrConflict, pattern, tok.text, n.wildcardName)
+			}
+
+			if n.wildcard == nil {
+				parent, child := n, newNode()
+				pending = append(pending, func() { parent.wildcard, parent.wildcardName = child, tok.text })
+				n = child
+			} else {
+				n = n.wildcard
+			}
+
+			names = append(names, tok.text)
+		default:
+			child, ok := n.static.Search(tok.text)
+			if !ok {
+				parent, text := n, tok.text
+				child = newNode()
+				pending = append(pending, func() { parent.static.Insert(text, child) })
+			}
+
+			n = child
+		}
+	}
+
+	if _, ok := n.handlers[method]; ok {
+		return fmt.Errorf("%w: %s %s is registered already", ErrConflict, method, pattern)
+	}
+
+	for _, apply := range pending {
+		apply()
+	}
+
+	if n.handlers == nil {
+		n.handlers = map[string]http.Handler{}
+	}
+
+	n.handlers[method] = handler
+	n.names = names
+
+	return nil
+}
+
+// HandleFunc registers the handler function for requests with method and path matching pattern
+// the same way as Handle.
+func (r *Router) HandleFunc(method, pattern string, handler func(http.ResponseWriter, *http.Request)) error {
+	// A nil function converted to http.HandlerFunc is not a nil interface.
+	if handler == nil {
+		return r.Handle(method, pattern, nil)
+	}
+
+	return r.Handle(method, pattern, http.HandlerFunc(handler))
+}
+
+// match calls found for every node where a pattern matching path ends, in order of precedence,
+// with values of its parameters, until found returns true. It reports whether it did.
+func (n *node) match(path string, values []string, found func(*node, []string) bool) bool {
+	if path == "" && len(n.handlers) > 0 && found(n, values) {
+		return true
+	}
+
+	// Longer static parts are more specific, so they go first. There are few of them on a path.
+	var buf [8]*node
+
+	ends, nodes := make([]int, 0, len(buf)), buf[:0]
+
+	for prefix, child := range n.static.PrefixesOf(path) {
+		ends, nodes = append(ends, len(prefix)), append(nodes, child)
+	}
+
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if nodes[i].match(path[ends[i]:], values, found) {
+			return true
+		}
+	}
+
+	if n.param != nil {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+
+		if end > 0 && n.param.match(path[end:], append(values, path[:end]), found) {
+			return true
+		}
+	}
+
+	return n.wildcard != nil && found(n.wildcard, append(values, path))
+}
+
+// ServeHTTP dispatches the request to the handler of the most specific pattern matching its method and path.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var handler http.Handler
+
+	r.mu.RLock()
+
+	matched := r.root.match(req.URL.Path, nil, func(n *node, values []string) bool {
+		h, ok := n.handlers[req.Method]
+		if !ok {
+			return false
+		}
+
+		for i, name := range n.names {
+			req.SetPathValue(name, values[i])
+		}
+
+		handler = h
+
+		return true
+	})
+
+	var allowed []string
+
+	if !matched {
+		r.root.match(req.URL.Path, nil, func(n *node, _ []string) bool {
+			for method := range n.handlers {
+				if !slices.Contains(allowed, method) {
+					allowed = append(allowed, method)
+				}
+			}
+
+			return false
+		})
+	}
+
+	r.mu.RUnlock()
+
+	switch {
+	case matched:
+		handler.ServeHTTP(w, req)
+	case len(allowed) > 0:
+		slices.Sort(allowed)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, req)
+	}
+}
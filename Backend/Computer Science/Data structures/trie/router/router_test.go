@@ -0,0 +1,280 @@
+package router_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/trie/router"
+)
+
+// echo responds with the pattern and values of the given parameters.
+func echo(pattern string, names ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = name + "=" + r.PathValue(name)
+		}
+
+		fmt.Fprint(w, strings.Join(append([]string{r.Method, pattern}, values...), " "))
+	}
+}
+
+func mustHandle(t *testing.T, r *router.Router, method, pattern string, names ...string) {
+	t.Helper()
+
+	if err := r.Handle(method, pattern, echo(pattern, names...)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	sut := router.New()
+	mustHandle(t, sut, http.MethodGet, "/")
+	mustHandle(t, sut, http.MethodGet, "/users")
+	mustHandle(t, sut, http.MethodPost, "/users")
+	mustHandle(t, sut, http.MethodGet, "/users/new")
+	mustHandle(t, sut, http.MethodGet, "/users/:id", "id")
+	mustHandle(t, sut, http.MethodDelete, "/users/:id", "id")
+	mustHandle(t, sut, http.MethodGet, "/users/:id/posts/*rest", "id", "rest")
+	mustHandle(t, sut, http.MethodGet, "/users/:id/posts/latest", "id")
+	mustHandle(t, sut, http.MethodPost, "/users/new/posts", "id")
+	mustHandle(t, sut, http.MethodGet, "/static/*path", "path")
+	mustHandle(t, sut, http.MethodGet, "/:lang/docs", "lang")
+
+	tests := []struct {
+		method   string
+		path     string
+		code     int
+		expected string
+	}{
+		{method: "GET", path: "/", code: 200, expected: "GET /"},
+		{method: "GET", path: "/users", code: 200, expected: "GET /users"},
+		{method: "POST", path: "/users", code: 200, expected: "POST /users"},
+		{method: "GET", path: "/users/new", code: 200, expected: "GET /users/new"},
+		{method: "GET", path: "/users/42", code: 200, expected: "GET /users/:id id=42"},
+		{method: "DELETE", path: "/users/new", code: 200, expected: "DELETE /users/:id id=new"},
+		{
+			method: "GET", path: "/users/42/posts/2024/05/title", code: 200,
+			expected: "GET /users/:id/posts/*rest id=42 rest=2024/05/title",
+		},
+		{method: "GET", path: "/users/42/posts/", code: 200, expected: "GET /users/:id/posts/*rest id=42 rest="},
+		{method: "GET", path: "/users/42/posts/latest", code: 200, expected: "GET /users/:id/posts/latest id=42"},
+		// /users/new/posts is registered for POST only, so GET backtracks to the parameter.
+		{method: "GET", path: "/users/new/posts/x", code: 200, expected: "GET /users/:id/posts/*rest id=new rest=x"},
+		{method: "GET", path: "/static/css/site.css", code: 200, expected: "GET /static/*path path=css/site.css"},
+		{method: "GET", path: "/en/docs", code: 200, expected: "GET /:lang/docs lang=en"},
+		{method: "GET", path: "/users/", code: 404},
+		{method: "GET", path: "/users/42/", code: 404},
+		{method: "GET", path: "/users/42/posts", code: 404},
+		{method: "GET", path: "//docs", code: 404},
+		{method: "PUT", path: "/users/42", code: 405, expected: "DELETE, GET"},
+		{method: "GET", path: "/users/new/posts", code: 405, expected: "POST"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		sut.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if rec.Code != tt.code {
+			t.Errorf("%s %s: expected code %d, got %d", tt.method, tt.path, tt.code, rec.Code)
+			continue
+		}
+
+		switch tt.code {
+		case http.StatusOK:
+			if got := rec.Body.String(); got != tt.expected {
+				t.Errorf("%s %s: expected %q, got %q", tt.method, tt.path, tt.expected, got)
+			}
+		case http.StatusMethodNotAllowed:
+			if got := rec.Header().Get("Allow"); got != tt.expected {
+				t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.expected, got)
+			}
+		}
+	}
+}
+
+func TestHandleRejects(t *testing.T) {
+	t.Parallel()
+
+	sut := router.New()
+	mustHandle(t, sut, http.MethodGet, "/users/:id/posts", "id")
+	mustHandle(t, sut, http.MethodGet, "/files/*path", "path")
+
+	tests := []struct {
+		pattern  string
+		expected error
+	}{
+		{pattern: "users", expected: router.ErrInvalidPattern},
+		{pattern: "/users/:", expected: router.ErrInvalidPattern},
+		{pattern: "/users/id:x", expected: router.ErrInvalidPattern},
+		{pattern: "/a/*rest/b", expected: router.ErrInvalidPattern},
+		{pattern: "/a/:x/:x", expected: router.ErrInvalidPattern},
+		{pattern: "/users/:id/posts", expected: router.ErrConflict},
+		{pattern: "/users/:name", expected: router.ErrConflict},
+		{pattern: "/users/*rest", expected: router.ErrConflict},
+		{pattern: "/files/:name", expected: router.ErrConflict},
+		{pattern: "/files/*name", expected: router.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		if err := sut.Handle(http.MethodGet, tt.pattern, echo(tt.pattern)); !errors.Is(err, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.pattern, tt.expected, err)
+		}
+	}
+
+	// Rejected patterns leave no routes behind.
+	rec := httptest.NewRecorder()
+	sut.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a rejected pattern, got %d", rec.Code)
+	}
+
+	if err := sut.Handle(http.MethodGet, "/nil", nil); !errors.Is(err, router.ErrNilHandler) {
+		t.Errorf("nil handler: expected %v, got %v", router.ErrNilHandler, err)
+	}
+
+	if err := sut.HandleFunc(http.MethodGet, "/nil", nil); !errors.Is(err, router.ErrNilHandler) {
+		t.Errorf("nil handler function: expected %v, got %v", router.ErrNilHandler, err)
+	}
+
+	// The same pattern is fine for another method, and so are static segments beside parameters.
+	mustHandle(t, sut, http.MethodPost, "/users/:id/posts", "id")
+	mustHandle(t, sut, http.MethodGet, "/users/me")
+	mustHandle(t, sut, http.MethodGet, "/files/index.html")
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	sut := router.New()
+	mustHandle(t, sut, http.MethodGet, "/users/:id", "id")
+
+	srv := httptest.NewServer(sut)
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/users/%F0%9F%90%B9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parameters are matched against the decoded path.
+	if expected := "GET /users/:id id=🐹"; resp.StatusCode != http.StatusOK || string(body) != expected {
+		t.Errorf("expected 200 %q, got %s %q", expected, resp.Status, body)
+	}
+}
+
+// regexRouter is the naive alternative: a map of compiled patterns tried one by one.
+type regexRouter map[*regexp.Regexp]http.Handler
+
+func (r regexRouter) handle(method, pattern string, handler http.Handler) {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = `(?P<` + segment[1:] + `>[^/]+)`
+		case strings.HasPrefix(segment, "*"):
+			segments[i] = `(?P<` + segment[1:] + `>.*)`
+		default:
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+
+	r[regexp.MustCompile("^"+method+" "+strings.Join(segments, "/")+"$")] = handler
+}
+
+func (r regexRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	key := req.Method + " " + req.URL.Path
+
+	for re, handler := range r {
+		if m := re.FindStringSubmatch(key); m != nil {
+			for i, name := range re.SubexpNames()[1:] {
+				req.SetPathValue(name, m[i+1])
+			}
+
+			handler.ServeHTTP(w, req)
+
+			return
+		}
+	}
+
+	http.NotFound(w, req)
+}
+
+// routes resemble a REST API of a code hosting service.
+var routes = func() []string {
+	var result []string
+
+	for _, resource := range []string{"users", "orgs", "repos", "gists", "teams", "projects", "issues", "pulls"} {
+		result = append(result,
+			"/"+resource,
+			"/"+resource+"/:id",
+			"/"+resource+"/:id/events",
+			"/"+resource+"/:id/members",
+			"/"+resource+"/:id/comments/:comment",
+			"/"+resource+"/:id/files/*path",
+		)
+	}
+
+	return result
+}()
+
+func benchmarkRouter(b *testing.B, handler http.Handler) {
+	b.Helper()
+
+	paths := []string{
+		"/users", "/repos/marathon", "/orgs/go/members", "/pulls/7/comments/42", "/gists/1/files/a/b/c.go",
+	}
+	requests := make([]*http.Request, len(paths))
+
+	for i, path := range paths {
+		requests[i] = httptest.NewRequest(http.MethodGet, path, nil)
+	}
+
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		handler.ServeHTTP(w, requests[i%len(requests)])
+	}
+}
+
+func BenchmarkRouter(b *testing.B) {
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	b.Run("radix", func(b *testing.B) {
+		sut := router.New()
+		for _, pattern := range routes {
+			if err := sut.Handle(http.MethodGet, pattern, noop); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		benchmarkRouter(b, sut)
+	})
+
+	b.Run("regexps", func(b *testing.B) {
+		sut := regexRouter{}
+		for _, pattern := range routes {
+			sut.handle(http.MethodGet, pattern, noop)
+		}
+
+		benchmarkRouter(b, sut)
+	})
+}
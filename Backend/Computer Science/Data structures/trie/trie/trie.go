@@ -0,0 +1,236 @@
+// Package trie implements a prefix tree mapping string keys to values: every node
+// stands for a prefix and its children extend it by one byte, so lookups and prefix queries
+// take O(len(key)) regardless of how many keys are stored.
+package trie
+
+import (
+	"slices"
+	"sync"
+	"unicode/utf8"
+)
+
+type (
+	node[V any] struct {
+		label    byte       // the last byte of the prefix of the node.
+		children []*node[V] // sorted by label.
+		val      V
+		terminal bool // whether the prefix of the node is a key.
+	}
+
+	// Trie maps string keys to values and answers prefix queries.
+	// Keys are compared bytewise, so they are ordered as strings are.
+	// Use New to create a trie.
+	Trie[V any] struct {
+		mu      sync.RWMutex
+		root    node[V] // stands for the empty prefix.
+		size    uint
+		version uint64 // incremented on every modification.
+	}
+)
+
+// New creates an empty trie.
+func New[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// child returns position of the child labelled c, or where it would be inserted, and whether it is there.
+func (n *node[V]) child(c byte) (int, bool) {
+	return slices.BinarySearchFunc(n.children, c, func(child *node[V], c byte) int {
+		return int(child.label) - int(c)
+	})
+}
+
+// find returns the node of prefix or nil.
+func (t *Trie[V]) find(prefix string) *node[V] {
+	n := &t.root
+
+	for i := 0; i < len(prefix); i++ {
+		at, ok := n.child(prefix[i])
+		if !ok {
+			return nil
+		}
+
+		n = n.children[at]
+	}
+
+	return n
+}
+
+// Insert associates val with key, replacing the previous value if any.
+// Asymptotic: O(len(key) log σ) for σ distinct bytes following a prefix.
+func (t *Trie[V]) Insert(key string, val V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := &t.root
+
+	for i := 0; i < len(key); i++ {
+		at, ok := n.child(key[i])
+		if !ok {
+			n.children = slices.Insert(n.children, at, &node[V]{label: key[i]})
+		}
+
+		n = n.children[at]
+	}
+
+	if !n.terminal {
+		n.terminal = true
+		t.size++
+	}
+
+	n.val = val
+	t.version++
+}
+
+// delete removes key from the subtree of n and reports whether it was present
+// and whether n has become useless: neither a key nor a prefix of one.
+func (n *node[V]) delete(key string) (bool, bool) {
+	if key == "" {
+		if !n.terminal {
+			return false, false
+		}
+
+		var zero V
+
+		n.terminal, n.val = false, zero
+
+		return true, len(n.children) == 0
+	}
+
+	at, ok := n.child(key[0])
+	if !ok {
+		return false, false
+	}
+
+	deleted, prune := n.children[at].delete(key[1:])
+	if prune {
+		n.children = slices.Delete(n.children, at, at+1)
+	}
+
+	return deleted, !n.terminal && len(n.children) == 0
+}
+
+// Delete removes key from the trie and reports whether it was present.
+// Nodes left without keys below them are removed too.
+// Asymptotic: O(len(key) log σ)
+func (t *Trie[V]) Delete(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	deleted, _ := t.root.delete(key)
+	if deleted {
+		t.size--
+		t.version++
+	}
+
+	return deleted
+}
+
+// Search returns the value associated with key if presented.
+// Asymptotic: O(len(key) log σ)
+func (t *Trie[V]) Search(key string) (V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if n := t.find(key); n != nil && n.terminal {
+		return n.val, true
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// HasPrefix reports whether any key starts with prefix. Every key starts with the empty prefix.
+// Asymptotic: O(len(prefix) log σ)
+func (t *Trie[V]) HasPrefix(prefix string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	// Useless nodes are pruned, so every node is a prefix of some key, except the empty root.
+	n := t.find(prefix)
+
+	return n != nil && (n.terminal || len(n.children) > 0)
+}
+
+// Size returns count of keys in the trie.
+// Asymptotic: O(1)
+func (t *Trie[V]) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.size
+}
+
+// LongestCommonPrefix returns the longest prefix shared by all keys, trimmed to whole
+// UTF-8 characters, so "héllo" and "hèllo" share "h". It is empty for an empty trie.
+// Asymptotic: O(len(result))
+func (t *Trie[V]) LongestCommonPrefix() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var prefix []byte
+
+	for n := &t.root; !n.terminal && len(n.children) == 1; {
+		n = n.children[0]
+		prefix = append(prefix, n.label)
+	}
+
+	return string(trimIncompleteRune(prefix))
+}
+
+// trimIncompleteRune drops a trailing incomplete UTF-8 sequence.
+func trimIncompleteRune(s []byte) []byte {
+	// A sequence is at most utf8.UTFMax bytes long, so look for its start among the last ones.
+	for i := len(s) - 1; i >= 0 && i >= len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			if !utf8.FullRune(s[i:]) {
+				return s[:i]
+			}
+
+			break
+		}
+	}
+
+	return s
+}
+
+// LongestPrefixOf returns the longest key that s starts with together with its value,
+// e.g. the most specific route matching a path, and reports whether there is one.
+// Asymptotic: O(len(s) log σ)
+func (t *Trie[V]) LongestPrefixOf(s string) (string, V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var (
+		match *node[V]
+		end   int
+	)
+
+	n := &t.root
+
+	for i := 0; ; i++ {
+		if n.terminal {
+			match, end = n, i
+		}
+
+		if i == len(s) {
+			break
+		}
+
+		at, ok := n.child(s[i])
+		if !ok {
+			break
+		}
+
+		n = n.children[at]
+	}
+
+	if match == nil {
+		var zero V
+
+		return "", zero, false
+	}
+
+	return s[:end], match.val, true
+}
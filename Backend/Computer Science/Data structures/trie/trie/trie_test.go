@@ -0,0 +1,308 @@
+package trie_test
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dzianismaroz/marathon/trie/trie"
+)
+
+func build(keys ...string) *trie.Trie[int] {
+	t := trie.New[int]()
+	for i, key := range keys {
+		t.Insert(key, i)
+	}
+
+	return t
+}
+
+func keys[V any](t *trie.Trie[V], prefix string) []string {
+	var result []string
+	for key := range t.KeysWithPrefix(prefix) {
+		result = append(result, key)
+	}
+
+	return result
+}
+
+func TestInsertSearchDelete(t *testing.T) {
+	t.Parallel()
+
+	sut := build("tea", "ten", "to", "", "tea")
+
+	if sut.Size() != 4 {
+		t.Errorf("expected size 4, got %d", sut.Size())
+	}
+
+	tests := []struct {
+		key      string
+		expected int
+		found    bool
+	}{
+		{key: "tea", expected: 4, found: true},
+		{key: "ten", expected: 1, found: true},
+		{key: "", expected: 3, found: true},
+		{key: "te", found: false},
+		{key: "teas", found: false},
+		{key: "x", found: false},
+	}
+
+	for _, tt := range tests {
+		if got, found := sut.Search(tt.key); got != tt.expected || found != tt.found {
+			t.Errorf("Search(%q): expected %d, %v, got %d, %v", tt.key, tt.expected, tt.found, got, found)
+		}
+	}
+
+	if sut.Delete("te") {
+		t.Error("deleted a prefix which is not a key")
+	}
+
+	if !sut.Delete("tea") || sut.Delete("tea") {
+		t.Error("expected tea to be deleted exactly once")
+	}
+
+	if sut.HasPrefix("tea") {
+		t.Error("nodes of a deleted key are kept")
+	}
+
+	if !sut.HasPrefix("te") {
+		t.Error("te is a prefix of ten")
+	}
+
+	if sut.Size() != 3 {
+		t.Errorf("expected size 3, got %d", sut.Size())
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	t.Parallel()
+
+	sut := build("apple", "app")
+
+	for prefix, expected := range map[string]bool{
+		"": true, "a": true, "app": true, "appl": true, "apple": true, "apples": false, "b": false,
+	} {
+		if got := sut.HasPrefix(prefix); got != expected {
+			t.Errorf("HasPrefix(%q): expected %v, got %v", prefix, expected, got)
+		}
+	}
+
+	if trie.New[int]().HasPrefix("") {
+		t.Error("an empty trie has no keys with the empty prefix")
+	}
+}
+
+func TestKeysWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	sut := build("car", "cart", "carbon", "cat", "dog", "ca", "c")
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{prefix: "", expected: []string{"c", "ca", "car", "carbon", "cart", "cat", "dog"}},
+		{prefix: "car", expected: []string{"car", "carbon", "cart"}},
+		{prefix: "cart", expected: []string{"cart"}},
+		{prefix: "cb", expected: nil},
+		{prefix: "carts", expected: nil},
+	}
+
+	for _, tt := range tests {
+		if got := keys(sut, tt.prefix); !slices.Equal(got, tt.expected) {
+			t.Errorf("KeysWithPrefix(%q): expected %v, got %v", tt.prefix, tt.expected, got)
+		}
+	}
+
+	// Autocomplete takes a few first suggestions only.
+	var suggestions []string
+
+	for key, val := range sut.KeysWithPrefix("ca") {
+		if len(suggestions) == 2 {
+			break
+		}
+
+		suggestions = append(suggestions, key)
+
+		if stored, _ := sut.Search(key); stored != val {
+			t.Errorf("%s: expected value %d, got %d", key, stored, val)
+		}
+	}
+
+	if expected := []string{"ca", "car"}; !slices.Equal(suggestions, expected) {
+		t.Errorf("expected suggestions %v, got %v", expected, suggestions)
+	}
+}
+
+func TestKeysWithPrefixWhileModifying(t *testing.T) {
+	t.Parallel()
+
+	sut := build("a", "ab", "abc", "abd", "b", "ba")
+
+	// Deleting the yielded key with its subtree, adding keys before and after the current one.
+	var got []string
+
+	for key := range sut.All() {
+		got = append(got, key)
+
+		switch key {
+		case "ab":
+			sut.Delete("ab")
+			sut.Delete("abc")
+			sut.Insert("aa", 0)
+			sut.Insert("abcd", 0)
+		case "b":
+			sut.Delete("ba")
+			sut.Insert("bb", 0)
+		}
+	}
+
+	if expected := []string{"a", "ab", "abcd", "abd", "b", "bb"}; !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestLongestCommonPrefix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		keys     []string
+		expected string
+	}{
+		{name: "leetcode", keys: []string{"flower", "flow", "flight"}, expected: "fl"},
+		{name: "no common prefix", keys: []string{"dog", "racecar", "car"}, expected: ""},
+		{name: "empty trie", keys: nil, expected: ""},
+		{name: "single key", keys: []string{"alone"}, expected: "alone"},
+		{name: "key is a prefix of others", keys: []string{"inter", "interval", "internet"}, expected: "inter"},
+		{name: "split inside a character", keys: []string{"héllo", "hèllo"}, expected: "h"},
+		{name: "empty key", keys: []string{"", "abc"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := build(tt.keys...).LongestCommonPrefix(); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestLongestPrefixOf(t *testing.T) {
+	t.Parallel()
+
+	sut := trie.New[string]()
+	sut.Insert("/", "root")
+	sut.Insert("/api", "api")
+	sut.Insert("/api/users", "users")
+
+	tests := []struct {
+		s        string
+		expected string
+		val      string
+		found    bool
+	}{
+		{s: "/api/users/42", expected: "/api/users", val: "users", found: true},
+		{s: "/api/user", expected: "/api", val: "api", found: true},
+		{s: "/static", expected: "/", val: "root", found: true},
+		{s: "api", found: false},
+		{s: "", found: false},
+	}
+
+	for _, tt := range tests {
+		got, val, found := sut.LongestPrefixOf(tt.s)
+		if got != tt.expected || val != tt.val || found != tt.found {
+			t.Errorf("LongestPrefixOf(%q): expected %q, %q, %v, got %q, %q, %v",
+				tt.s, tt.expected, tt.val, tt.found, got, val, found)
+		}
+	}
+}
+
+func TestRandomAgainstMap(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(21))
+	randomKey := func() string {
+		var b strings.Builder
+		for range rnd.Intn(6) {
+			b.WriteByte("abc"[rnd.Intn(3)])
+		}
+
+		return b.String()
+	}
+
+	sut := trie.New[int]()
+	model := map[string]int{}
+
+	for i := 0; i < 20_000; i++ {
+		key := randomKey()
+
+		if rnd.Intn(3) == 0 {
+			_, found := model[key]
+			if deleted := sut.Delete(key); deleted != found {
+				t.Fatalf("step %d: Delete(%q) = %v, expected %v", i, key, deleted, found)
+			}
+
+			delete(model, key)
+		} else {
+			sut.Insert(key, i)
+			model[key] = i
+		}
+
+		if sut.Size() != uint(len(model)) {
+			t.Fatalf("step %d: expected size %d, got %d", i, len(model), sut.Size())
+		}
+
+		if i%500 != 0 {
+			continue
+		}
+
+		prefix := randomKey()
+
+		var expected []string
+
+		for _, k := range slices.Sorted(maps.Keys(model)) {
+			if strings.HasPrefix(k, prefix) {
+				expected = append(expected, k)
+			}
+		}
+
+		if got := keys(sut, prefix); !slices.Equal(got, expected) {
+			t.Fatalf("step %d: KeysWithPrefix(%q): expected %v, got %v", i, prefix, expected, got)
+		}
+
+		if got := sut.HasPrefix(prefix); got != (len(expected) > 0) {
+			t.Fatalf("step %d: HasPrefix(%q) = %v", i, prefix, got)
+		}
+	}
+}
+
+func BenchmarkKeysWithPrefix(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	sut := trie.New[int]()
+
+	words := make([]string, 100_000)
+	for i := range words {
+		var w strings.Builder
+		for range 3 + rnd.Intn(8) {
+			w.WriteByte(byte('a' + rnd.Intn(26)))
+		}
+
+		words[i] = w.String()
+		sut.Insert(words[i], i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	// Ten completions of a two-letter query, as a search box shows.
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for range sut.KeysWithPrefix(words[i%len(words)][:2]) {
+			if count++; count == 10 {
+				break
+			}
+		}
+	}
+}
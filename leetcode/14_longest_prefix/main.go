@@ -1,69 +1,8 @@
 package main
 
-import "fmt"
-
 func main() {}
 
-const empty = '\000'
-
-type (
-	node struct {
-		char byte
-		next *node
-	}
-
-	trie struct {
-		root *node
-	}
-)
-
-func newNode() *node {
-	return &node{char: empty}
-}
-
-func (t *trie) insert(word string) {
-	current := t.root
-
-	for _, c := range []byte(word) {
-
-		if current.next == nil {
-			current.next = newNode()
-		}
-
-		if current.next.char == empty {
-			current.next.char = c
-			return
-		}
-
-		if current.next.char != c {
-			current.next = nil
-			return
-		}
-
-		current = current.next
-	}
-}
-
-func (t *trie) traverse() string {
-	result := []byte{}
-
-	current := t.root.next
-
-	for current != nil {
-		result = append(result, current.char)
-		current = current.next
-	}
-
-	fmt.Printf("fot from traverse: %s\n", result)
-	return string(result)
-}
-
-func newTrie(initial string) *trie {
-	t := &trie{root: &node{}}
-	t.insert(initial)
-	return t
-}
-
+// longestCommonPrefix finds the longest common prefix among a slice of strings.
 func longestCommonPrefix(strs []string) string {
 	if len(strs) == 0 {
 		return ""
@@ -84,5 +23,3 @@ func longestCommonPrefix(strs []string) string {
 		}
 	}
 }
-
-// longestCommonPrefix finds the longest common prefix among a slice of strings.